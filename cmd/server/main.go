@@ -16,16 +16,25 @@
 // @in header
 // @name Authorization
 // @description JWT Bearer token. Format: Bearer {token}
+//
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Service API client key, for routes open to API clients
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"fmt"
+	"time"
 
 	"community-support-service/internal/config"
 	"community-support-service/internal/database"
 	"community-support-service/internal/handlers"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
 	"community-support-service/internal/repositories/postgres"
 	"community-support-service/internal/services"
 	"community-support-service/pkg/middleware"
@@ -59,13 +68,82 @@ func main() {
 	// Setup repositories and services
 	repo := postgres.NewRepository(db)
 	notificationService := services.NewNotificationService(cfg)
-	handlers.SetDependencies(repo, notificationService)
+	ticketService := services.NewTicketService(repo, notificationService, cfg.SLA)
+	revocationService := services.NewRevocationService(repo.Revocation, cfg.Auth.RevocationCacheTTL)
+	apiClientService := services.NewAPIClientService(repo.APIClient)
+	accessService := services.NewAccessService(repo.Role, cfg.Auth.RoleCacheTTL)
+	impersonationService := services.NewImpersonationService(repo.Impersonation, cfg.Auth.ImpersonationTTL)
+	archiveService := services.NewArchiveService(repo.Archive, cfg.Archive.AfterMonths)
+	handlers.SetDependencies(handlers.Dependencies{
+		Repository:    repo,
+		Notifications: notificationService,
+		Tickets:       ticketService,
+		Revocations:   revocationService,
+		APIClients:    apiClientService,
+		Access:        accessService,
+		Impersonation: impersonationService,
+		Archive:       archiveService,
+		Config:        cfg,
+	})
 
 	// Setup JWT middleware
-	jwtMiddleware := middleware.NewJWTMiddleware(cfg.Auth.JWTSecret)
+	var keySet *middleware.KeySet
+	if cfg.Auth.JWKSFile != "" || cfg.Auth.JWKSURL != "" {
+		keySet, err = middleware.NewKeySet(cfg.Auth.JWKSFile, cfg.Auth.JWKSURL, cfg.Auth.JWKSRefresh)
+		if err != nil {
+			log.Fatalf("Failed to load JWKS: %v", err)
+		}
+	}
+	jwtMiddleware := middleware.NewJWTMiddleware(cfg.Auth.JWTSecret, keySet, middleware.ClaimRules{
+		Issuer:      cfg.Auth.Issuer,
+		Audience:    cfg.Auth.Audience,
+		Leeway:      cfg.Auth.Leeway,
+		Revocations: revocationService,
+	})
+
+	// Service API clients may only call the routes listed here, and only
+	// ticket creation and comments may act on behalf of a student
+	apiKeyMiddleware := middleware.NewAPIKeyMiddleware(apiClientService, middleware.APIKeyRules{
+		RouteScopes: map[string]string{
+			"POST /api/v1/tickets":               models.ScopeTicketsCreate,
+			"GET /api/v1/tickets/{id}":           models.ScopeTicketsRead,
+			"GET /api/v1/tickets/{id}/comments":  models.ScopeTicketsRead,
+			"POST /api/v1/tickets/{id}/comments": models.ScopeCommentsWrite,
+		},
+		DelegableScopes:     []string{models.ScopeTicketsCreate, models.ScopeCommentsWrite},
+		DelegatedOnlyScopes: []string{models.ScopeTicketsCreate},
+	})
+
+	// Each ticket created sends several notifications, so ticket creation
+	// and comments get stricter limits than the rest of the API
+	var rateLimitRepo repositories.RateLimitRepository
+	if cfg.RateLimit.Shared {
+		rateLimitRepo = repo.RateLimit
+	}
+	perMinute := func(requests int) middleware.RateLimit {
+		return middleware.RateLimit{Requests: requests, Per: time.Minute}
+	}
+	perHour := func(requests int) middleware.RateLimit {
+		return middleware.RateLimit{Requests: requests, Per: time.Hour}
+	}
+	rateLimiter := middleware.NewRateLimiter(services.NewRateLimitService(rateLimitRepo), middleware.RateLimitRule{
+		User: perMinute(cfg.RateLimit.UserPerMinute),
+		IP:   perMinute(cfg.RateLimit.IPPerMinute),
+	}, map[string]middleware.RateLimitRule{
+		"POST /api/v1/tickets": {
+			User: perHour(cfg.RateLimit.TicketsPerUserPerHour),
+			IP:   perHour(cfg.RateLimit.TicketsPerIPPerHour),
+		},
+		"POST /api/v1/tickets/{id}/comments": {
+			User: perHour(cfg.RateLimit.CommentsPerUserPerHour),
+			IP:   perHour(cfg.RateLimit.CommentsPerIPPerHour),
+		},
+	}, cfg.RateLimit.TrustProxy)
 
 	// Setup router
 	router := mux.NewRouter()
+	router.Use(middleware.RequestID)
+	router.Use(middleware.StripIdentityHeaders)
 	
 	// Health check endpoint (no auth required)
 	router.HandleFunc("/health", handlers.HealthCheck).Methods("GET")
@@ -73,8 +151,12 @@ func main() {
 	// Swagger documentation endpoint
 	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
-	// API routes
+	// API routes. The per-IP limit comes before authentication so that it
+	// also covers public routes and requests with bad credentials.
 	api := router.PathPrefix("/api/v1").Subrouter()
+	if cfg.RateLimit.Enabled {
+		api.Use(rateLimiter.LimitIP)
+	}
 	
 	// Public routes (no authentication required)
 	public := api.PathPrefix("/public").Subrouter()
@@ -83,48 +165,151 @@ func main() {
 	// Protected routes (authentication required)
 	protected := api.PathPrefix("").Subrouter()
 	protected.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(jwtMiddleware.ValidateToken(next.ServeHTTP))
+		withToken := jwtMiddleware.ValidateToken(next.ServeHTTP)
+		withKey := apiKeyMiddleware.ValidateKey(next.ServeHTTP)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if middleware.HasAPIKey(r) {
+				withKey(w, r)
+				return
+			}
+			withToken(w, r)
+		})
 	})
+	if cfg.RateLimit.Enabled {
+		protected.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(rateLimiter.LimitUser(next.ServeHTTP))
+		})
+	}
+	protected.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(middleware.LoadGrants(accessService)(next.ServeHTTP))
+	})
+	// Admins viewing the API as a student through an impersonation session
+	impersonationMiddleware := middleware.NewImpersonationMiddleware(impersonationService, accessService, models.PermImpersonate)
+	protected.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(impersonationMiddleware.Impersonate(next.ServeHTTP))
+	})
+	// Ticket creation, comments and completion may be retried safely with an
+	// Idempotency-Key
+	idempotent := middleware.Idempotent(services.NewIdempotencyService(repo.Idempotency, cfg.Server.IdempotencyTTL))
+	// requires guards a route with a permission the user must hold in at
+	// least one of their grants
+	requires := func(permission string, handler http.HandlerFunc) http.HandlerFunc {
+		return middleware.RequirePermission(permission)(handler)
+	}
 	
 	// Student routes
+	createTicket := idempotent(handlers.CreateTicket)
 	protected.HandleFunc("/tickets", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "GET" {
 			handlers.GetStudentTickets(w, r)
 		} else if r.Method == "POST" {
-			handlers.CreateTicket(w, r)
+			createTicket(w, r)
 		}
 	}).Methods("GET", "POST")
+	protected.HandleFunc("/tickets/similar", handlers.FindSimilarTickets).Methods("POST")
 	protected.HandleFunc("/tickets/{id}", handlers.GetTicketByID).Methods("GET")
-	protected.HandleFunc("/tickets/{id}/complete", handlers.CompleteTicket).Methods("POST")
+	protected.HandleFunc("/tickets/{id}/complete", idempotent(handlers.CompleteTicket)).Methods("POST")
+	addTicketComment := idempotent(handlers.AddTicketComment)
 	protected.HandleFunc("/tickets/{id}/comments", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "GET" {
 			handlers.GetTicketComments(w, r)
 		} else if r.Method == "POST" {
-			handlers.AddTicketComment(w, r)
+			addTicketComment(w, r)
+		}
+	}).Methods("GET", "POST")
+	protected.HandleFunc("/tickets/{id}/watchers", handlers.GetTicketWatchers).Methods("GET")
+	protected.HandleFunc("/tickets/{id}/watchers", handlers.AddTicketWatcher).Methods("POST")
+	protected.HandleFunc("/tickets/{id}/watchers/{userId}", handlers.RemoveTicketWatcher).Methods("DELETE")
+	protected.HandleFunc("/tickets/{id}/watch", handlers.WatchTicket).Methods("POST")
+	protected.HandleFunc("/tickets/{id}/watch", handlers.UnwatchTicket).Methods("DELETE")
+	protected.HandleFunc("/tickets/{id}/relations", handlers.GetTicketRelations).Methods("GET")
+	protected.HandleFunc("/tags", handlers.GetTags).Methods("GET")
+	protected.HandleFunc("/archived-tickets", handlers.GetArchivedTickets).Methods("GET")
+	protected.HandleFunc("/archived-tickets/{id}", handlers.GetArchivedTicket).Methods("GET")
+
+	// Saved ticket views
+	protected.HandleFunc("/views", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			handlers.GetSavedViews(w, r)
+		} else if r.Method == "POST" {
+			handlers.CreateSavedView(w, r)
 		}
 	}).Methods("GET", "POST")
+	protected.HandleFunc("/views/{id}", handlers.UpdateSavedView).Methods("PUT")
+	protected.HandleFunc("/views/{id}", handlers.DeleteSavedView).Methods("DELETE")
 	
-	// Instructor/Admin routes
+	// Staff routes. Each route needs a permission; handlers additionally check
+	// that the user's grants cover the ticket at hand.
 	instructorRoutes := protected.PathPrefix("/instructor").Subrouter()
-	instructorRoutes.Use(func(next http.Handler) http.Handler {
-		return middleware.RequireRole("instructor", "admin")(next.ServeHTTP)
-	})
 	
-	instructorRoutes.HandleFunc("/tickets", handlers.GetInstructorTickets).Methods("GET")
-	instructorRoutes.HandleFunc("/tickets/{id}", handlers.UpdateTicket).Methods("PUT")
-	instructorRoutes.HandleFunc("/tickets/{id}/assign", handlers.AssignTicket).Methods("POST")
+	instructorRoutes.HandleFunc("/tickets", requires(models.PermTicketsRead, handlers.GetInstructorTickets)).Methods("GET")
+	instructorRoutes.HandleFunc("/tickets/{id}", requires(models.PermTicketsUpdate, handlers.UpdateTicket)).Methods("PUT")
+	instructorRoutes.HandleFunc("/tickets/{id}", requires(models.PermTicketsDelete, handlers.DeleteTicket)).Methods("DELETE")
+	instructorRoutes.HandleFunc("/tickets/{id}/comments/{commentId}", requires(models.PermTicketsDelete, handlers.DeleteTicketComment)).Methods("DELETE")
+	instructorRoutes.HandleFunc("/tickets/{id}/assign", requires(models.PermTicketsAssign, handlers.AssignTicket)).Methods("POST")
+	instructorRoutes.HandleFunc("/tickets/{id}/duplicates", requires(models.PermTicketsRead, handlers.GetTicketDuplicates)).Methods("GET")
+	instructorRoutes.HandleFunc("/tickets/{id}/merge", requires(models.PermTicketsUpdate, handlers.MergeTickets)).Methods("POST")
+	instructorRoutes.HandleFunc("/tickets/{id}/relations", requires(models.PermTicketsUpdate, handlers.CreateTicketRelation)).Methods("POST")
+	instructorRoutes.HandleFunc("/tickets/{id}/relations/{relationId}", requires(models.PermTicketsUpdate, handlers.DeleteTicketRelation)).Methods("DELETE")
+	instructorRoutes.HandleFunc("/tickets/{id}/incident", requires(models.PermTicketsUpdate, handlers.SetTicketIncident)).Methods("PUT")
+	instructorRoutes.HandleFunc("/tickets/{id}/broadcast", requires(models.PermTicketsUpdate, handlers.BroadcastIncidentUpdate)).Methods("POST")
+	instructorRoutes.HandleFunc("/tickets/{id}/macros/{macroId}", requires(models.PermMacrosUse, handlers.ApplyMacro)).Methods("POST")
+	instructorRoutes.HandleFunc("/tickets/{id}/tags", requires(models.PermTicketsUpdate, handlers.AddTicketTags)).Methods("POST")
+	instructorRoutes.HandleFunc("/tickets/{id}/tags/{tag}", requires(models.PermTicketsUpdate, handlers.RemoveTicketTag)).Methods("DELETE")
+	instructorRoutes.HandleFunc("/macros", requires(models.PermMacrosUse, handlers.GetMacros)).Methods("GET")
+	instructorRoutes.HandleFunc("/macros", requires(models.PermMacrosUse, handlers.CreateMacro)).Methods("POST")
+	instructorRoutes.HandleFunc("/macros/{id}", requires(models.PermMacrosUse, handlers.UpdateMacro)).Methods("PUT")
+	instructorRoutes.HandleFunc("/macros/{id}", requires(models.PermMacrosUse, handlers.DeleteMacro)).Methods("DELETE")
+	
+	// Admin routes
+	adminRoutes := protected.PathPrefix("/admin").Subrouter()
+	
+	adminRoutes.HandleFunc("/categories", requires(models.PermCatalogManage, handlers.GetAdminCategories)).Methods("GET")
+	adminRoutes.HandleFunc("/categories", requires(models.PermCatalogManage, handlers.CreateCategory)).Methods("POST")
+	adminRoutes.HandleFunc("/categories/{id}", requires(models.PermCatalogManage, handlers.UpdateCategory)).Methods("PUT")
+	adminRoutes.HandleFunc("/categories/{id}", requires(models.PermCatalogManage, handlers.DeleteCategory)).Methods("DELETE")
+	adminRoutes.HandleFunc("/tags", requires(models.PermCatalogManage, handlers.CreateTag)).Methods("POST")
+	adminRoutes.HandleFunc("/tags/{name}", requires(models.PermCatalogManage, handlers.UpdateTag)).Methods("PUT")
+	adminRoutes.HandleFunc("/tags/{name}", requires(models.PermCatalogManage, handlers.DeleteTag)).Methods("DELETE")
+	adminRoutes.HandleFunc("/reports/tags", requires(models.PermReportsRead, handlers.GetTagReport)).Methods("GET")
+	adminRoutes.HandleFunc("/form-fields", requires(models.PermCatalogManage, handlers.GetFormFields)).Methods("GET")
+	adminRoutes.HandleFunc("/form-fields", requires(models.PermCatalogManage, handlers.CreateFormField)).Methods("POST")
+	adminRoutes.HandleFunc("/form-fields/{id}", requires(models.PermCatalogManage, handlers.UpdateFormField)).Methods("PUT")
+	adminRoutes.HandleFunc("/form-fields/{id}", requires(models.PermCatalogManage, handlers.DeleteFormField)).Methods("DELETE")
+	adminRoutes.HandleFunc("/token-revocations", requires(models.PermAccessManage, handlers.RevokeToken)).Methods("POST")
+	adminRoutes.HandleFunc("/users/{userId}/revoke-tokens", requires(models.PermAccessManage, handlers.RevokeUserTokens)).Methods("POST")
+	adminRoutes.HandleFunc("/api-clients", requires(models.PermAccessManage, handlers.GetAPIClients)).Methods("GET")
+	adminRoutes.HandleFunc("/api-clients", requires(models.PermAccessManage, handlers.CreateAPIClient)).Methods("POST")
+	adminRoutes.HandleFunc("/api-clients/{id}", requires(models.PermAccessManage, handlers.UpdateAPIClient)).Methods("PUT")
+	adminRoutes.HandleFunc("/api-clients/{id}/rotate-key", requires(models.PermAccessManage, handlers.RotateAPIClientKey)).Methods("POST")
+	adminRoutes.HandleFunc("/api-clients/{id}/usage", requires(models.PermAccessManage, handlers.GetAPIClientUsage)).Methods("GET")
+	adminRoutes.HandleFunc("/roles", requires(models.PermAccessManage, handlers.GetRoles)).Methods("GET")
+	adminRoutes.HandleFunc("/roles", requires(models.PermAccessManage, handlers.CreateRole)).Methods("POST")
+	adminRoutes.HandleFunc("/roles/{name}", requires(models.PermAccessManage, handlers.UpdateRole)).Methods("PUT")
+	adminRoutes.HandleFunc("/roles/{name}", requires(models.PermAccessManage, handlers.DeleteRole)).Methods("DELETE")
+	adminRoutes.HandleFunc("/role-assignments", requires(models.PermAccessManage, handlers.GetRoleAssignments)).Methods("GET")
+	adminRoutes.HandleFunc("/role-assignments", requires(models.PermAccessManage, handlers.CreateRoleAssignment)).Methods("POST")
+	adminRoutes.HandleFunc("/role-assignments/{id}", requires(models.PermAccessManage, handlers.DeleteRoleAssignment)).Methods("DELETE")
+	adminRoutes.HandleFunc("/impersonations", requires(models.PermImpersonate, handlers.GetImpersonations)).Methods("GET")
+	adminRoutes.HandleFunc("/impersonations", requires(models.PermImpersonate, handlers.StartImpersonation)).Methods("POST")
+	adminRoutes.HandleFunc("/impersonations/{id}", requires(models.PermImpersonate, handlers.EndImpersonation)).Methods("DELETE")
+	adminRoutes.HandleFunc("/impersonations/{id}/requests", requires(models.PermImpersonate, handlers.GetImpersonationRequests)).Methods("GET")
+	adminRoutes.HandleFunc("/deleted-tickets", requires(models.PermTicketsRestore, handlers.GetDeletedTickets)).Methods("GET")
+	adminRoutes.HandleFunc("/tickets/{id}/restore", requires(models.PermTicketsRestore, handlers.RestoreTicket)).Methods("POST")
+	adminRoutes.HandleFunc("/tickets/{id}/deleted-comments", requires(models.PermTicketsRestore, handlers.GetDeletedComments)).Methods("GET")
+	adminRoutes.HandleFunc("/comments/{id}/restore", requires(models.PermTicketsRestore, handlers.RestoreComment)).Methods("POST")
 	
 	// Slack integration endpoints
 	slackRoutes := protected.PathPrefix("/slack").Subrouter()
-	slackRoutes.Use(func(next http.Handler) http.Handler {
-		return middleware.RequireRole("admin", "instructor")(next.ServeHTTP)
-	})
 	
-	slackRoutes.HandleFunc("/reply", handlers.SlackReply).Methods("POST")
+	slackRoutes.HandleFunc("/reply", requires(models.PermSlackReply, handlers.SlackReply)).Methods("POST")
 	
 	// Slack webhook (no auth required - Slack will verify)
 	api.HandleFunc("/slack/webhook", handlers.SlackWebhook).Methods("POST")
 
+	// Move old closed tickets to the archive in the background
+	go archiveService.Run(context.Background(), cfg.Archive.Interval)
+
 	// Start server
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	log.Printf("Server starting on %s", addr)
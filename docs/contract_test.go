@@ -0,0 +1,323 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"community-support-service/internal/models"
+	"community-support-service/pkg/utils"
+)
+
+// The contract suite checks the API's JSON against docs/swagger.json: every
+// documented model must have exactly the documented properties, response
+// envelopes may only carry documented properties of the documented types, and
+// every model field is spelled in camelCase. Regenerate the spec with
+// `swag init -g cmd/server/main.go -o docs` after changing a model.
+
+type schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Properties map[string]*schema `json:"properties"`
+	Items      *schema            `json:"items"`
+	AllOf      []*schema          `json:"allOf"`
+}
+
+type spec struct {
+	Definitions map[string]*schema `json:"definitions"`
+}
+
+func loadSpec(t *testing.T) *spec {
+	t.Helper()
+
+	data, err := os.ReadFile("swagger.json")
+	if err != nil {
+		t.Fatalf("failed to read swagger.json: %v", err)
+	}
+	var s spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatalf("failed to parse swagger.json: %v", err)
+	}
+	return &s
+}
+
+// resolve follows $ref and single-element allOf wrappers to the schema they
+// point at.
+func (s *spec) resolve(t *testing.T, sch *schema) *schema {
+	t.Helper()
+
+	for sch != nil {
+		switch {
+		case sch.Ref != "":
+			name := strings.TrimPrefix(sch.Ref, "#/definitions/")
+			next, ok := s.Definitions[name]
+			if !ok {
+				t.Fatalf("swagger.json references undefined %s", name)
+			}
+			sch = next
+		case len(sch.AllOf) == 1:
+			sch = sch.AllOf[0]
+		default:
+			return sch
+		}
+	}
+	return nil
+}
+
+// documentedModels maps each model definition in swagger.json to the Go type
+// it was generated from.
+var documentedModels = map[string]interface{}{
+	"models.AddWatcherRequest":           models.AddWatcherRequest{},
+	"models.AssignTicketRequest":         models.AssignTicketRequest{},
+	"models.BroadcastRequest":            models.BroadcastRequest{},
+	"models.CreateAPIClientRequest":      models.CreateAPIClientRequest{},
+	"models.CreateCategoryRequest":       models.CreateCategoryRequest{},
+	"models.CreateCommentRequest":        models.CreateCommentRequest{},
+	"models.CreateFormFieldRequest":      models.CreateFormFieldRequest{},
+	"models.CreateMacroRequest":          models.CreateMacroRequest{},
+	"models.CreateRelationRequest":       models.CreateRelationRequest{},
+	"models.CreateRoleAssignmentRequest": models.CreateRoleAssignmentRequest{},
+	"models.CreateRoleRequest":           models.CreateRoleRequest{},
+	"models.CreateSavedViewRequest":      models.CreateSavedViewRequest{},
+	"models.CreateTagRequest":            models.CreateTagRequest{},
+	"models.CreateTicketRequest":         models.CreateTicketRequest{},
+	"models.MacroActions":                models.MacroActions{},
+	"models.MergeTicketsRequest":         models.MergeTicketsRequest{},
+	"models.RevokeTokenRequest":          models.RevokeTokenRequest{},
+	"models.RevokeUserTokensRequest":     models.RevokeUserTokensRequest{},
+	"models.SetIncidentRequest":          models.SetIncidentRequest{},
+	"models.SimilarTicketsRequest":       models.SimilarTicketsRequest{},
+	"models.StartImpersonationRequest":   models.StartImpersonationRequest{},
+	"models.TicketTagsRequest":           models.TicketTagsRequest{},
+	"models.UpdateAPIClientRequest":      models.UpdateAPIClientRequest{},
+	"models.UpdateCategoryRequest":       models.UpdateCategoryRequest{},
+	"models.UpdateFormFieldRequest":      models.UpdateFormFieldRequest{},
+	"models.UpdateMacroRequest":          models.UpdateMacroRequest{},
+	"models.UpdateRoleRequest":           models.UpdateRoleRequest{},
+	"models.UpdateSavedViewRequest":      models.UpdateSavedViewRequest{},
+	"models.UpdateTagRequest":            models.UpdateTagRequest{},
+	"models.UpdateTicketRequest":         models.UpdateTicketRequest{},
+	"utils.APIError":                     utils.APIError{},
+	"utils.APIResponse":                  utils.APIResponse{},
+	"utils.FieldError":                   utils.FieldError{},
+	"utils.PaginatedResponse":            utils.PaginatedResponse{},
+	"utils.Pagination":                   utils.Pagination{},
+}
+
+// responseModels are returned in the data of responses; the spec describes
+// them only as objects, so they are checked for camelCase names alone.
+var responseModels = []interface{}{
+	models.APIClient{}, models.APIClientUsage{}, models.ArchivedTicket{}, models.Attachment{},
+	models.Category{}, models.FormField{}, models.ImpersonationSession{}, models.ImpersonatedRequest{},
+	models.Macro{}, models.TicketRelation{}, models.RevokedToken{}, models.UserTokenRevocation{},
+	models.Role{}, models.RoleAssignment{}, models.SavedView{}, models.Tag{}, models.TagCount{},
+	models.Ticket{}, models.TicketComment{}, models.SimilarTicket{}, models.TicketHistory{},
+	models.TicketWatcher{}, models.CreateAttachmentRequest{},
+}
+
+// jsonNames returns the JSON names of t's fields, flattening embedded
+// structs the way encoding/json does.
+func jsonNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				names = append(names, jsonNames(embedded)...)
+				continue
+			}
+		}
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestDocumentedModelsMatchSpec(t *testing.T) {
+	s := loadSpec(t)
+
+	for name, definition := range s.Definitions {
+		if definition.Type != "object" || definition.Properties == nil {
+			continue
+		}
+		value, ok := documentedModels[name]
+		if !ok {
+			t.Errorf("%s is documented but not covered by the contract suite", name)
+			continue
+		}
+
+		var documented []string
+		for property := range definition.Properties {
+			documented = append(documented, property)
+		}
+		sort.Strings(documented)
+
+		actual := jsonNames(reflect.TypeOf(value))
+		if !reflect.DeepEqual(actual, documented) {
+			t.Errorf("%s serializes %v, swagger.json documents %v", name, actual, documented)
+		}
+	}
+
+	for name := range documentedModels {
+		if _, ok := s.Definitions[name]; !ok {
+			t.Errorf("%s is missing from swagger.json", name)
+		}
+	}
+}
+
+var camelCase = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+func TestModelFieldsAreCamelCase(t *testing.T) {
+	values := append([]interface{}{}, responseModels...)
+	for _, value := range documentedModels {
+		values = append(values, value)
+	}
+
+	for _, value := range values {
+		modelType := reflect.TypeOf(value)
+		for _, name := range jsonNames(modelType) {
+			if !camelCase.MatchString(name) {
+				t.Errorf("%s field %q is not camelCase", modelType, name)
+			}
+		}
+	}
+}
+
+// checkBody fails when body has a property the schema does not document or a
+// value of another type than documented. Untyped values and free-form
+// objects are not checked.
+func checkBody(t *testing.T, s *spec, sch *schema, body interface{}, path string) {
+	t.Helper()
+
+	sch = s.resolve(t, sch)
+	if sch == nil || body == nil || (sch.Type == "" && sch.Properties == nil) {
+		return
+	}
+
+	switch value := body.(type) {
+	case map[string]interface{}:
+		if sch.Type != "object" {
+			t.Errorf("%s is an object, swagger.json documents %s", path, sch.Type)
+			return
+		}
+		if sch.Properties == nil {
+			return
+		}
+		for key, property := range value {
+			propertySchema, ok := sch.Properties[key]
+			if !ok {
+				t.Errorf("%s.%s is not documented in swagger.json", path, key)
+				continue
+			}
+			checkBody(t, s, propertySchema, property, path+"."+key)
+		}
+	case []interface{}:
+		if sch.Type != "array" {
+			t.Errorf("%s is an array, swagger.json documents %s", path, sch.Type)
+			return
+		}
+		for _, item := range value {
+			checkBody(t, s, sch.Items, item, path+"[]")
+		}
+	case string:
+		if sch.Type != "string" {
+			t.Errorf("%s is a string, swagger.json documents %s", path, sch.Type)
+		}
+	case bool:
+		if sch.Type != "boolean" {
+			t.Errorf("%s is a boolean, swagger.json documents %s", path, sch.Type)
+		}
+	case float64:
+		if sch.Type != "integer" && sch.Type != "number" {
+			t.Errorf("%s is a number, swagger.json documents %s", path, sch.Type)
+		}
+	}
+}
+
+func TestResponsesMatchSpec(t *testing.T) {
+	s := loadSpec(t)
+	total := int64(42)
+
+	tests := []struct {
+		name       string
+		definition string
+		status     int
+		write      func(w http.ResponseWriter)
+	}{
+		{
+			name:       "success",
+			definition: "utils.APIResponse",
+			status:     http.StatusOK,
+			write: func(w http.ResponseWriter) {
+				utils.WriteSuccess(w, "Ticket updated successfully", map[string]interface{}{"ticket": models.Ticket{}})
+			},
+		},
+		{
+			name:       "created",
+			definition: "utils.APIResponse",
+			status:     http.StatusCreated,
+			write: func(w http.ResponseWriter) {
+				utils.WriteCreated(w, "Comment added successfully", map[string]interface{}{"comment": models.TicketComment{}})
+			},
+		},
+		{
+			name:       "validation error",
+			definition: "utils.APIResponse",
+			status:     http.StatusBadRequest,
+			write: func(w http.ResponseWriter) {
+				utils.WriteError(w, http.StatusBadRequest, utils.CodeValidationFailed, "Validation failed",
+					utils.FieldError{Field: "title", Message: "is required"})
+			},
+		},
+		{
+			name:       "paginated",
+			definition: "utils.PaginatedResponse",
+			status:     http.StatusOK,
+			write: func(w http.ResponseWriter) {
+				utils.WritePaginated(w, []models.Ticket{}, utils.Pagination{Limit: 20, Total: &total, NextCursor: "next", PrevCursor: "prev"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			recorder.Header().Set(utils.RequestIDHeader, "request-1")
+			tt.write(recorder)
+
+			if recorder.Code != tt.status {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.status)
+			}
+			if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", contentType)
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("response is not a JSON object: %v", err)
+			}
+			checkBody(t, s, &schema{Ref: "#/definitions/" + tt.definition}, body, tt.definition)
+			if body["requestId"] != "request-1" {
+				t.Errorf("requestId = %v, want request-1", body["requestId"])
+			}
+		})
+	}
+}
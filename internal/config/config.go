@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -14,12 +15,22 @@ type Config struct {
 	Upload        UploadConfig
 	Notifications NotificationConfig
 	Frontend      FrontendConfig
+	SLA           SLAConfig
+	RateLimit     RateLimitConfig
+	Archive       ArchiveConfig
 }
 
 type ServerConfig struct {
 	Host string
 	Port int
 	Env  string
+	// JSONCompatMode accepts request field names that only match the
+	// camelCase contract case-insensitively (e.g. "TicketNumber"), flagging
+	// them with a Warning header instead of rejecting the request.
+	JSONCompatMode bool
+	// IdempotencyTTL is how long responses to requests with an
+	// Idempotency-Key are kept for replay.
+	IdempotencyTTL time.Duration
 }
 
 type DatabaseConfig struct {
@@ -32,8 +43,61 @@ type DatabaseConfig struct {
 }
 
 
+// DefaultJWTSecret is the placeholder HMAC secret. It is only accepted in
+// development.
+const DefaultJWTSecret = "your-secret-key"
+
 type AuthConfig struct {
+	// JWTSecret verifies HMAC-signed tokens. Leave it empty to only accept
+	// tokens signed with a JWKS key.
 	JWTSecret string
+	// JWKSFile or JWKSURL locate the JWKS document whose public keys verify
+	// RS256, ES256 and EdDSA tokens. The file takes precedence.
+	JWKSFile    string
+	JWKSURL     string
+	JWKSRefresh time.Duration
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew allowed when checking exp, nbf and iat.
+	Leeway time.Duration
+	// RevocationCacheTTL is how long the token revocation list is cached
+	// before it is reloaded from the database.
+	RevocationCacheTTL time.Duration
+	// RoleCacheTTL is how long roles and role assignments are cached before
+	// they are reloaded from the database.
+	RoleCacheTTL time.Duration
+	// ImpersonationTTL is how long an admin's read-only impersonation
+	// session lasts.
+	ImpersonationTTL time.Duration
+}
+
+// RateLimitConfig sets the request limits per user and per client IP. Ticket
+// creation and comments have their own, stricter limits; every other route
+// shares the default ones. A limit of 0 turns it off.
+type RateLimitConfig struct {
+	Enabled bool
+	// Shared keeps the buckets in Postgres so that every instance counts
+	// the same requests, instead of in each instance's memory.
+	Shared bool
+	// TrustProxy takes the client IP from X-Forwarded-For. Only enable it
+	// behind a proxy that sets the header.
+	TrustProxy bool
+
+	UserPerMinute          int
+	IPPerMinute            int
+	TicketsPerUserPerHour  int
+	TicketsPerIPPerHour    int
+	CommentsPerUserPerHour int
+	CommentsPerIPPerHour   int
+}
+
+// ArchiveConfig sets when closed tickets are moved to the archive. Tickets
+// closed for AfterMonths are archived by a job running every Interval; an
+// AfterMonths of 0 turns archiving off.
+type ArchiveConfig struct {
+	AfterMonths int
+	Interval    time.Duration
 }
 
 type UploadConfig struct {
@@ -56,12 +120,37 @@ type FrontendConfig struct {
 	BaseURL string
 }
 
+// SLAConfig holds the first-response targets per ticket priority.
+type SLAConfig struct {
+	UrgentHours int
+	HighHours   int
+	MediumHours int
+	LowHours    int
+}
+
+// DueAt returns the SLA deadline for a ticket of the given priority opened at
+// createdAt. Unknown priorities use the low-priority target.
+func (c SLAConfig) DueAt(priority string, createdAt time.Time) time.Time {
+	hours := c.LowHours
+	switch priority {
+	case "urgent":
+		hours = c.UrgentHours
+	case "high":
+		hours = c.HighHours
+	case "medium":
+		hours = c.MediumHours
+	}
+	return createdAt.Add(time.Duration(hours) * time.Hour)
+}
+
 func Load() (*Config, error) {
 	config := &Config{
 		Server: ServerConfig{
-			Host: getEnv("SERVER_HOST", "localhost"),
-			Port: getEnvAsInt("SERVER_PORT", 8080),
-			Env:  getEnv("SERVER_ENV", "development"),
+			Host:           getEnv("SERVER_HOST", "localhost"),
+			Port:           getEnvAsInt("SERVER_PORT", 8080),
+			Env:            getEnv("SERVER_ENV", "production"),
+			JSONCompatMode: getEnvAsBool("JSON_COMPAT_MODE", true),
+			IdempotencyTTL: time.Duration(getEnvAsInt("IDEMPOTENCY_TTL_HOURS", 24)) * time.Hour,
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -72,7 +161,17 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
 		},
 		Auth: AuthConfig{
-			JWTSecret: getEnv("JWT_SECRET", "your-secret-key"),
+			JWTSecret:   getEnv("JWT_SECRET", ""),
+			JWKSFile:    getEnv("JWT_JWKS_FILE", ""),
+			JWKSURL:     getEnv("JWT_JWKS_URL", ""),
+			JWKSRefresh: time.Duration(getEnvAsInt("JWT_JWKS_REFRESH_MINUTES", 15)) * time.Minute,
+			Issuer:      getEnv("JWT_ISSUER", ""),
+			Audience:    getEnv("JWT_AUDIENCE", ""),
+			Leeway:      time.Duration(getEnvAsInt("JWT_LEEWAY_SECONDS", 30)) * time.Second,
+
+			RevocationCacheTTL: time.Duration(getEnvAsInt("JWT_REVOCATION_CACHE_SECONDS", 30)) * time.Second,
+			RoleCacheTTL:       time.Duration(getEnvAsInt("ROLE_CACHE_SECONDS", 30)) * time.Second,
+			ImpersonationTTL:   time.Duration(getEnvAsInt("IMPERSONATION_MINUTES", 15)) * time.Minute,
 		},
 		Notifications: NotificationConfig{
 			EmailServiceURL:   getEnv("EMAIL_SERVICE_URL", "http://localhost:8081"),
@@ -86,6 +185,28 @@ func Load() (*Config, error) {
 		Frontend: FrontendConfig{
 			BaseURL: getEnv("FRONTEND_BASE_URL", "https://kemuko.com"),
 		},
+		SLA: SLAConfig{
+			UrgentHours: getEnvAsInt("SLA_URGENT_HOURS", 4),
+			HighHours:   getEnvAsInt("SLA_HIGH_HOURS", 24),
+			MediumHours: getEnvAsInt("SLA_MEDIUM_HOURS", 72),
+			LowHours:    getEnvAsInt("SLA_LOW_HOURS", 120),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:    getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			Shared:     getEnv("RATE_LIMIT_STORE", "memory") == "postgres",
+			TrustProxy: getEnvAsBool("RATE_LIMIT_TRUST_PROXY", false),
+
+			UserPerMinute:          getEnvAsInt("RATE_LIMIT_USER_PER_MINUTE", 120),
+			IPPerMinute:            getEnvAsInt("RATE_LIMIT_IP_PER_MINUTE", 600),
+			TicketsPerUserPerHour:  getEnvAsInt("RATE_LIMIT_TICKETS_PER_USER_PER_HOUR", 10),
+			TicketsPerIPPerHour:    getEnvAsInt("RATE_LIMIT_TICKETS_PER_IP_PER_HOUR", 60),
+			CommentsPerUserPerHour: getEnvAsInt("RATE_LIMIT_COMMENTS_PER_USER_PER_HOUR", 60),
+			CommentsPerIPPerHour:   getEnvAsInt("RATE_LIMIT_COMMENTS_PER_IP_PER_HOUR", 300),
+		},
+		Archive: ArchiveConfig{
+			AfterMonths: getEnvAsInt("ARCHIVE_AFTER_MONTHS", 12),
+			Interval:    time.Duration(getEnvAsInt("ARCHIVE_INTERVAL_HOURS", 24)) * time.Hour,
+		},
 		Upload: UploadConfig{
 			MaxFileSize: getEnvAsInt64("MAX_FILE_SIZE", 10*1024*1024), // 10MB
 			UploadDir:   getEnv("UPLOAD_DIR", "./uploads"),
@@ -97,9 +218,29 @@ func Load() (*Config, error) {
 		},
 	}
 
+	if err := config.Auth.validate(config.Server.Env); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
+// validate checks that tokens can be verified at all, falling back to the
+// default secret in development, and refuses the default secret elsewhere.
+func (c *AuthConfig) validate(env string) error {
+	hasJWKS := c.JWKSFile != "" || c.JWKSURL != ""
+	if c.JWTSecret == "" && !hasJWKS {
+		if env != "development" {
+			return fmt.Errorf("JWT_SECRET or JWT_JWKS_FILE/JWT_JWKS_URL must be set outside development")
+		}
+		c.JWTSecret = DefaultJWTSecret
+	}
+	if c.JWTSecret == DefaultJWTSecret && env != "development" {
+		return fmt.Errorf("JWT_SECRET must not be the default secret outside development")
+	}
+	return nil
+}
+
 func (c *Config) DatabaseURL() string {
 	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
 		c.Database.User,
@@ -136,6 +277,15 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsStringSlice(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		// Split by comma and trim spaces
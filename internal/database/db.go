@@ -34,8 +34,9 @@ func (db *DB) Close() error {
 	return db.DB.Close()
 }
 
-// Transaction helper
-func (db *DB) WithTx(fn func(*sqlx.Tx) error) error {
+// Transaction helper. The transaction is committed if fn succeeds and
+// rolled back otherwise; commit errors are returned to the caller.
+func (db *DB) WithTx(fn func(*sqlx.Tx) error) (err error) {
 	tx, err := db.Beginx()
 	if err != nil {
 		return err
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"community-support-service/internal/models"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetAPIClients godoc
+// @Summary List API clients
+// @Description Retrieve all service API clients with their scopes. Keys are never returned.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/api-clients [get]
+func GetAPIClients(w http.ResponseWriter, r *http.Request) {
+	clients, err := repo.APIClient.GetAll(r.Context())
+	if err != nil {
+		writeError(w, err, "Failed to fetch API clients")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"apiClients": clients,
+		"total":      len(clients),
+	})
+}
+
+// CreateAPIClient godoc
+// @Summary Create API client
+// @Description Register a service API client with scopes. The response holds the key; it cannot be retrieved again.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param client body models.CreateAPIClientRequest true "API client"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/api-clients [post]
+func CreateAPIClient(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateAPIClientRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	client, key, err := apiClientService.Create(r.Context(), &req, requestActor(r).UserID)
+	if err != nil {
+		writeError(w, err, "Failed to create API client")
+		return
+	}
+
+	utils.WriteCreated(w, "API client created successfully", map[string]interface{}{
+		"apiClient": client,
+		"apiKey":    key,
+	})
+}
+
+// UpdateAPIClient godoc
+// @Summary Update API client
+// @Description Change an API client's name, description or scopes, or deactivate it
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "API client ID"
+// @Param client body models.UpdateAPIClientRequest true "Changes"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/api-clients/{id} [put]
+func UpdateAPIClient(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := apiClientID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAPIClientRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	client, err := apiClientService.Update(r.Context(), clientID, &req)
+	if err != nil {
+		writeError(w, err, "Failed to update API client")
+		return
+	}
+
+	utils.WriteSuccess(w, "API client updated successfully", map[string]interface{}{
+		"apiClient": client,
+	})
+}
+
+// RotateAPIClientKey godoc
+// @Summary Rotate API client key
+// @Description Issue a new key for an API client. The old key stops working immediately.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "API client ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/api-clients/{id}/rotate-key [post]
+func RotateAPIClientKey(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := apiClientID(w, r)
+	if !ok {
+		return
+	}
+
+	client, key, err := apiClientService.RotateKey(r.Context(), clientID)
+	if err != nil {
+		writeError(w, err, "Failed to rotate API key")
+		return
+	}
+
+	utils.WriteSuccess(w, "API key rotated successfully", map[string]interface{}{
+		"apiClient": client,
+		"apiKey":    key,
+	})
+}
+
+// GetAPIClientUsage godoc
+// @Summary API client usage
+// @Description Retrieve the most recent requests made with an API client's key, newest first
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "API client ID"
+// @Param limit query int false "Number of entries (default 100, max 1000)"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/api-clients/{id}/usage [get]
+func GetAPIClientUsage(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := apiClientID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := 100
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	if _, err := repo.APIClient.GetByID(r.Context(), clientID); err != nil {
+		writeError(w, err, "Failed to fetch API client")
+		return
+	}
+	usage, err := repo.APIClient.GetUsage(r.Context(), clientID, limit)
+	if err != nil {
+		writeError(w, err, "Failed to fetch API client usage")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"usage": usage,
+		"total": len(usage),
+	})
+}
+
+func apiClientID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	clientID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid API client ID")
+		return uuid.Nil, false
+	}
+	return clientID, true
+}
\ No newline at end of file
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"community-support-service/internal/repositories"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetArchivedTickets godoc
+// @Summary List archived tickets
+// @Description Retrieve archived tickets, most recently closed first. Students see their own; staff see the ones their grants cover.
+// @Tags archive
+// @Security BearerAuth
+// @Produce json
+// @Param studentId query string false "Only tickets opened by this student"
+// @Param courseId query string false "Only tickets of this course"
+// @Param ticketNumber query string false "Only the ticket with this number"
+// @Param limit query int false "Number of tickets (default 20, max 100)"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Router /archived-tickets [get]
+func GetArchivedTickets(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	var filters repositories.ArchiveFilters
+	if value := params.Get("studentId"); value != "" {
+		filters.StudentID = &value
+	}
+	if value := params.Get("courseId"); value != "" {
+		filters.CourseID = &value
+	}
+	if value := params.Get("ticketNumber"); value != "" {
+		filters.TicketNumber = &value
+	}
+	if value := params.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid limit")
+			return
+		}
+		filters.Limit = parsed
+	}
+
+	tickets, err := archiveService.List(actorContext(r), filters)
+	if err != nil {
+		writeError(w, err, "Failed to fetch archived tickets")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"tickets": tickets,
+		"total":   len(tickets),
+	})
+}
+
+// GetArchivedTicket godoc
+// @Summary Get archived ticket
+// @Description Retrieve an archived ticket with its comments and attachments as they were when it was archived. Internal comments and the ticket's history are only included for users with the tickets:internal permission on the ticket.
+// @Tags archive
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /archived-tickets/{id} [get]
+func GetArchivedTicket(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	archived, err := archiveService.Get(actorContext(r), ticketID)
+	if err != nil {
+		writeError(w, err, "Failed to fetch archived ticket")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"archivedTicket": archived,
+	})
+}
\ No newline at end of file
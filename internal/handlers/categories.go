@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const categoryCacheTTL = 5 * time.Minute
+
+// categoryTree is the public view of the category catalog: active categories
+// nested under their parents with their form fields, plus the form fields
+// defined per ticket type.
+type categoryTree struct {
+	Categories []*models.Category
+	TypeFields map[models.TicketType][]*models.FormField
+}
+
+// categoryTreeCache holds the category tree served by the public endpoint.
+// Admin changes invalidate it immediately; the TTL bounds how long other
+// server instances may serve a stale tree.
+type categoryTreeCache struct {
+	mu        sync.RWMutex
+	tree      *categoryTree
+	expiresAt time.Time
+}
+
+var categoryCache = &categoryTreeCache{}
+
+func (c *categoryTreeCache) get(ctx context.Context) (*categoryTree, error) {
+	c.mu.RLock()
+	if c.tree != nil && time.Now().Before(c.expiresAt) {
+		tree := c.tree
+		c.mu.RUnlock()
+		return tree, nil
+	}
+	c.mu.RUnlock()
+
+	categories, err := repo.Category.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := repo.FormField.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	tree := buildCategoryTree(categories, fields)
+
+	c.mu.Lock()
+	c.tree = tree
+	c.expiresAt = time.Now().Add(categoryCacheTTL)
+	c.mu.Unlock()
+	return tree, nil
+}
+
+func (c *categoryTreeCache) invalidate() {
+	c.mu.Lock()
+	c.tree = nil
+	c.mu.Unlock()
+}
+
+// GetAdminCategories godoc
+// @Summary List all categories
+// @Description Retrieve every category, including inactive ones, as a flat list
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/categories [get]
+func GetAdminCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := repo.Category.GetAll(r.Context(), false)
+	if err != nil {
+		writeError(w, err, "Failed to fetch categories")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"categories": categories,
+		"total":      len(categories),
+	})
+}
+
+// CreateCategory godoc
+// @Summary Create category
+// @Description Create a support category, optionally under a parent category
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param category body models.CreateCategoryRequest true "Category"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /admin/categories [post]
+func CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCategoryRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Category name is required and must be at most 100 characters")
+		return
+	}
+	if req.Color != nil && !colorPattern.MatchString(*req.Color) {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Color must be a hex color such as #1E88E5")
+		return
+	}
+	if req.Type == "" {
+		req.Type = models.TicketTypeGeneral
+	}
+	if req.ParentID != nil {
+		parent, err := repo.Category.GetByID(r.Context(), *req.ParentID)
+		if errors.Is(err, repositories.ErrNotFound) || (err == nil && !parent.IsActive) {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Unknown or inactive parent category")
+			return
+		}
+		if err != nil {
+			writeError(w, err, "Failed to fetch parent category")
+			return
+		}
+	}
+
+	now := time.Now()
+	category := &models.Category{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+		Type:        req.Type,
+		ParentID:    req.ParentID,
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := repo.Category.Create(r.Context(), category); err != nil {
+		writeError(w, err, "Failed to create category")
+		return
+	}
+	categoryCache.invalidate()
+
+	utils.WriteCreated(w, "Category created successfully", map[string]interface{}{
+		"category": category,
+	})
+}
+
+// UpdateCategory godoc
+// @Summary Update category
+// @Description Change a category or move it in the hierarchy. Deactivating a category used by open tickets requires reassignTo, the category those tickets move to.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID" Format(uuid)
+// @Param category body models.UpdateCategoryRequest true "Category changes"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /admin/categories/{id} [put]
+func UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	category, ok := loadCategory(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCategoryRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > 100 {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Category name is required and must be at most 100 characters")
+			return
+		}
+		category.Name = name
+	}
+	if req.Description != nil {
+		category.Description = req.Description
+	}
+	if req.Color != nil {
+		if !colorPattern.MatchString(*req.Color) {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Color must be a hex color such as #1E88E5")
+			return
+		}
+		category.Color = req.Color
+	}
+	if req.Type != nil {
+		category.Type = *req.Type
+	}
+	if req.TopLevel {
+		category.ParentID = nil
+	} else if req.ParentID != nil {
+		if err := checkCategoryParent(r.Context(), category.ID, *req.ParentID); err != nil {
+			writeError(w, err, "Failed to fetch categories")
+			return
+		}
+		category.ParentID = req.ParentID
+	}
+
+	deactivate := req.IsActive != nil && !*req.IsActive && category.IsActive
+	if req.IsActive != nil && *req.IsActive {
+		category.IsActive = true
+	}
+	if deactivate {
+		if err := checkReassignTarget(r.Context(), category.ID, req.ReassignTo); err != nil {
+			writeError(w, err, "Failed to fetch category")
+			return
+		}
+	}
+
+	// Deactivate first so that a blocked deactivation leaves the category
+	// untouched
+	category.UpdatedAt = time.Now()
+	var reassigned int64
+	if deactivate {
+		moved, err := repo.Category.Deactivate(r.Context(), category.ID, req.ReassignTo, false, requestActor(r).UserID, category.UpdatedAt)
+		if err != nil {
+			writeError(w, err, "Failed to update category")
+			return
+		}
+		reassigned = moved
+		category.IsActive = false
+	}
+
+	if err := repo.Category.Update(r.Context(), category); err != nil {
+		writeError(w, err, "Failed to update category")
+		return
+	}
+	categoryCache.invalidate()
+
+	utils.WriteSuccess(w, "Category updated successfully", map[string]interface{}{
+		"category":          category,
+		"reassignedTickets": reassigned,
+	})
+}
+
+// DeleteCategory godoc
+// @Summary Delete category
+// @Description Delete a category without subcategories. Open tickets using it must be moved with reassignTo.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Category ID" Format(uuid)
+// @Param reassignTo query string false "Category that open tickets move to" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /admin/categories/{id} [delete]
+func DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	category, ok := loadCategory(w, r)
+	if !ok {
+		return
+	}
+
+	var reassignTo *uuid.UUID
+	if value := r.URL.Query().Get("reassignTo"); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid reassignTo category ID")
+			return
+		}
+		reassignTo = &parsed
+		if err := checkReassignTarget(r.Context(), category.ID, reassignTo); err != nil {
+			writeError(w, err, "Failed to fetch category")
+			return
+		}
+	}
+
+	moved, err := repo.Category.Deactivate(r.Context(), category.ID, reassignTo, true, requestActor(r).UserID, time.Now())
+	if err != nil {
+		writeError(w, err, "Failed to delete category")
+		return
+	}
+	categoryCache.invalidate()
+
+	utils.WriteSuccess(w, "Category deleted successfully", map[string]interface{}{
+		"reassignedTickets": moved,
+	})
+}
+
+func loadCategory(w http.ResponseWriter, r *http.Request) (*models.Category, bool) {
+	categoryID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid category ID")
+		return nil, false
+	}
+
+	category, err := repo.Category.GetByID(r.Context(), categoryID)
+	if err != nil {
+		writeError(w, err, "Failed to fetch category")
+		return nil, false
+	}
+	return category, true
+}
+
+// checkCategoryParent verifies that parentID is an active category and is not
+// the category itself or one of its descendants.
+func checkCategoryParent(ctx context.Context, categoryID, parentID uuid.UUID) error {
+	categories, err := repo.Category.GetAll(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	parents := make(map[uuid.UUID]*uuid.UUID, len(categories))
+	active := make(map[uuid.UUID]bool, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+		active[category.ID] = category.IsActive
+	}
+	if !active[parentID] {
+		return badRequest("Unknown or inactive parent category")
+	}
+
+	for id := &parentID; id != nil; id = parents[*id] {
+		if *id == categoryID {
+			return badRequest("A category cannot be moved under itself or its subcategories")
+		}
+	}
+	return nil
+}
+
+// checkReassignTarget verifies that open tickets can be moved to target.
+func checkReassignTarget(ctx context.Context, categoryID uuid.UUID, target *uuid.UUID) error {
+	if target == nil {
+		return nil
+	}
+	if *target == categoryID {
+		return badRequest("Tickets cannot be reassigned to the same category")
+	}
+
+	category, err := repo.Category.GetByID(ctx, *target)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && !category.IsActive) {
+		return badRequest("Unknown or inactive reassignment category")
+	}
+	return err
+}
+
+// buildCategoryTree nests categories under their parents and attaches form
+// fields to their category or ticket type. Categories whose parent is not in
+// the list are dropped, so an inactive parent hides its whole subtree.
+func buildCategoryTree(categories []*models.Category, fields []*models.FormField) *categoryTree {
+	byID := make(map[uuid.UUID]*models.Category, len(categories))
+	for _, category := range categories {
+		node := *category
+		node.Children = nil
+		node.FormFields = nil
+		byID[category.ID] = &node
+	}
+
+	tree := &categoryTree{TypeFields: make(map[models.TicketType][]*models.FormField)}
+	for _, field := range fields {
+		switch {
+		case field.CategoryID != nil:
+			if category, ok := byID[*field.CategoryID]; ok {
+				category.FormFields = append(category.FormFields, field)
+			}
+		case field.TicketType != nil:
+			tree.TypeFields[*field.TicketType] = append(tree.TypeFields[*field.TicketType], field)
+		}
+	}
+
+	for _, category := range categories {
+		node := byID[category.ID]
+		if category.ParentID == nil {
+			tree.Categories = append(tree.Categories, node)
+			continue
+		}
+		if parent, ok := byID[*category.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	return tree
+}
\ No newline at end of file
@@ -2,40 +2,81 @@ package handlers
 
 import (
 	"net/http"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/services"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 )
 
 // GetTicketComments godoc
 // @Summary Get ticket comments
-// @Description Retrieve all comments for a specific ticket
+// @Description Retrieve all comments for a specific ticket. Internal comments are only returned to users with the tickets:internal permission on the ticket.
 // @Tags comments
 // @Security BearerAuth
 // @Produce json
 // @Param id path string true "Ticket ID" Format(uuid)
-// @Success 200 {object} map[string]interface{}
-// @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
 // @Router /tickets/{id}/comments [get]
 func GetTicketComments(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"Get ticket comments endpoint - Coming soon"}`))
+	ticket, ok := loadAccessibleTicket(w, r)
+	if !ok {
+		return
+	}
+
+	includeInternal := services.Can(requestActor(r), services.ActionViewInternal, ticket)
+	comments, err := repo.Comment.GetByTicketID(r.Context(), ticket.ID, includeInternal)
+	if err != nil {
+		writeError(w, err, "Failed to fetch comments")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"comments": comments,
+		"total":    len(comments),
+	})
 }
 
 // AddTicketComment godoc
 // @Summary Add comment to ticket
-// @Description Add a new comment to a specific ticket
+// @Description Add a new comment to a specific ticket. The author is added as a watcher and the ticket's watchers are notified.
 // @Tags comments
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Ticket ID" Format(uuid)
-// @Success 201 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Param comment body models.CreateCommentRequest true "Comment"
+// @Param Idempotency-Key header string false "Key making retries of this request safe"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
 // @Router /tickets/{id}/comments [post]
 func AddTicketComment(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	w.Write([]byte(`{"message":"Add ticket comment endpoint - Coming soon"}`))
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	comment, err := ticketService.AddComment(actorContext(r), ticketID, &req)
+	if err != nil {
+		writeError(w, err, "Failed to create comment")
+		return
+	}
+
+	utils.WriteCreated(w, "Comment added successfully", map[string]interface{}{
+		"comment": comment,
+	})
 }
\ No newline at end of file
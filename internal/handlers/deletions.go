@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// DeleteTicket godoc
+// @Summary Delete ticket
+// @Description Soft-delete a ticket. It disappears from every listing and lookup, but its comments, attachments and history are kept and an admin can restore it.
+// @Tags instructor
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Param If-Match header string false "ETag of the ticket version the change is based on"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 412 {object} utils.APIResponse
+// @Router /instructor/tickets/{id} [delete]
+func DeleteTicket(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	if err := ticketService.Delete(actorContext(r), ticketID, ifMatchVersion(r)); err != nil {
+		writeError(w, err, "Failed to delete ticket")
+		return
+	}
+
+	utils.WriteSuccess(w, "Ticket deleted successfully", nil)
+}
+
+// DeleteTicketComment godoc
+// @Summary Delete comment
+// @Description Soft-delete a comment on a ticket. It is hidden from the ticket's comments until an admin restores it.
+// @Tags instructor
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Param commentId path string true "Comment ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /instructor/tickets/{id}/comments/{commentId} [delete]
+func DeleteTicketComment(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+	commentID, err := uuid.Parse(mux.Vars(r)["commentId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid comment ID")
+		return
+	}
+
+	if err := ticketService.DeleteComment(actorContext(r), ticketID, commentID); err != nil {
+		writeError(w, err, "Failed to delete comment")
+		return
+	}
+
+	utils.WriteSuccess(w, "Comment deleted successfully", nil)
+}
+
+// GetDeletedTickets godoc
+// @Summary List deleted tickets
+// @Description Retrieve the most recently deleted tickets, newest first, with who deleted them and when
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Number of tickets (default 100, max 1000)"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/deleted-tickets [get]
+func GetDeletedTickets(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	tickets, err := repo.Ticket.GetDeleted(r.Context(), limit)
+	if err != nil {
+		writeError(w, err, "Failed to fetch deleted tickets")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"tickets": tickets,
+		"total":   len(tickets),
+	})
+}
+
+// RestoreTicket godoc
+// @Summary Restore ticket
+// @Description Restore a deleted ticket with its comments, attachments and history
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/tickets/{id}/restore [post]
+func RestoreTicket(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	ticket, err := ticketService.Restore(actorContext(r), ticketID)
+	if err != nil {
+		writeError(w, err, "Failed to restore ticket")
+		return
+	}
+
+	w.Header().Set("ETag", ticketETag(ticket))
+	utils.WriteSuccess(w, "Ticket restored successfully", map[string]interface{}{
+		"ticket": ticket,
+	})
+}
+
+// GetDeletedComments godoc
+// @Summary List deleted comments
+// @Description Retrieve the deleted comments of a ticket, internal ones included
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/tickets/{id}/deleted-comments [get]
+func GetDeletedComments(w http.ResponseWriter, r *http.Request) {
+	ticket, ok := loadAccessibleTicket(w, r)
+	if !ok {
+		return
+	}
+
+	comments, err := repo.Comment.GetDeletedByTicketID(r.Context(), ticket.ID)
+	if err != nil {
+		writeError(w, err, "Failed to fetch deleted comments")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"comments": comments,
+		"total":    len(comments),
+	})
+}
+
+// RestoreComment godoc
+// @Summary Restore comment
+// @Description Restore a deleted comment with its attachments
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Comment ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/comments/{id}/restore [post]
+func RestoreComment(w http.ResponseWriter, r *http.Request) {
+	commentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid comment ID")
+		return
+	}
+
+	comment, err := ticketService.RestoreComment(actorContext(r), commentID)
+	if err != nil {
+		writeError(w, err, "Failed to restore comment")
+		return
+	}
+
+	utils.WriteSuccess(w, "Comment restored successfully", map[string]interface{}{
+		"comment": comment,
+	})
+}
\ No newline at end of file
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"community-support-service/internal/services"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	similarTicketMinScore = 0.3
+	similarTicketLimit    = 5
+)
+
+// FindSimilarTickets godoc
+// @Summary Suggest similar tickets
+// @Description Find open tickets similar to a draft, matched on course, type and title/description similarity. Students only see their own tickets.
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param draft body models.SimilarTicketsRequest true "Draft ticket"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Router /tickets/similar [post]
+func FindSimilarTickets(w http.ResponseWriter, r *http.Request) {
+	userID := requestActor(r).UserID
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User ID not found in request")
+		return
+	}
+
+	var req models.SimilarTicketsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	similar, err := findSimilarTickets(r.Context(), requestActor(r), req, nil)
+	if err != nil {
+		writeError(w, err, "Failed to find similar tickets")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"similarTickets": similar,
+		"total":          len(similar),
+	})
+}
+
+// GetTicketDuplicates godoc
+// @Summary Find possible duplicates
+// @Description List open tickets that look like duplicates of the given ticket
+// @Tags instructor
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /instructor/tickets/{id}/duplicates [get]
+func GetTicketDuplicates(w http.ResponseWriter, r *http.Request) {
+	ticket, ok := loadAccessibleTicket(w, r)
+	if !ok {
+		return
+	}
+
+	req := models.SimilarTicketsRequest{
+		Title:       ticket.Title,
+		Description: ticket.Description,
+		Type:        ticket.Type,
+		CourseID:    ticket.CourseID,
+	}
+	similar, err := findSimilarTickets(r.Context(), requestActor(r), req, &ticket.ID)
+	if err != nil {
+		writeError(w, err, "Failed to find duplicate tickets")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"duplicates": similar,
+		"total":      len(similar),
+	})
+}
+
+// MergeTickets godoc
+// @Summary Merge duplicate tickets
+// @Description Move comments, attachments, history and watchers from duplicates into this ticket, close the duplicates with a redirect to it, and notify their students
+// @Tags instructor
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Primary ticket ID" Format(uuid)
+// @Param merge body models.MergeTicketsRequest true "Duplicates to merge"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /instructor/tickets/{id}/merge [post]
+func MergeTickets(w http.ResponseWriter, r *http.Request) {
+	primaryID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	var req models.MergeTicketsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	primary, merged, err := ticketService.Merge(actorContext(r), primaryID, req.DuplicateIDs)
+	if err != nil {
+		writeError(w, err, "Failed to merge tickets")
+		return
+	}
+
+	utils.WriteSuccess(w, "Tickets merged successfully", map[string]interface{}{
+		"ticketId":      primary.ID,
+		"mergedTickets": merged,
+	})
+}
+
+// findSimilarTickets looks up open tickets resembling req. Users without
+// tickets:read are only shown their own tickets and others the tickets within
+// their scope, so
+// that suggestions never leak requests the actor may not view.
+func findSimilarTickets(ctx context.Context, actor *services.Actor, req models.SimilarTicketsRequest, excludeID *uuid.UUID) ([]*models.SimilarTicket, error) {
+	query := repositories.SimilarTicketQuery{
+		Title:       req.Title,
+		Description: req.Description,
+		CourseID:    req.CourseID,
+		ExcludeID:   excludeID,
+		OpenOnly:    true,
+		MinScore:    similarTicketMinScore,
+		Limit:       similarTicketLimit,
+	}
+	if req.Type != "" {
+		ticketType := string(req.Type)
+		query.Type = &ticketType
+	}
+	if !actor.HasPermission(models.PermTicketsRead) {
+		query.StudentID = &actor.UserID
+	}
+	query.Scope = services.ScopeFor(actor)
+
+	return repo.Ticket.FindSimilar(ctx, query)
+}
\ No newline at end of file
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"community-support-service/internal/repositories"
+	"community-support-service/internal/services"
+	"community-support-service/pkg/utils"
+)
+
+// domainErrors maps domain errors from repositories and services to their
+// HTTP status and error code. Not-found errors are mapped separately, with a
+// code naming the missing entity.
+var domainErrors = []struct {
+	err    error
+	status int
+	code   utils.ErrorCode
+}{
+	{services.ErrUnauthenticated, http.StatusUnauthorized, utils.CodeUnauthorized},
+	{services.ErrForbidden, http.StatusForbidden, utils.CodeForbidden},
+	{services.ErrInvalidInput, http.StatusBadRequest, utils.CodeBadRequest},
+	{services.ErrNotAnIncident, http.StatusConflict, "NOT_AN_INCIDENT"},
+	{services.ErrAlreadyWatching, http.StatusConflict, "ALREADY_WATCHING"},
+	{repositories.ErrInvalidTransition, http.StatusConflict, "INVALID_TRANSITION"},
+	{repositories.ErrVersionConflict, http.StatusPreconditionFailed, "VERSION_CONFLICT"},
+	{repositories.ErrInvalidCursor, http.StatusBadRequest, "INVALID_CURSOR"},
+	{repositories.ErrInvalidSort, http.StatusBadRequest, "INVALID_SORT"},
+	{repositories.ErrTicketMerged, http.StatusConflict, "TICKET_MERGED"},
+	{repositories.ErrRelationExists, http.StatusConflict, "RELATION_EXISTS"},
+	{repositories.ErrTicketHasParent, http.StatusConflict, "TICKET_HAS_PARENT"},
+	{repositories.ErrRelationCycle, http.StatusConflict, "RELATION_CYCLE"},
+	{repositories.ErrCategoryInUse, http.StatusConflict, "CATEGORY_IN_USE"},
+	{repositories.ErrCategoryHasChildren, http.StatusConflict, "CATEGORY_HAS_CHILDREN"},
+	{repositories.ErrCategoryExists, http.StatusConflict, "CATEGORY_EXISTS"},
+	{repositories.ErrFormFieldExists, http.StatusConflict, "FORM_FIELD_EXISTS"},
+	{repositories.ErrTagExists, http.StatusConflict, "TAG_EXISTS"},
+	{repositories.ErrSavedViewExists, http.StatusConflict, "SAVED_VIEW_EXISTS"},
+	{repositories.ErrRoleExists, http.StatusConflict, "ROLE_EXISTS"},
+	{repositories.ErrRoleAssigned, http.StatusConflict, "ROLE_ASSIGNED"},
+}
+
+// writeError writes the error response for err. Domain errors get their
+// mapped status and code, as do requestErrors; any other error is logged and reported as an
+// internal error with the given message, so driver errors never reach
+// clients.
+func writeError(w http.ResponseWriter, err error, message string) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		utils.WriteError(w, reqErr.status, reqErr.code, reqErr.message)
+		return
+	}
+
+	var notFound *repositories.NotFoundError
+	if errors.As(err, &notFound) {
+		code := strings.ToUpper(strings.ReplaceAll(notFound.Entity, " ", "_")) + "_NOT_FOUND"
+		utils.WriteError(w, http.StatusNotFound, utils.ErrorCode(code), notFound.Error())
+		return
+	}
+
+	for _, mapping := range domainErrors {
+		if errors.Is(err, mapping.err) {
+			utils.WriteError(w, mapping.status, mapping.code, err.Error())
+			return
+		}
+	}
+
+	fmt.Printf("%s: %v\n", message, err)
+	utils.WriteError(w, http.StatusInternalServerError, utils.CodeInternal, message)
+}
+
+// requestError is a problem found while checking a request on behalf of a
+// handler, carrying the status and code of its response.
+type requestError struct {
+	status  int
+	code    utils.ErrorCode
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+func badRequest(message string) error {
+	return &requestError{status: http.StatusBadRequest, code: utils.CodeBadRequest, message: message}
+}
\ No newline at end of file
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+var formFieldKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,63}$`)
+
+// GetFormFields godoc
+// @Summary List custom form fields
+// @Description Retrieve all custom ticket form fields, including inactive ones
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/form-fields [get]
+func GetFormFields(w http.ResponseWriter, r *http.Request) {
+	fields, err := repo.FormField.GetAll(r.Context(), false)
+	if err != nil {
+		writeError(w, err, "Failed to fetch form fields")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"formFields": fields,
+		"total":      len(fields),
+	})
+}
+
+// CreateFormField godoc
+// @Summary Create custom form field
+// @Description Add a typed field to the ticket form of a category (and its subcategories) or of a ticket type
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param field body models.CreateFormFieldRequest true "Form field"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /admin/form-fields [post]
+func CreateFormField(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateFormFieldRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if !formFieldKeyPattern.MatchString(req.Key) {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Field keys must start with a letter and contain at most 64 letters, digits or '_'")
+		return
+	}
+	if isReservedFormFieldKey(req.Key) {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Field key is reserved")
+		return
+	}
+	label := strings.TrimSpace(req.Label)
+	if label == "" || len(label) > 100 {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Field label is required and must be at most 100 characters")
+		return
+	}
+	options, err := formFieldOptions(req.FieldType, req.Options)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, err.Error())
+		return
+	}
+
+	if (req.CategoryID == nil) == (req.TicketType == nil) {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Exactly one of categoryId and ticketType is required")
+		return
+	}
+	if req.CategoryID != nil {
+		_, err := repo.Category.GetByID(r.Context(), *req.CategoryID)
+		if errors.Is(err, repositories.ErrNotFound) {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Category not found")
+			return
+		}
+		if err != nil {
+			writeError(w, err, "Failed to fetch category")
+			return
+		}
+	}
+
+	now := time.Now()
+	field := &models.FormField{
+		ID:         uuid.New(),
+		Key:        req.Key,
+		Label:      label,
+		FieldType:  req.FieldType,
+		Required:   req.Required,
+		Options:    options,
+		HelpText:   req.HelpText,
+		CategoryID: req.CategoryID,
+		TicketType: req.TicketType,
+		SortOrder:  req.SortOrder,
+		IsActive:   true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := repo.FormField.Create(r.Context(), field); err != nil {
+		writeError(w, err, "Failed to create form field")
+		return
+	}
+	categoryCache.invalidate()
+
+	utils.WriteCreated(w, "Form field created successfully", map[string]interface{}{
+		"formField": field,
+	})
+}
+
+// UpdateFormField godoc
+// @Summary Update custom form field
+// @Description Change a form field's label, options, requirement or order, or deactivate it. The key, type and scope are fixed.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Form field ID" Format(uuid)
+// @Param field body models.UpdateFormFieldRequest true "Form field changes"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/form-fields/{id} [put]
+func UpdateFormField(w http.ResponseWriter, r *http.Request) {
+	field, ok := loadFormField(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateFormFieldRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.Label != nil {
+		label := strings.TrimSpace(*req.Label)
+		if label == "" || len(label) > 100 {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Field label is required and must be at most 100 characters")
+			return
+		}
+		field.Label = label
+	}
+	if req.Options != nil {
+		options, err := formFieldOptions(field.FieldType, req.Options)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, err.Error())
+			return
+		}
+		field.Options = options
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if req.HelpText != nil {
+		field.HelpText = req.HelpText
+	}
+	if req.SortOrder != nil {
+		field.SortOrder = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		field.IsActive = *req.IsActive
+	}
+	field.UpdatedAt = time.Now()
+
+	if err := repo.FormField.Update(r.Context(), field); err != nil {
+		writeError(w, err, "Failed to update form field")
+		return
+	}
+	categoryCache.invalidate()
+
+	utils.WriteSuccess(w, "Form field updated successfully", map[string]interface{}{
+		"formField": field,
+	})
+}
+
+// DeleteFormField godoc
+// @Summary Delete custom form field
+// @Description Remove a form field. Values already stored on tickets are kept.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Form field ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/form-fields/{id} [delete]
+func DeleteFormField(w http.ResponseWriter, r *http.Request) {
+	field, ok := loadFormField(w, r)
+	if !ok {
+		return
+	}
+
+	if err := repo.FormField.Delete(r.Context(), field.ID); err != nil {
+		writeError(w, err, "Failed to delete form field")
+		return
+	}
+	categoryCache.invalidate()
+
+	utils.WriteSuccess(w, "Form field deleted successfully", nil)
+}
+
+func loadFormField(w http.ResponseWriter, r *http.Request) (*models.FormField, bool) {
+	fieldID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid form field ID")
+		return nil, false
+	}
+
+	field, err := repo.FormField.GetByID(r.Context(), fieldID)
+	if err != nil {
+		writeError(w, err, "Failed to fetch form field")
+		return nil, false
+	}
+	return field, true
+}
+
+// formFieldOptions trims and deduplicates select options. Only select fields
+// take options, and they need at least one.
+func formFieldOptions(fieldType models.FormFieldType, options []string) ([]string, error) {
+	if fieldType != models.FormFieldTypeSelect {
+		if len(options) > 0 {
+			return nil, errors.New("Only select fields take options")
+		}
+		return nil, nil
+	}
+
+	var cleaned []string
+	for _, option := range options {
+		if option = strings.TrimSpace(option); option != "" {
+			cleaned = append(cleaned, option)
+		}
+	}
+	cleaned = uniqueOptions(cleaned)
+	if len(cleaned) == 0 {
+		return nil, errors.New("Select fields need at least one option")
+	}
+	return cleaned, nil
+}
+
+func uniqueOptions(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// isReservedFormFieldKey reports whether key is a metadata key the service
+// reads itself, such as the student name used by macros.
+func isReservedFormFieldKey(key string) bool {
+	return strings.EqualFold(key, "studentName")
+}
\ No newline at end of file
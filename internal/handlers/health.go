@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+
+	"community-support-service/pkg/utils"
 )
 
 // HealthCheck godoc
@@ -9,10 +11,12 @@ import (
 // @Description Returns the health status of the service
 // @Tags health
 // @Produce json
-// @Success 200 {object} map[string]string
+// @Success 200 {object} utils.APIResponse
 // @Router /health [get]
 func HealthCheck(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"healthy","service":"kemuko-support-service","platform":"kemuko"}`))
+	utils.WriteJSON(w, http.StatusOK, map[string]string{
+		"status":   "healthy",
+		"service":  "kemuko-support-service",
+		"platform": "kemuko",
+	})
 }
\ No newline at end of file
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"community-support-service/internal/models"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// StartImpersonation godoc
+// @Summary Start impersonation session
+// @Description Start a short-lived, read-only session to view the API as a student. Send the session ID in the X-Impersonation-Session header alongside your own token; every request is audited and writes are refused.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param session body models.StartImpersonationRequest true "User to view as and reason"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/impersonations [post]
+func StartImpersonation(w http.ResponseWriter, r *http.Request) {
+	var req models.StartImpersonationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	session, err := impersonationService.Start(r.Context(), &req, requestActor(r).UserID)
+	if err != nil {
+		writeError(w, err, "Failed to start impersonation session")
+		return
+	}
+
+	utils.WriteCreated(w, "Impersonation session started successfully", map[string]interface{}{
+		"session": session,
+	})
+}
+
+// EndImpersonation godoc
+// @Summary End impersonation session
+// @Description End an impersonation session before it expires
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/impersonations/{id} [delete]
+func EndImpersonation(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := impersonationSessionID(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := impersonationService.End(r.Context(), sessionID)
+	if err != nil {
+		writeError(w, err, "Failed to end impersonation session")
+		return
+	}
+
+	utils.WriteSuccess(w, "Impersonation session ended successfully", map[string]interface{}{
+		"session": session,
+	})
+}
+
+// GetImpersonations godoc
+// @Summary List impersonation sessions
+// @Description Retrieve the most recent impersonation sessions, newest first
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param adminId query string false "Only sessions started by this admin"
+// @Param limit query int false "Number of sessions (default 100, max 1000)"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/impersonations [get]
+func GetImpersonations(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	var adminID *string
+	if value := r.URL.Query().Get("adminId"); value != "" {
+		adminID = &value
+	}
+
+	sessions, err := repo.Impersonation.GetRecent(r.Context(), adminID, limit)
+	if err != nil {
+		writeError(w, err, "Failed to fetch impersonation sessions")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"sessions": sessions,
+		"total":    len(sessions),
+	})
+}
+
+// GetImpersonationRequests godoc
+// @Summary Impersonated requests
+// @Description Retrieve every request made within an impersonation session, with both the admin's and the viewed user's identity
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/impersonations/{id}/requests [get]
+func GetImpersonationRequests(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := impersonationSessionID(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := repo.Impersonation.GetByID(r.Context(), sessionID); err != nil {
+		writeError(w, err, "Failed to fetch impersonation session")
+		return
+	}
+	requests, err := repo.Impersonation.GetRequests(r.Context(), sessionID)
+	if err != nil {
+		writeError(w, err, "Failed to fetch impersonated requests")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"requests": requests,
+		"total":    len(requests),
+	})
+}
+
+func impersonationSessionID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	sessionID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid impersonation session ID")
+		return uuid.Nil, false
+	}
+	return sessionID, true
+}
\ No newline at end of file
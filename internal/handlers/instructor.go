@@ -2,47 +2,106 @@ package handlers
 
 import (
 	"net/http"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 )
 
 // GetInstructorTickets godoc
-// @Summary Get instructor's assigned tickets
-// @Description Retrieve tickets assigned to the authenticated instructor
+// @Summary Get tickets for instructors
+// @Description Retrieve the staff ticket queue, paginated by cursor. Use q=assigned:me for tickets assigned to the authenticated instructor.
 // @Tags instructor
 // @Security BearerAuth
 // @Produce json
-// @Param status query string false "Filter by ticket status" Enums(open,in_progress,resolved,closed)
+// @Param status query string false "Filter by ticket status" Enums(open,inProgress,waitingForCustomer,resolved,closed)
 // @Param priority query string false "Filter by ticket priority" Enums(low,medium,high,urgent)
 // @Param courseId query string false "Filter by course ID"
-// @Param page query int false "Page number for pagination" default(1)
-// @Param pageSize query int false "Number of items per page" default(10)
-// @Success 200 {object} map[string]interface{}
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Param tagsAny query string false "Comma-separated tags; tickets with any of them"
+// @Param tagsAll query string false "Comma-separated tags; tickets with all of them"
+// @Param q query string false "Filter expression, e.g. status:open,inProgress priority:>=high assigned:none"
+// @Param view query string false "Saved view ID to apply" Format(uuid)
+// @Param cursor query string false "Opaque cursor from a previous page's nextCursor or prevCursor"
+// @Param limit query int false "Number of items per page" default(20)
+// @Param sort query string false "Comma-separated sort keys, prefix with - for descending: priority, slaDueAt, lastActivityAt, updatedAt, createdAt, ticketNumber" default(-createdAt)
+// @Param includeTotal query bool false "Also return the total number of matching tickets"
+// @Success 200 {object} utils.PaginatedResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
 // @Router /instructor/tickets [get]
 func GetInstructorTickets(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"Instructor tickets endpoint - Coming soon"}`))
+	userID := requestActor(r).UserID
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User ID not found in request")
+		return
+	}
+
+	filters := repositories.TicketFilters{}
+	if status := r.URL.Query().Get("status"); status != "" {
+		filters.Status = &status
+	}
+	if priority := r.URL.Query().Get("priority"); priority != "" {
+		filters.Priority = &priority
+	}
+	if courseID := r.URL.Query().Get("courseId"); courseID != "" {
+		filters.CourseID = &courseID
+	}
+	filters.TagsAny = tagListParam(r, "tagsAny")
+	filters.TagsAll = tagListParam(r, "tagsAll")
+
+	query, err := resolveTicketQuery(r, userID)
+	if err != nil {
+		writeError(w, err, "Failed to resolve ticket query")
+		return
+	}
+	filters.Query = query
+
+	listTickets(w, r, filters)
 }
 
 // UpdateTicket godoc
 // @Summary Update ticket
-// @Description Update ticket status, priority, or assignment
+// @Description Update a ticket's fields, status, priority or assignment, recording each change in the ticket history. Assigning needs tickets:assign and resolving or closing tickets:resolve.
 // @Tags instructor
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Ticket ID" Format(uuid)
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Param If-Match header string false "ETag of the ticket version the change is based on"
+// @Param request body models.UpdateTicketRequest true "Fields to change"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 412 {object} utils.APIResponse
 // @Router /instructor/tickets/{id} [put]
 func UpdateTicket(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"Update ticket endpoint - Coming soon"}`))
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	var req models.UpdateTicketRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	result, err := ticketService.Update(actorContext(r), ticketID, &req, ifMatchVersion(r))
+	if err != nil {
+		writeError(w, err, "Failed to update ticket")
+		return
+	}
+
+	w.Header().Set("ETag", ticketETag(result.Ticket))
+	utils.WriteSuccess(w, "Ticket updated successfully", map[string]interface{}{
+		"ticket":           result.Ticket,
+		"resolvedChildren": result.ResolvedChildren,
+	})
 }
 
 // AssignTicket godoc
@@ -53,14 +112,35 @@ func UpdateTicket(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Ticket ID" Format(uuid)
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Param If-Match header string false "ETag of the ticket version the change is based on"
+// @Param request body models.AssignTicketRequest true "Instructor to assign"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 412 {object} utils.APIResponse
 // @Router /instructor/tickets/{id}/assign [post]
 func AssignTicket(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"Assign ticket endpoint - Coming soon"}`))
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	var req models.AssignTicketRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	ticket, err := ticketService.Assign(actorContext(r), ticketID, req.InstructorID, ifMatchVersion(r))
+	if err != nil {
+		writeError(w, err, "Failed to assign ticket")
+		return
+	}
+
+	w.Header().Set("ETag", ticketETag(ticket))
+	utils.WriteSuccess(w, "Ticket assigned successfully", map[string]interface{}{
+		"ticket": ticket,
+	})
 }
\ No newline at end of file
@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/services"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetMacros godoc
+// @Summary List macros
+// @Description Retrieve the shared macros and the authenticated user's personal macros
+// @Tags macros
+// @Security BearerAuth
+// @Produce json
+// @Param includeInactive query bool false "Include deactivated macros"
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Router /instructor/macros [get]
+func GetMacros(w http.ResponseWriter, r *http.Request) {
+	userID := requestActor(r).UserID
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User ID not found in request")
+		return
+	}
+
+	activeOnly := r.URL.Query().Get("includeInactive") != "true"
+	macros, err := repo.Macro.GetVisible(r.Context(), userID, activeOnly)
+	if err != nil {
+		writeError(w, err, "Failed to fetch macros")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"macros": macros,
+		"total":  len(macros),
+	})
+}
+
+// CreateMacro godoc
+// @Summary Create macro
+// @Description Create a canned response. Users with the macros:share permission create shared macros unless personal is set; everyone else always creates personal macros.
+// @Tags macros
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param macro body models.CreateMacroRequest true "Macro"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Router /instructor/macros [post]
+func CreateMacro(w http.ResponseWriter, r *http.Request) {
+	userID := requestActor(r).UserID
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User ID not found in request")
+		return
+	}
+
+	var req models.CreateMacroRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Macro name is required and must be at most 100 characters")
+		return
+	}
+	if err := validateMacro(req.Content, &req.Actions); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now()
+	macro := &models.Macro{
+		ID:         uuid.New(),
+		Name:       req.Name,
+		Content:    req.Content,
+		IsInternal: req.IsInternal,
+		Actions:    req.Actions,
+		CreatedBy:  userID,
+		IsActive:   true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if req.Personal || !requestActor(r).HasPermission(models.PermMacrosShare) {
+		macro.OwnerID = &userID
+	}
+
+	if err := repo.Macro.Create(r.Context(), macro); err != nil {
+		writeError(w, err, "Failed to create macro")
+		return
+	}
+
+	utils.WriteCreated(w, "Macro created successfully", map[string]interface{}{
+		"macro": macro,
+	})
+}
+
+// UpdateMacro godoc
+// @Summary Update macro
+// @Description Change a macro's text, actions or active state. Shared macros can only be changed with the macros:share permission, personal macros by their owner.
+// @Tags macros
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Macro ID" Format(uuid)
+// @Param macro body models.UpdateMacroRequest true "Macro changes"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /instructor/macros/{id} [put]
+func UpdateMacro(w http.ResponseWriter, r *http.Request) {
+	macro, ok := loadManageableMacro(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateMacroRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > 100 {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Macro name is required and must be at most 100 characters")
+			return
+		}
+		macro.Name = name
+	}
+	if req.Content != nil {
+		macro.Content = req.Content
+	}
+	if req.IsInternal != nil {
+		macro.IsInternal = *req.IsInternal
+	}
+	if req.Actions != nil {
+		macro.Actions = *req.Actions
+	}
+	if req.IsActive != nil {
+		macro.IsActive = *req.IsActive
+	}
+	if err := validateMacro(macro.Content, &macro.Actions); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, err.Error())
+		return
+	}
+	macro.UpdatedAt = time.Now()
+
+	if err := repo.Macro.Update(r.Context(), macro); err != nil {
+		writeError(w, err, "Failed to update macro")
+		return
+	}
+
+	utils.WriteSuccess(w, "Macro updated successfully", map[string]interface{}{
+		"macro": macro,
+	})
+}
+
+// DeleteMacro godoc
+// @Summary Delete macro
+// @Description Delete a macro. Shared macros can only be deleted with the macros:share permission, personal macros by their owner.
+// @Tags macros
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Macro ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /instructor/macros/{id} [delete]
+func DeleteMacro(w http.ResponseWriter, r *http.Request) {
+	macro, ok := loadManageableMacro(w, r)
+	if !ok {
+		return
+	}
+
+	if err := repo.Macro.Delete(r.Context(), macro.ID); err != nil {
+		writeError(w, err, "Failed to delete macro")
+		return
+	}
+
+	utils.WriteSuccess(w, "Macro deleted successfully", nil)
+}
+
+// ApplyMacro godoc
+// @Summary Apply macro
+// @Description Post the macro's rendered reply and apply its field changes to the ticket in one transaction, recording each change in the ticket history
+// @Tags macros
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Param macroId path string true "Macro ID" Format(uuid)
+// @Param If-Match header string false "ETag of the ticket version the change is based on"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 412 {object} utils.APIResponse
+// @Router /instructor/tickets/{id}/macros/{macroId} [post]
+func ApplyMacro(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+	macroID, err := uuid.Parse(mux.Vars(r)["macroId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid macro ID")
+		return
+	}
+
+	result, err := ticketService.ApplyMacro(actorContext(r), ticketID, macroID, ifMatchVersion(r))
+	if err != nil {
+		writeError(w, err, "Failed to apply macro")
+		return
+	}
+
+	w.Header().Set("ETag", ticketETag(result.Ticket))
+	utils.WriteSuccess(w, "Macro applied successfully", map[string]interface{}{
+		"ticket":           result.Ticket,
+		"comment":          result.Comment,
+		"resolvedChildren": result.ResolvedChildren,
+	})
+}
+
+// loadManageableMacro fetches the macro named in the route and checks that
+// the user may change it: users with macros:share manage shared macros, owners their own.
+func loadManageableMacro(w http.ResponseWriter, r *http.Request) (*models.Macro, bool) {
+	macroID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid macro ID")
+		return nil, false
+	}
+
+	userID := requestActor(r).UserID
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User ID not found in request")
+		return nil, false
+	}
+
+	macro, err := repo.Macro.GetByID(r.Context(), macroID)
+	if err != nil {
+		writeError(w, err, "Failed to fetch macro")
+		return nil, false
+	}
+	if macro.OwnerID != nil && *macro.OwnerID != userID {
+		utils.WriteError(w, http.StatusNotFound, "MACRO_NOT_FOUND", "Macro not found")
+		return nil, false
+	}
+	if macro.OwnerID == nil && !requestActor(r).HasPermission(models.PermMacrosShare) {
+		utils.WriteError(w, http.StatusForbidden, utils.CodeForbidden, "You do not have permission to change shared macros")
+		return nil, false
+	}
+
+	return macro, true
+}
+
+// validateMacro checks the template variables and actions of a macro and
+// normalizes its tags.
+func validateMacro(content *string, actions *models.MacroActions) error {
+	hasContent := content != nil && strings.TrimSpace(*content) != ""
+	if hasContent {
+		if unknown := services.UnknownMacroVariables(*content); len(unknown) > 0 {
+			return fmt.Errorf("Unknown template variables: %s", strings.Join(unknown, ", "))
+		}
+	}
+	if actions.AssignTo != nil && strings.TrimSpace(*actions.AssignTo) == "" {
+		return fmt.Errorf("Assignee must not be empty")
+	}
+
+	tags := make([]string, 0, len(actions.AddTags))
+	seen := make(map[string]bool)
+	for _, tag := range actions.AddTags {
+		tag, ok := services.NormalizeTag(tag)
+		if !ok {
+			return fmt.Errorf("Invalid tag %q", tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	actions.AddTags = tags
+
+	if !hasContent && actions.Status == nil && actions.Priority == nil && actions.AssignTo == nil && len(tags) == 0 {
+		return fmt.Errorf("Macro must have reply text or at least one action")
+	}
+	return nil
+}
\ No newline at end of file
@@ -1,18 +1,50 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+
+	"community-support-service/internal/models"
+	"community-support-service/pkg/utils"
 )
 
 // GetCategories godoc
 // @Summary Get all active categories
-// @Description Retrieve all active support categories without authentication
+// @Description Retrieve the active support categories as a tree, with the custom form fields of each category (inherited by its subcategories) and of each ticket type, without authentication
 // @Tags public
 // @Produce json
-// @Success 200 {object} map[string]interface{}
+// @Param type query string false "Only top-level categories of this ticket type (with their subcategories)" Enums(general,technical,course,assignment,grading,platform,content)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
 // @Router /public/categories [get]
 func GetCategories(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"Public categories endpoint - Coming soon"}`))
+	tree, err := categoryCache.get(r.Context())
+	if err != nil {
+		writeError(w, err, "Failed to fetch categories")
+		return
+	}
+
+	categories := tree.Categories
+	typeFields := tree.TypeFields
+	if value := r.URL.Query().Get("type"); value != "" {
+		categoryType := models.TicketType(value)
+		if !categoryType.Valid() {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid category type")
+			return
+		}
+		categories = make([]*models.Category, 0, len(tree.Categories))
+		for _, category := range tree.Categories {
+			if category.Type == categoryType {
+				categories = append(categories, category)
+			}
+		}
+		typeFields = map[models.TicketType][]*models.FormField{categoryType: tree.TypeFields[categoryType]}
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(categoryCacheTTL.Seconds())))
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"categories":     categories,
+		"typeFormFields": typeFields,
+		"total":          len(categories),
+	})
 }
\ No newline at end of file
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"net/http"
+
+	"community-support-service/internal/models"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetTicketRelations godoc
+// @Summary List ticket relations
+// @Description Retrieve the parent/child, relates-to and blocking links of a ticket
+// @Tags relations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /tickets/{id}/relations [get]
+func GetTicketRelations(w http.ResponseWriter, r *http.Request) {
+	ticket, ok := loadAccessibleTicket(w, r)
+	if !ok {
+		return
+	}
+
+	relations, err := repo.Relation.GetByTicketID(r.Context(), ticket.ID)
+	if err != nil {
+		writeError(w, err, "Failed to fetch relations")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"relations": relations,
+		"total":     len(relations),
+	})
+}
+
+// CreateTicketRelation godoc
+// @Summary Link tickets
+// @Description Link this ticket to another one. Type is read from this ticket's point of view: parentOf, childOf, relatesTo, blocks or blockedBy.
+// @Tags relations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Param relation body models.CreateRelationRequest true "Relation"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /instructor/tickets/{id}/relations [post]
+func CreateTicketRelation(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	var req models.CreateRelationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	relation, err := ticketService.LinkTickets(actorContext(r), ticketID, &req)
+	if err != nil {
+		writeError(w, err, "Failed to link tickets")
+		return
+	}
+
+	utils.WriteCreated(w, "Tickets linked successfully", map[string]interface{}{
+		"relation": relation,
+	})
+}
+
+// DeleteTicketRelation godoc
+// @Summary Unlink tickets
+// @Description Remove a relation involving this ticket
+// @Tags relations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Param relationId path string true "Relation ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /instructor/tickets/{id}/relations/{relationId} [delete]
+func DeleteTicketRelation(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+	relationID, err := uuid.Parse(mux.Vars(r)["relationId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid relation ID")
+		return
+	}
+
+	if err := ticketService.UnlinkTickets(actorContext(r), ticketID, relationID); err != nil {
+		writeError(w, err, "Failed to unlink tickets")
+		return
+	}
+
+	utils.WriteSuccess(w, "Tickets unlinked successfully", nil)
+}
+
+// SetTicketIncident godoc
+// @Summary Toggle incident mode
+// @Description Mark a ticket as the parent of a platform incident so that updates can be broadcast to its children
+// @Tags relations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Param incident body models.SetIncidentRequest true "Incident flag"
+// @Param If-Match header string false "ETag of the ticket version the change is based on"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 412 {object} utils.APIResponse
+// @Router /instructor/tickets/{id}/incident [put]
+func SetTicketIncident(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	var req models.SetIncidentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	ticket, err := ticketService.SetIncident(actorContext(r), ticketID, req.IsIncident, ifMatchVersion(r))
+	if err != nil {
+		writeError(w, err, "Failed to update ticket")
+		return
+	}
+
+	w.Header().Set("ETag", ticketETag(ticket))
+	utils.WriteSuccess(w, "Ticket updated successfully", map[string]interface{}{
+		"ticket": ticket,
+	})
+}
+
+// BroadcastIncidentUpdate godoc
+// @Summary Broadcast incident update
+// @Description Post a public comment on an incident ticket and on each of its open child tickets, notifying their watchers
+// @Tags relations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Incident ticket ID" Format(uuid)
+// @Param broadcast body models.BroadcastRequest true "Update"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /instructor/tickets/{id}/broadcast [post]
+func BroadcastIncidentUpdate(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	var req models.BroadcastRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	children, failed, err := ticketService.BroadcastIncidentUpdate(actorContext(r), ticketID, req.Content)
+	if err != nil {
+		writeError(w, err, "Failed to broadcast incident update")
+		return
+	}
+
+	utils.WriteCreated(w, "Incident update broadcast successfully", map[string]interface{}{
+		"childTickets": children,
+		"failed":       failed,
+	})
+}
\ No newline at end of file
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// RevokeToken godoc
+// @Summary Revoke a token
+// @Description Revoke a single JWT by its jti claim until the token expires
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param revocation body models.RevokeTokenRequest true "Token to revoke"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/token-revocations [post]
+func RevokeToken(w http.ResponseWriter, r *http.Request) {
+	var req models.RevokeTokenRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	now := time.Now()
+	if !req.ExpiresAt.After(now) {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Token has already expired")
+		return
+	}
+
+	token := &models.RevokedToken{
+		JTI:       req.JTI,
+		ExpiresAt: req.ExpiresAt,
+		Reason:    req.Reason,
+		RevokedBy: requestActor(r).UserID,
+		RevokedAt: now,
+	}
+	if err := revocationService.RevokeToken(r.Context(), token); err != nil {
+		writeError(w, err, "Failed to revoke token")
+		return
+	}
+
+	utils.WriteCreated(w, "Token revoked successfully", map[string]interface{}{
+		"revocation": token,
+	})
+}
+
+// RevokeUserTokens godoc
+// @Summary Revoke a user's tokens
+// @Description Revoke every token issued to a user until now. Tokens issued afterwards are accepted again.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param revocation body models.RevokeUserTokensRequest false "Reason"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/users/{userId}/revoke-tokens [post]
+func RevokeUserTokens(w http.ResponseWriter, r *http.Request) {
+	var req models.RevokeUserTokensRequest
+	if r.ContentLength != 0 && !decodeRequest(w, r, &req) {
+		return
+	}
+
+	now := time.Now()
+	revocation := &models.UserTokenRevocation{
+		UserID:        mux.Vars(r)["userId"],
+		RevokedBefore: now,
+		Reason:        req.Reason,
+		RevokedBy:     requestActor(r).UserID,
+		UpdatedAt:     now,
+	}
+	if err := revocationService.RevokeUserTokens(r.Context(), revocation); err != nil {
+		writeError(w, err, "Failed to revoke user tokens")
+		return
+	}
+
+	utils.WriteSuccess(w, "User tokens revoked successfully", map[string]interface{}{
+		"revocation": revocation,
+	})
+}
\ No newline at end of file
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"net/http"
+
+	"community-support-service/internal/models"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetRoles godoc
+// @Summary List roles
+// @Description Retrieve every role with its permissions, including the built-in ones
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/roles [get]
+func GetRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := repo.Role.GetAll(r.Context())
+	if err != nil {
+		writeError(w, err, "Failed to fetch roles")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"roles":       roles,
+		"permissions": models.Permissions,
+		"total":       len(roles),
+	})
+}
+
+// CreateRole godoc
+// @Summary Create role
+// @Description Add a role mapped to a set of permissions. Tokens whose userType names the role hold it; course-scoped roles then apply only to the token's courses.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param role body models.CreateRoleRequest true "Role"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /admin/roles [post]
+func CreateRole(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateRoleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	role, err := accessService.CreateRole(r.Context(), &req)
+	if err != nil {
+		writeError(w, err, "Failed to create role")
+		return
+	}
+
+	utils.WriteCreated(w, "Role created successfully", map[string]interface{}{
+		"role": role,
+	})
+}
+
+// UpdateRole godoc
+// @Summary Update role
+// @Description Change a role's description, permissions or course scoping. The admin role cannot be changed.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Param role body models.UpdateRoleRequest true "Changes"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/roles/{name} [put]
+func UpdateRole(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateRoleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	role, err := accessService.UpdateRole(r.Context(), mux.Vars(r)["name"], &req)
+	if err != nil {
+		writeError(w, err, "Failed to update role")
+		return
+	}
+
+	utils.WriteSuccess(w, "Role updated successfully", map[string]interface{}{
+		"role": role,
+	})
+}
+
+// DeleteRole godoc
+// @Summary Delete role
+// @Description Delete a role and every assignment of it. Built-in roles cannot be deleted.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/roles/{name} [delete]
+func DeleteRole(w http.ResponseWriter, r *http.Request) {
+	if err := accessService.DeleteRole(r.Context(), mux.Vars(r)["name"]); err != nil {
+		writeError(w, err, "Failed to delete role")
+		return
+	}
+
+	utils.WriteSuccess(w, "Role deleted successfully", nil)
+}
+
+// GetRoleAssignments godoc
+// @Summary List role assignments
+// @Description Retrieve the roles assigned to users, optionally for one user
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param userId query string false "Only this user's assignments"
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/role-assignments [get]
+func GetRoleAssignments(w http.ResponseWriter, r *http.Request) {
+	var userID *string
+	if value := r.URL.Query().Get("userId"); value != "" {
+		userID = &value
+	}
+
+	assignments, err := repo.Role.GetAssignments(r.Context(), userID)
+	if err != nil {
+		writeError(w, err, "Failed to fetch role assignments")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"assignments": assignments,
+		"total":       len(assignments),
+	})
+}
+
+// CreateRoleAssignment godoc
+// @Summary Assign role
+// @Description Give a user a role for the listed courses, or for all courses when courseIds is empty
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param assignment body models.CreateRoleAssignmentRequest true "Assignment"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /admin/role-assignments [post]
+func CreateRoleAssignment(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateRoleAssignmentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	assignment, err := accessService.Assign(r.Context(), &req, requestActor(r).UserID)
+	if err != nil {
+		writeError(w, err, "Failed to assign role")
+		return
+	}
+
+	utils.WriteCreated(w, "Role assigned successfully", map[string]interface{}{
+		"assignment": assignment,
+	})
+}
+
+// DeleteRoleAssignment godoc
+// @Summary Remove role assignment
+// @Description Take an assigned role away from a user
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Assignment ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/role-assignments/{id} [delete]
+func DeleteRoleAssignment(w http.ResponseWriter, r *http.Request) {
+	assignmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid assignment ID")
+		return
+	}
+
+	if err := accessService.Unassign(r.Context(), assignmentID); err != nil {
+		writeError(w, err, "Failed to remove role assignment")
+		return
+	}
+
+	utils.WriteSuccess(w, "Role assignment removed successfully", nil)
+}
\ No newline at end of file
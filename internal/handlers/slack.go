@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+
+	"community-support-service/pkg/utils"
 )
 
 // SlackReply godoc
@@ -11,15 +13,13 @@ import (
 // @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
 // @Router /slack/reply [post]
 func SlackReply(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"Slack reply endpoint - Coming soon"}`))
+	utils.WriteSuccess(w, "Slack reply endpoint - Coming soon", nil)
 }
 
 // SlackWebhook godoc
@@ -28,10 +28,8 @@ func SlackReply(w http.ResponseWriter, r *http.Request) {
 // @Tags slack
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} utils.APIResponse
 // @Router /slack/webhook [post]
 func SlackWebhook(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"Slack webhook endpoint - Coming soon"}`))
+	utils.WriteSuccess(w, "Slack webhook endpoint - Coming soon", nil)
 }
\ No newline at end of file
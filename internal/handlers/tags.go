@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/services"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// GetTags godoc
+// @Summary List tag catalog
+// @Description Retrieve the admin-managed tag catalog with display colors
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Router /tags [get]
+func GetTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := repo.Tag.GetAll(r.Context())
+	if err != nil {
+		writeError(w, err, "Failed to fetch tags")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"tags":  tags,
+		"total": len(tags),
+	})
+}
+
+// CreateTag godoc
+// @Summary Create catalog tag
+// @Description Add a tag with a display color to the catalog
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param tag body models.CreateTagRequest true "Tag"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /admin/tags [post]
+func CreateTag(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateTagRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	name, ok := services.NormalizeTag(req.Name)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Tag names must be 1-50 lowercase letters, digits, '-' or '_'")
+		return
+	}
+	if req.Color != nil && !colorPattern.MatchString(*req.Color) {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Color must be a hex color such as #1E88E5")
+		return
+	}
+
+	now := time.Now()
+	tag := &models.Tag{
+		Name:        name,
+		Color:       req.Color,
+		Description: req.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := repo.Tag.Create(r.Context(), tag); err != nil {
+		writeError(w, err, "Failed to create tag")
+		return
+	}
+
+	utils.WriteCreated(w, "Tag created successfully", map[string]interface{}{
+		"tag": tag,
+	})
+}
+
+// UpdateTag godoc
+// @Summary Update catalog tag
+// @Description Change a catalog tag's color or description
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param name path string true "Tag name"
+// @Param tag body models.UpdateTagRequest true "Tag changes"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /admin/tags/{name} [put]
+func UpdateTag(w http.ResponseWriter, r *http.Request) {
+	tag, err := repo.Tag.GetByName(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		writeError(w, err, "Failed to fetch tag")
+		return
+	}
+
+	var req models.UpdateTagRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Color != nil {
+		if !colorPattern.MatchString(*req.Color) {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Color must be a hex color such as #1E88E5")
+			return
+		}
+		tag.Color = req.Color
+	}
+	if req.Description != nil {
+		tag.Description = req.Description
+	}
+	tag.UpdatedAt = time.Now()
+
+	if err := repo.Tag.Update(r.Context(), tag); err != nil {
+		writeError(w, err, "Failed to update tag")
+		return
+	}
+
+	utils.WriteSuccess(w, "Tag updated successfully", map[string]interface{}{
+		"tag": tag,
+	})
+}
+
+// DeleteTag godoc
+// @Summary Delete catalog tag
+// @Description Remove a tag from the catalog. Tickets keep the tag but lose its color.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Tag name"
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/tags/{name} [delete]
+func DeleteTag(w http.ResponseWriter, r *http.Request) {
+	if err := repo.Tag.Delete(r.Context(), mux.Vars(r)["name"]); err != nil {
+		writeError(w, err, "Failed to delete tag")
+		return
+	}
+
+	utils.WriteSuccess(w, "Tag deleted successfully", nil)
+}
+
+// AddTicketTags godoc
+// @Summary Tag ticket
+// @Description Add one or more tags to a ticket. Tags need not be in the catalog.
+// @Tags tags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Param tags body models.TicketTagsRequest true "Tags"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /instructor/tickets/{id}/tags [post]
+func AddTicketTags(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	var req models.TicketTagsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	added, err := ticketService.AddTags(actorContext(r), ticketID, req.Tags)
+	if err != nil {
+		writeError(w, err, "Failed to tag ticket")
+		return
+	}
+
+	utils.WriteSuccess(w, "Tags added successfully", map[string]interface{}{
+		"added": added,
+	})
+}
+
+// RemoveTicketTag godoc
+// @Summary Untag ticket
+// @Description Remove a tag from a ticket
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Param tag path string true "Tag name"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /instructor/tickets/{id}/tags/{tag} [delete]
+func RemoveTicketTag(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	if err := ticketService.RemoveTag(actorContext(r), ticketID, mux.Vars(r)["tag"]); err != nil {
+		writeError(w, err, "Failed to untag ticket")
+		return
+	}
+
+	utils.WriteSuccess(w, "Tag removed successfully", nil)
+}
+
+// GetTagReport godoc
+// @Summary Tag usage report
+// @Description Count tickets per tag, with open (unresolved) counts and catalog colors
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param fromDate query string false "Only tickets created on or after this date (YYYY-MM-DD)"
+// @Param toDate query string false "Only tickets created before the end of this date (YYYY-MM-DD)"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /admin/reports/tags [get]
+func GetTagReport(w http.ResponseWriter, r *http.Request) {
+	var fromDate, toDate *time.Time
+	if value := r.URL.Query().Get("fromDate"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid fromDate")
+			return
+		}
+		fromDate = &parsed
+	}
+	if value := r.URL.Query().Get("toDate"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid toDate")
+			return
+		}
+		endOfDay := parsed.Add(24*time.Hour - time.Nanosecond)
+		toDate = &endOfDay
+	}
+
+	counts, err := repo.Tag.GetCounts(r.Context(), fromDate, toDate)
+	if err != nil {
+		writeError(w, err, "Failed to build tag report")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"tags":  counts,
+		"total": len(counts),
+	})
+}
+
+// tagListParam reads a comma-separated list of tags from a query parameter,
+// skipping entries that are not valid tag names.
+func tagListParam(r *http.Request, name string) []string {
+	var tags []string
+	for _, part := range strings.Split(r.URL.Query().Get(name), ",") {
+		if tag, ok := services.NormalizeTag(part); ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
\ No newline at end of file
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"community-support-service/pkg/middleware"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetSavedViews godoc
+// @Summary List saved views
+// @Description Retrieve the authenticated user's saved ticket views and views shared with their teams
+// @Tags views
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Router /views [get]
+func GetSavedViews(w http.ResponseWriter, r *http.Request) {
+	userID := requestActor(r).UserID
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User ID not found in request")
+		return
+	}
+
+	views, err := repo.SavedView.GetVisible(r.Context(), userID, userTeams(r))
+	if err != nil {
+		writeError(w, err, "Failed to fetch saved views")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"views": views,
+		"total": len(views),
+	})
+}
+
+// CreateSavedView godoc
+// @Summary Create saved view
+// @Description Save a named ticket filter expression, optionally shared with one of the user's teams
+// @Tags views
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param view body models.CreateSavedViewRequest true "Saved view"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /views [post]
+func CreateSavedView(w http.ResponseWriter, r *http.Request) {
+	userID := requestActor(r).UserID
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User ID not found in request")
+		return
+	}
+
+	var req models.CreateSavedViewRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "View name is required and must be at most 100 characters")
+		return
+	}
+	if _, err := repositories.ParseTicketQuery(req.Query, userID, time.Now()); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, fmt.Sprintf("Invalid query: %v", err))
+		return
+	}
+	if req.TeamID != nil && !hasTeam(r, *req.TeamID) {
+		utils.WriteError(w, http.StatusForbidden, utils.CodeForbidden, "Views can only be shared with your own teams")
+		return
+	}
+
+	now := time.Now()
+	view := &models.SavedView{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Query:     req.Query,
+		OwnerID:   userID,
+		TeamID:    req.TeamID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := repo.SavedView.Create(r.Context(), view); err != nil {
+		writeError(w, err, "Failed to create saved view")
+		return
+	}
+
+	utils.WriteCreated(w, "Saved view created successfully", map[string]interface{}{
+		"view": view,
+	})
+}
+
+// UpdateSavedView godoc
+// @Summary Update saved view
+// @Description Rename, change the query of, or re-share a saved view owned by the user
+// @Tags views
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "View ID" Format(uuid)
+// @Param view body models.UpdateSavedViewRequest true "Saved view changes"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /views/{id} [put]
+func UpdateSavedView(w http.ResponseWriter, r *http.Request) {
+	userID := requestActor(r).UserID
+	view, ok := loadOwnedView(w, r, userID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSavedViewRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > 100 {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "View name is required and must be at most 100 characters")
+			return
+		}
+		view.Name = name
+	}
+	if req.Query != nil {
+		if _, err := repositories.ParseTicketQuery(*req.Query, userID, time.Now()); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, fmt.Sprintf("Invalid query: %v", err))
+			return
+		}
+		view.Query = *req.Query
+	}
+	if req.TeamID != nil {
+		// An empty team ID makes the view private again
+		if *req.TeamID == "" {
+			view.TeamID = nil
+		} else if !hasTeam(r, *req.TeamID) {
+			utils.WriteError(w, http.StatusForbidden, utils.CodeForbidden, "Views can only be shared with your own teams")
+			return
+		} else {
+			view.TeamID = req.TeamID
+		}
+	}
+	view.UpdatedAt = time.Now()
+
+	if err := repo.SavedView.Update(r.Context(), view); err != nil {
+		writeError(w, err, "Failed to update saved view")
+		return
+	}
+
+	utils.WriteSuccess(w, "Saved view updated successfully", map[string]interface{}{
+		"view": view,
+	})
+}
+
+// DeleteSavedView godoc
+// @Summary Delete saved view
+// @Description Delete a saved view owned by the user
+// @Tags views
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "View ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /views/{id} [delete]
+func DeleteSavedView(w http.ResponseWriter, r *http.Request) {
+	userID := requestActor(r).UserID
+	view, ok := loadOwnedView(w, r, userID)
+	if !ok {
+		return
+	}
+
+	if err := repo.SavedView.Delete(r.Context(), view.ID); err != nil {
+		writeError(w, err, "Failed to delete saved view")
+		return
+	}
+
+	utils.WriteSuccess(w, "Saved view deleted successfully", nil)
+}
+
+// loadOwnedView fetches the view named in the route and checks that userID
+// owns it, writing the error response and returning false otherwise.
+func loadOwnedView(w http.ResponseWriter, r *http.Request, userID string) (*models.SavedView, bool) {
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User ID not found in request")
+		return nil, false
+	}
+
+	viewID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid view ID")
+		return nil, false
+	}
+
+	view, err := repo.SavedView.GetByID(r.Context(), viewID)
+	if err != nil {
+		writeError(w, err, "Failed to fetch saved view")
+		return nil, false
+	}
+	if view.OwnerID != userID {
+		utils.WriteError(w, http.StatusForbidden, utils.CodeForbidden, "Only the owner can modify a saved view")
+		return nil, false
+	}
+
+	return view, true
+}
+
+// resolveTicketQuery combines the saved view named by the "view" query
+// parameter with the ad-hoc "q" expression. It returns a nil query when
+// neither is present.
+func resolveTicketQuery(r *http.Request, userID string) (*repositories.TicketQuery, error) {
+	var parts []string
+
+	if viewParam := r.URL.Query().Get("view"); viewParam != "" {
+		viewID, err := uuid.Parse(viewParam)
+		if err != nil {
+			return nil, badRequest("Invalid view ID")
+		}
+		view, err := repo.SavedView.GetByID(r.Context(), viewID)
+		if err != nil {
+			return nil, err
+		}
+		if view.OwnerID != userID && (view.TeamID == nil || !hasTeam(r, *view.TeamID)) {
+			return nil, &repositories.NotFoundError{Entity: "saved view"}
+		}
+		parts = append(parts, view.Query)
+	}
+
+	if q := r.URL.Query().Get("q"); q != "" {
+		parts = append(parts, q)
+	}
+
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	query, err := repositories.ParseTicketQuery(strings.Join(parts, " "), userID, time.Now())
+	if err != nil {
+		return nil, badRequest(fmt.Sprintf("Invalid query: %v", err))
+	}
+	return query, nil
+}
+
+func userTeams(r *http.Request) []string {
+	if user, ok := middleware.GetUserFromContext(r.Context()); ok {
+		return user.Teams
+	}
+	return nil
+}
+
+func hasTeam(r *http.Request, teamID string) bool {
+	for _, team := range userTeams(r) {
+		if team == teamID {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
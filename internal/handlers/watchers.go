@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"net/http"
+
+	"community-support-service/internal/models"
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetTicketWatchers godoc
+// @Summary List ticket watchers
+// @Description Retrieve the users following a ticket
+// @Tags watchers
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /tickets/{id}/watchers [get]
+func GetTicketWatchers(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	watchers, err := ticketService.Watchers(actorContext(r), ticketID)
+	if err != nil {
+		writeError(w, err, "Failed to fetch watchers")
+		return
+	}
+
+	utils.WriteSuccess(w, "", map[string]interface{}{
+		"watchers": watchers,
+		"total":    len(watchers),
+	})
+}
+
+// AddTicketWatcher godoc
+// @Summary Add ticket watcher
+// @Description Add a CC participant to a ticket. Users with the watchers:manage permission can add any role; the ticket's student can add a guardian.
+// @Tags watchers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Param watcher body models.AddWatcherRequest true "Watcher"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /tickets/{id}/watchers [post]
+func AddTicketWatcher(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	var req models.AddWatcherRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	watcher, err := ticketService.AddWatcher(actorContext(r), ticketID, &req)
+	if err != nil {
+		writeError(w, err, "Failed to add watcher")
+		return
+	}
+
+	utils.WriteCreated(w, "Watcher added successfully", map[string]interface{}{
+		"watcher": watcher,
+	})
+}
+
+// RemoveTicketWatcher godoc
+// @Summary Remove ticket watcher
+// @Description Stop a user from following a ticket. Users with the watchers:manage permission can remove anyone, users can remove themselves, and the ticket's student can remove watchers they added.
+// @Tags watchers
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Param userId path string true "Watcher user ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /tickets/{id}/watchers/{userId} [delete]
+func RemoveTicketWatcher(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	if err := ticketService.RemoveWatcher(actorContext(r), ticketID, mux.Vars(r)["userId"]); err != nil {
+		writeError(w, err, "Failed to remove watcher")
+		return
+	}
+
+	utils.WriteSuccess(w, "Watcher removed successfully", nil)
+}
+
+// WatchTicket godoc
+// @Summary Watch ticket
+// @Description Follow a ticket as the authenticated user, who needs the tickets:read permission
+// @Tags watchers
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /tickets/{id}/watch [post]
+func WatchTicket(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	if err := ticketService.Watch(actorContext(r), ticketID); err != nil {
+		writeError(w, err, "Failed to watch ticket")
+		return
+	}
+
+	utils.WriteSuccess(w, "You are now watching this ticket", nil)
+}
+
+// UnwatchTicket godoc
+// @Summary Unwatch ticket
+// @Description Stop following a ticket as the authenticated user
+// @Tags watchers
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ticket ID" Format(uuid)
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.APIResponse
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /tickets/{id}/watch [delete]
+func UnwatchTicket(w http.ResponseWriter, r *http.Request) {
+	ticketID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, "Invalid ticket ID")
+		return
+	}
+
+	if err := ticketService.Unwatch(actorContext(r), ticketID); err != nil {
+		writeError(w, err, "Failed to unwatch ticket")
+		return
+	}
+
+	utils.WriteSuccess(w, "You are no longer watching this ticket", nil)
+}
\ No newline at end of file
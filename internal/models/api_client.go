@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// API client scopes. A client may only call the routes that require one of
+// its scopes.
+const (
+	ScopeTicketsCreate = "tickets:create"
+	ScopeTicketsRead   = "tickets:read"
+	ScopeCommentsWrite = "comments:write"
+)
+
+// APIScopes lists every scope an API client can be granted.
+var APIScopes = []string{ScopeTicketsCreate, ScopeTicketsRead, ScopeCommentsWrite}
+
+// APIClient is a service (the LMS, the grading service) calling the API with
+// a key instead of a user's JWT. Only a hash of the key is kept.
+type APIClient struct {
+	ID          uuid.UUID      `json:"id" db:"id"`
+	Name        string         `json:"name" db:"name"`
+	Description *string        `json:"description" db:"description"`
+	KeyPrefix   string         `json:"keyPrefix" db:"keyPrefix"`
+	KeyHash     string         `json:"-" db:"keyHash"`
+	Scopes      pq.StringArray `json:"scopes" db:"scopes" swaggertype:"array,string"`
+	IsActive    bool           `json:"isActive" db:"isActive"`
+	CreatedBy   string         `json:"createdBy" db:"createdBy"`
+	CreatedAt   time.Time      `json:"createdAt" db:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt" db:"updatedAt"`
+	LastUsedAt  *time.Time     `json:"lastUsedAt" db:"lastUsedAt"`
+}
+
+// APIClientUsage is one audited request made with an API client's key.
+type APIClientUsage struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	ClientID   uuid.UUID `json:"clientId" db:"clientId"`
+	Method     string    `json:"method" db:"method"`
+	Path       string    `json:"path" db:"path"`
+	Scope      *string   `json:"scope" db:"scope"`
+	OnBehalfOf *string   `json:"onBehalfOf" db:"onBehalfOf"`
+	Status     int       `json:"status" db:"status"`
+	RequestID  *string   `json:"requestId" db:"requestId"`
+	RemoteAddr *string   `json:"remoteAddr" db:"remoteAddr"`
+	CreatedAt  time.Time `json:"createdAt" db:"createdAt"`
+}
+
+type CreateAPIClientRequest struct {
+	Name        string   `json:"name" validate:"required,max=100"`
+	Description *string  `json:"description"`
+	Scopes      []string `json:"scopes" validate:"required,min=1"`
+}
+
+type UpdateAPIClientRequest struct {
+	Name        *string  `json:"name" validate:"omitempty,max=100"`
+	Description *string  `json:"description"`
+	Scopes      []string `json:"scopes" validate:"omitempty,min=1"`
+	IsActive    *bool    `json:"isActive"`
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ArchivedTicket is a closed ticket moved to the archive, with its comments,
+// attachments and history as they were when it was archived. Listings only
+// carry the ticket.
+type ArchivedTicket struct {
+	Ticket      *Ticket          `json:"ticket"`
+	Comments    []*TicketComment `json:"comments,omitempty"`
+	Attachments []*Attachment    `json:"attachments,omitempty"`
+	History     []*TicketHistory `json:"history,omitempty"`
+	ArchivedAt  time.Time        `json:"archivedAt"`
+}
\ No newline at end of file
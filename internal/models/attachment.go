@@ -7,20 +7,20 @@ import (
 )
 
 type Attachment struct {
-	ID         uuid.UUID  `jsonb:"id" db:"id"`
-	TicketID   *uuid.UUID `jsonb:"ticketId" db:"ticketId"`
-	CommentID  *uuid.UUID `jsonb:"commentId" db:"commentId"`
-	FileName   string     `jsonb:"fileName" db:"fileName"`
-	FileUrl    string     `jsonb:"fileUrl" db:"fileUrl"`
-	FileType   *string    `jsonb:"fileType" db:"fileType"`
-	UploadedBy string     `jsonb:"uploadedBy" db:"uploadedBy"`
-	Metadata   JSONB      `jsonb:"metadata" db:"metadata"`
-	CreatedAt  time.Time  `jsonb:"createdAt" db:"createdAt"`
+	ID         uuid.UUID  `json:"id" db:"id"`
+	TicketID   *uuid.UUID `json:"ticketId" db:"ticketId"`
+	CommentID  *uuid.UUID `json:"commentId" db:"commentId"`
+	FileName   string     `json:"fileName" db:"fileName"`
+	FileUrl    string     `json:"fileUrl" db:"fileUrl"`
+	FileType   *string    `json:"fileType" db:"fileType"`
+	UploadedBy string     `json:"uploadedBy" db:"uploadedBy"`
+	Metadata   JSONB      `json:"metadata" db:"metadata"`
+	CreatedAt  time.Time  `json:"createdAt" db:"createdAt"`
 }
 
 type CreateAttachmentRequest struct {
-	FileName string  `jsonb:"fileName" validate:"required"`
-	FileUrl  string  `jsonb:"fileUrl" validate:"required,url"`
-	FileType *string `jsonb:"fileType"`
-	Metadata JSONB   `jsonb:"metadata"`
+	FileName string  `json:"fileName" validate:"required"`
+	FileUrl  string  `json:"fileUrl" validate:"required,url"`
+	FileType *string `json:"fileType"`
+	Metadata JSONB   `json:"metadata"`
 }
\ No newline at end of file
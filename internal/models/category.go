@@ -9,26 +9,38 @@ import (
 )
 
 type Category struct {
-	ID          uuid.UUID `jsonb:"id" db:"id"`
-	Name        string    `jsonb:"name" db:"name"`
-	Description *string   `jsonb:"description" db:"description"`
-	Color       *string   `jsonb:"color" db:"color"`
-	IsActive    bool      `jsonb:"isActive" db:"isActive"`
-	CreatedAt   time.Time `jsonb:"createdAt" db:"createdAt"`
-	UpdatedAt   time.Time `jsonb:"updatedAt" db:"updatedAt"`
+	ID          uuid.UUID  `json:"id" db:"id"`
+	Name        string     `json:"name" db:"name"`
+	Description *string    `json:"description" db:"description"`
+	Color       *string    `json:"color" db:"color"`
+	Type        TicketType `json:"type" db:"type"`
+	ParentID    *uuid.UUID `json:"parentId" db:"parentId"`
+	IsActive    bool       `json:"isActive" db:"isActive"`
+	CreatedAt   time.Time  `json:"createdAt" db:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt" db:"updatedAt"`
+
+	// Populated when building the category tree
+	Children   []*Category  `json:"children,omitempty" db:"-"`
+	FormFields []*FormField `json:"formFields,omitempty" db:"-"` // the category's own fields; subcategories inherit them
 }
 
 type CreateCategoryRequest struct {
-	Name        string  `jsonb:"name" validate:"required,max=100"`
-	Description *string `jsonb:"description"`
-	Color       *string `jsonb:"color" validate:"omitempty,len=7"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	Description *string    `json:"description"`
+	Color       *string    `json:"color" validate:"omitempty,len=7"`
+	Type        TicketType `json:"type"`
+	ParentID    *uuid.UUID `json:"parentId"`
 }
 
 type UpdateCategoryRequest struct {
-	Name        *string `jsonb:"name" validate:"omitempty,max=100"`
-	Description *string `jsonb:"description"`
-	Color       *string `jsonb:"color" validate:"omitempty,len=7"`
-	IsActive    *bool   `jsonb:"isActive"`
+	Name        *string     `json:"name" validate:"omitempty,max=100"`
+	Description *string     `json:"description"`
+	Color       *string     `json:"color" validate:"omitempty,len=7"`
+	Type        *TicketType `json:"type"`
+	ParentID    *uuid.UUID  `json:"parentId"`
+	TopLevel    bool        `json:"topLevel"` // move to the top level; a null parentId cannot be told apart from an omitted one
+	IsActive    *bool       `json:"isActive"`
+	ReassignTo  *uuid.UUID  `json:"reassignTo"` // category that open tickets move to when deactivating
 }
 
 // JSONB is a custom type for handling PostgreSQL JSONB fields
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type FormFieldType string
+
+const (
+	FormFieldTypeText   FormFieldType = "text"
+	FormFieldTypeNumber FormFieldType = "number"
+	FormFieldTypeSelect FormFieldType = "select"
+	FormFieldTypeDate   FormFieldType = "date"
+	FormFieldTypeURL    FormFieldType = "url"
+)
+
+const maxTextFieldLength = 1000
+
+var formFieldTypes = []FormFieldType{FormFieldTypeText, FormFieldTypeNumber, FormFieldTypeSelect, FormFieldTypeDate, FormFieldTypeURL}
+
+// Valid reports whether t is one of the known form field types.
+func (t FormFieldType) Valid() bool { return slices.Contains(formFieldTypes, t) }
+
+// Values lists the known form field types.
+func (FormFieldType) Values() []string { return enumValues(formFieldTypes) }
+
+// FormField is a typed custom field on the ticket form, scoped either to a
+// category (applying to its subcategories too) or to a ticket type. Values
+// are stored in the ticket's metadata under Key.
+type FormField struct {
+	ID         uuid.UUID      `json:"id" db:"id"`
+	Key        string         `json:"key" db:"key"`
+	Label      string         `json:"label" db:"label"`
+	FieldType  FormFieldType  `json:"fieldType" db:"fieldType"`
+	Required   bool           `json:"required" db:"required"`
+	Options    pq.StringArray `json:"options,omitempty" db:"options"`
+	HelpText   *string        `json:"helpText" db:"helpText"`
+	CategoryID *uuid.UUID     `json:"categoryId" db:"categoryId"`
+	TicketType *TicketType    `json:"ticketType" db:"ticketType"`
+	SortOrder  int            `json:"sortOrder" db:"sortOrder"`
+	IsActive   bool           `json:"isActive" db:"isActive"`
+	CreatedAt  time.Time      `json:"createdAt" db:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt" db:"updatedAt"`
+}
+
+// Normalize checks a submitted value against the field's type and returns it
+// in the form stored in ticket metadata: numbers as float64, everything else
+// as a trimmed string. A nil result means the optional field was left empty.
+func (f *FormField) Normalize(value interface{}) (interface{}, error) {
+	if str, ok := value.(string); ok {
+		value = strings.TrimSpace(str)
+	}
+	if value == nil || value == "" {
+		if f.Required {
+			return nil, fmt.Errorf("is required")
+		}
+		return nil, nil
+	}
+
+	switch f.FieldType {
+	case FormFieldTypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			number, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("must be a number")
+			}
+			return number, nil
+		}
+		return nil, fmt.Errorf("must be a number")
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("must be a string")
+	}
+
+	switch f.FieldType {
+	case FormFieldTypeText:
+		if len(str) > maxTextFieldLength {
+			return nil, fmt.Errorf("must be at most %d characters", maxTextFieldLength)
+		}
+	case FormFieldTypeSelect:
+		for _, option := range f.Options {
+			if option == str {
+				return str, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of: %s", strings.Join(f.Options, ", "))
+	case FormFieldTypeDate:
+		if _, err := time.Parse("2006-01-02", str); err != nil {
+			return nil, fmt.Errorf("must be a date (YYYY-MM-DD)")
+		}
+	case FormFieldTypeURL:
+		parsed, err := url.ParseRequestURI(str)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("must be an http or https URL")
+		}
+	}
+	return str, nil
+}
+
+type CreateFormFieldRequest struct {
+	Key        string        `json:"key" validate:"required,max=64"`
+	Label      string        `json:"label" validate:"required,max=100"`
+	FieldType  FormFieldType `json:"fieldType" validate:"required"`
+	Required   bool          `json:"required"`
+	Options    []string      `json:"options"`
+	HelpText   *string       `json:"helpText"`
+	CategoryID *uuid.UUID    `json:"categoryId"` // exactly one of categoryId and ticketType
+	TicketType *TicketType   `json:"ticketType"`
+	SortOrder  int           `json:"sortOrder"`
+}
+
+type UpdateFormFieldRequest struct {
+	Label     *string  `json:"label" validate:"omitempty,max=100"`
+	Required  *bool    `json:"required"`
+	Options   []string `json:"options"` // replaces the options when set
+	HelpText  *string  `json:"helpText"`
+	SortOrder *int     `json:"sortOrder"`
+	IsActive  *bool    `json:"isActive"`
+}
\ No newline at end of file
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// IdempotencyKey is a request sent with an Idempotency-Key header and, once
+// it has completed, its response. Status is nil while it is in flight.
+type IdempotencyKey struct {
+	UserID      string    `json:"userId" db:"userId"`
+	Key         string    `json:"key" db:"key"`
+	Fingerprint string    `json:"fingerprint" db:"fingerprint"`
+	Status      *int      `json:"status" db:"status"`
+	ContentType *string   `json:"contentType" db:"contentType"`
+	ETag        *string   `json:"etag" db:"etag"`
+	Body        []byte    `json:"-" db:"body"`
+	CreatedAt   time.Time `json:"createdAt" db:"createdAt"`
+	ExpiresAt   time.Time `json:"expiresAt" db:"expiresAt"`
+}
\ No newline at end of file
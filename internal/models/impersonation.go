@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ImpersonationSession lets an admin view the API as another user for a short
+// while. Sessions are read-only and end at ExpiresAt or when ended early.
+// The user is viewed with the email, teams and courses from their last token
+// before the session started.
+type ImpersonationSession struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	AdminID   string         `json:"adminId" db:"adminId"`
+	UserID    string         `json:"userId" db:"userId"`
+	Reason    string         `json:"reason" db:"reason"`
+	Email     *string        `json:"email" db:"email"`
+	Teams     pq.StringArray `json:"teams" db:"teams" swaggertype:"array,string"`
+	Courses   pq.StringArray `json:"courses" db:"courses" swaggertype:"array,string"`
+	ExpiresAt time.Time      `json:"expiresAt" db:"expiresAt"`
+	EndedAt   *time.Time     `json:"endedAt" db:"endedAt"`
+	CreatedAt time.Time      `json:"createdAt" db:"createdAt"`
+}
+
+// UserClaims are the identity claims of a user's most recent token.
+type UserClaims struct {
+	UserID    string         `json:"userId" db:"userId"`
+	Email     *string        `json:"email" db:"email"`
+	Teams     pq.StringArray `json:"teams" db:"teams" swaggertype:"array,string"`
+	Courses   pq.StringArray `json:"courses" db:"courses" swaggertype:"array,string"`
+	UpdatedAt time.Time      `json:"updatedAt" db:"updatedAt"`
+}
+
+// IsActive reports whether the session can still be used at now.
+func (s *ImpersonationSession) IsActive(now time.Time) bool {
+	return s.EndedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// ImpersonatedRequest is one audited request made within an impersonation
+// session, recorded with both the admin's and the viewed user's identity.
+type ImpersonatedRequest struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	SessionID uuid.UUID `json:"sessionId" db:"sessionId"`
+	AdminID   string    `json:"adminId" db:"adminId"`
+	UserID    string    `json:"userId" db:"userId"`
+	Method    string    `json:"method" db:"method"`
+	Path      string    `json:"path" db:"path"`
+	Status    int       `json:"status" db:"status"`
+	RequestID *string   `json:"requestId" db:"requestId"`
+	CreatedAt time.Time `json:"createdAt" db:"createdAt"`
+}
+
+type StartImpersonationRequest struct {
+	UserID string `json:"userId" validate:"required,max=255"`
+	Reason string `json:"reason" validate:"required,max=500"`
+}
\ No newline at end of file
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Macro is a canned response: templated reply text plus optional field
+// changes applied to the ticket in the same step. Shared macros have no
+// owner and are managed by admins; personal macros belong to an instructor.
+type Macro struct {
+	ID         uuid.UUID    `json:"id" db:"id"`
+	Name       string       `json:"name" db:"name"`
+	Content    *string      `json:"content" db:"content"`
+	IsInternal bool         `json:"isInternal" db:"isInternal"`
+	Actions    MacroActions `json:"actions" db:"actions"`
+	OwnerID    *string      `json:"ownerId" db:"ownerId"`
+	CreatedBy  string       `json:"createdBy" db:"createdBy"`
+	IsActive   bool         `json:"isActive" db:"isActive"`
+	CreatedAt  time.Time    `json:"createdAt" db:"createdAt"`
+	UpdatedAt  time.Time    `json:"updatedAt" db:"updatedAt"`
+}
+
+// MacroActions lists the field changes a macro makes. It is stored as JSONB,
+// so the json tags define the persisted keys. AssignTo accepts "me" for the
+// user applying the macro.
+type MacroActions struct {
+	Status   *TicketStatus   `json:"status,omitempty"`
+	Priority *TicketPriority `json:"priority,omitempty"`
+	AddTags  []string        `json:"addTags,omitempty"`
+	AssignTo *string         `json:"assignTo,omitempty"`
+}
+
+// Value implements the driver.Valuer interface for database/sql
+func (a MacroActions) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+// Scan implements the sql.Scanner interface for database/sql
+func (a *MacroActions) Scan(value interface{}) error {
+	if value == nil {
+		*a = MacroActions{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(bytes, a)
+}
+
+type CreateMacroRequest struct {
+	Name       string       `json:"name" validate:"required,max=100"`
+	Content    *string      `json:"content"`
+	IsInternal bool         `json:"isInternal"`
+	Actions    MacroActions `json:"actions"`
+	Personal   bool         `json:"personal"` // admins create shared macros unless set; instructors always create personal ones
+}
+
+type UpdateMacroRequest struct {
+	Name       *string       `json:"name" validate:"omitempty,max=100"`
+	Content    *string       `json:"content"`
+	IsInternal *bool         `json:"isInternal"`
+	Actions    *MacroActions `json:"actions"`
+	IsActive   *bool         `json:"isActive"`
+}
\ No newline at end of file
@@ -23,52 +23,52 @@ const (
 )
 
 type EmailNotificationRequest struct {
-	To          []string               `jsonb:"to" validate:"required"`
-	Subject     string                 `jsonb:"subject" validate:"required"`
-	TemplateID  string                 `jsonb:"templateId" validate:"required"`
-	TemplateData map[string]interface{} `jsonb:"templateData"`
-	ReplyTo     *string                `jsonb:"replyTo"`
+	To          []string               `json:"to" validate:"required"`
+	Subject     string                 `json:"subject" validate:"required"`
+	TemplateID  string                 `json:"templateId" validate:"required"`
+	TemplateData map[string]interface{} `json:"templateData"`
+	ReplyTo     *string                `json:"replyTo"`
 }
 
 type SlackNotificationRequest struct {
-	Channel     string                 `jsonb:"channel" validate:"required"`
-	Message     string                 `jsonb:"message" validate:"required"`
-	Blocks      []SlackBlock           `jsonb:"blocks,omitempty"`
-	ThreadTS    *string                `jsonb:"threadTs,omitempty"`
-	TemplateData map[string]interface{} `jsonb:"templateData"`
+	Channel     string                 `json:"channel" validate:"required"`
+	Message     string                 `json:"message" validate:"required"`
+	Blocks      []SlackBlock           `json:"blocks,omitempty"`
+	ThreadTS    *string                `json:"threadTs,omitempty"`
+	TemplateData map[string]interface{} `json:"templateData"`
 }
 
 type SlackBlock struct {
-	Type string      `jsonb:"type"`
-	Text *SlackText  `jsonb:"text,omitempty"`
-	Elements []SlackElement `jsonb:"elements,omitempty"`
+	Type string      `json:"type"`
+	Text *SlackText  `json:"text,omitempty"`
+	Elements []SlackElement `json:"elements,omitempty"`
 }
 
 type SlackText struct {
-	Type string `jsonb:"type"`
-	Text string `jsonb:"text"`
+	Type string `json:"type"`
+	Text string `json:"text"`
 }
 
 type SlackElement struct {
-	Type     string `jsonb:"type"`
-	Text     string `jsonb:"text,omitempty"`
-	Value    string `jsonb:"value,omitempty"`
-	ActionID string `jsonb:"actionId,omitempty"`
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Value    string `json:"value,omitempty"`
+	ActionID string `json:"actionId,omitempty"`
 }
 
 type SlackReplyRequest struct {
-	TicketID    uuid.UUID `jsonb:"ticketId" validate:"required"`
-	AdminUserID string    `jsonb:"adminUserId" validate:"required"`
-	Message     string    `jsonb:"message" validate:"required"`
-	IsInternal  bool      `jsonb:"isInternal"`
-	Metadata    JSONB     `jsonb:"metadata"`
+	TicketID    uuid.UUID `json:"ticketId" validate:"required"`
+	AdminUserID string    `json:"adminUserId" validate:"required"`
+	Message     string    `json:"message" validate:"required"`
+	IsInternal  bool      `json:"isInternal"`
+	Metadata    JSONB     `json:"metadata"`
 }
 
 type NotificationEvent struct {
-	Type         NotificationType `jsonb:"type"`
-	TicketID     uuid.UUID        `jsonb:"ticketId"`
-	TriggerUserID string          `jsonb:"triggerUserId"`
-	Recipients   []string         `jsonb:"recipients"`
-	Data         JSONB            `jsonb:"data"`
-	CreatedAt    time.Time        `jsonb:"createdAt"`
+	Type         NotificationType `json:"type"`
+	TicketID     uuid.UUID        `json:"ticketId"`
+	TriggerUserID string          `json:"triggerUserId"`
+	Recipients   []string         `json:"recipients"`
+	Data         JSONB            `json:"data"`
+	CreatedAt    time.Time        `json:"createdAt"`
 }
\ No newline at end of file
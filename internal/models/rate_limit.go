@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// RateLimitBucket is the state of one token bucket. A bucket that was never
+// used has a zero UpdatedAt and counts as full.
+type RateLimitBucket struct {
+	Key       string    `json:"key" db:"key"`
+	Tokens    float64   `json:"tokens" db:"tokens"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updatedAt"`
+	FullAt    time.Time `json:"fullAt" db:"fullAt"`
+}
\ No newline at end of file
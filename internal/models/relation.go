@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RelationType string
+
+// Relations are stored in one direction; the inverse views ("child of",
+// "blocked by") are derived by swapping source and target.
+const (
+	RelationTypeParent    RelationType = "parent"    // source is the parent of target
+	RelationTypeRelatesTo RelationType = "relatesTo" // symmetric
+	RelationTypeBlocks    RelationType = "blocks"    // source blocks target
+)
+
+type TicketRelation struct {
+	ID             uuid.UUID    `json:"id" db:"id"`
+	SourceTicketID uuid.UUID    `json:"sourceTicketId" db:"sourceTicketId"`
+	TargetTicketID uuid.UUID    `json:"targetTicketId" db:"targetTicketId"`
+	Type           RelationType `json:"type" db:"type"`
+	CreatedBy      string       `json:"createdBy" db:"createdBy"`
+	CreatedAt      time.Time    `json:"createdAt" db:"createdAt"`
+}
+
+// CreateRelationRequest links the ticket in the route to TargetTicketID.
+// Type is read from that ticket's point of view: parentOf, childOf,
+// relatesTo, blocks or blockedBy.
+type CreateRelationRequest struct {
+	TargetTicketID uuid.UUID `json:"targetTicketId" validate:"required"`
+	Type           string    `json:"type" validate:"required,oneof=parentOf childOf relatesTo blocks blockedBy"`
+}
+
+type SetIncidentRequest struct {
+	IsIncident bool `json:"isIncident"`
+}
+
+type BroadcastRequest struct {
+	Content string `json:"content" validate:"required"`
+}
\ No newline at end of file
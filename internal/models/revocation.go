@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// RevokedToken is a single JWT revoked by its jti claim.
+type RevokedToken struct {
+	JTI       string    `json:"jti" db:"jti"`
+	ExpiresAt time.Time `json:"expiresAt" db:"expiresAt"`
+	Reason    *string   `json:"reason" db:"reason"`
+	RevokedBy string    `json:"revokedBy" db:"revokedBy"`
+	RevokedAt time.Time `json:"revokedAt" db:"revokedAt"`
+}
+
+// UserTokenRevocation revokes every token issued to a user before
+// RevokedBefore.
+type UserTokenRevocation struct {
+	UserID        string    `json:"userId" db:"userId"`
+	RevokedBefore time.Time `json:"revokedBefore" db:"revokedBefore"`
+	Reason        *string   `json:"reason" db:"reason"`
+	RevokedBy     string    `json:"revokedBy" db:"revokedBy"`
+	UpdatedAt     time.Time `json:"updatedAt" db:"updatedAt"`
+}
+
+// RevokeTokenRequest revokes one token by jti. ExpiresAt is the token's exp
+// claim; the revocation is kept until then.
+type RevokeTokenRequest struct {
+	JTI       string    `json:"jti" validate:"required,max=255"`
+	ExpiresAt time.Time `json:"expiresAt" validate:"required"`
+	Reason    *string   `json:"reason"`
+}
+
+// RevokeUserTokensRequest revokes every token issued to a user so far.
+type RevokeUserTokensRequest struct {
+	Reason *string `json:"reason"`
+}
\ No newline at end of file
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Permissions grantable through roles. Ticket permissions apply to the
+// tickets a grant covers; the others are checked per route.
+const (
+	PermTicketsRead     = "tickets:read"     // view other users' tickets and the staff queue
+	PermTicketsComment  = "tickets:comment"  // comment on other users' tickets
+	PermTicketsInternal = "tickets:internal" // read and write internal notes
+	PermTicketsUpdate   = "tickets:update"   // tags, relations, merges, incidents, macros
+	PermTicketsAssign   = "tickets:assign"
+	PermTicketsResolve  = "tickets:resolve"
+	PermTicketsDelete   = "tickets:delete"
+	PermTicketsRestore  = "tickets:restore" // list and restore deleted tickets and comments
+	PermWatchersManage  = "watchers:manage" // add and remove other users as watchers
+	PermMacrosUse       = "macros:use"      // personal macros
+	PermMacrosShare     = "macros:share"    // shared macros
+	PermCatalogManage   = "catalog:manage"  // categories, tags and form fields
+	PermReportsRead     = "reports:read"
+	PermAccessManage    = "access:manage" // roles, API clients and token revocations
+	PermSlackReply      = "slack:reply"
+	PermImpersonate     = "users:impersonate" // read-only "view as" sessions
+)
+
+// Permissions lists every permission a role can be given.
+var Permissions = []string{
+	PermTicketsRead, PermTicketsComment, PermTicketsInternal, PermTicketsUpdate,
+	PermTicketsAssign, PermTicketsResolve, PermTicketsDelete, PermTicketsRestore, PermWatchersManage,
+	PermMacrosUse, PermMacrosShare, PermCatalogManage, PermReportsRead,
+	PermAccessManage, PermSlackReply, PermImpersonate,
+}
+
+// RoleAdmin is the built-in role that can never be changed, so admins cannot
+// lock themselves out.
+const RoleAdmin = "admin"
+
+// Role maps a role name to a set of permissions. Users hold the role named
+// by their token and any roles assigned to them in the service. When
+// CourseScoped is set, the role held through the token only applies to the
+// courses in the token's courses claim.
+type Role struct {
+	Name         string         `json:"name" db:"name"`
+	Description  *string        `json:"description" db:"description"`
+	Permissions  pq.StringArray `json:"permissions" db:"permissions" swaggertype:"array,string"`
+	CourseScoped bool           `json:"courseScoped" db:"courseScoped"`
+	BuiltIn      bool           `json:"builtIn" db:"builtIn"`
+	CreatedAt    time.Time      `json:"createdAt" db:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt" db:"updatedAt"`
+}
+
+// RoleAssignment gives a user a role, for the listed courses only or, when
+// CourseIDs is empty, for all courses.
+type RoleAssignment struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	UserID    string         `json:"userId" db:"userId"`
+	Role      string         `json:"role" db:"role"`
+	CourseIDs pq.StringArray `json:"courseIds" db:"courseIds" swaggertype:"array,string"`
+	CreatedBy string         `json:"createdBy" db:"createdBy"`
+	CreatedAt time.Time      `json:"createdAt" db:"createdAt"`
+}
+
+type CreateRoleRequest struct {
+	Name         string   `json:"name" validate:"required,max=50"`
+	Description  *string  `json:"description"`
+	Permissions  []string `json:"permissions"`
+	CourseScoped bool     `json:"courseScoped"`
+}
+
+type UpdateRoleRequest struct {
+	Description  *string  `json:"description"`
+	Permissions  []string `json:"permissions"`
+	CourseScoped *bool    `json:"courseScoped"`
+}
+
+type CreateRoleAssignmentRequest struct {
+	UserID    string   `json:"userId" validate:"required,max=255"`
+	Role      string   `json:"role" validate:"required,max=50"`
+	CourseIDs []string `json:"courseIds"`
+}
\ No newline at end of file
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedView is a named ticket filter expression owned by a user and
+// optionally shared with a team.
+type SavedView struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Query     string    `json:"query" db:"query"`
+	OwnerID   string    `json:"ownerId" db:"ownerId"`
+	TeamID    *string   `json:"teamId" db:"teamId"`
+	CreatedAt time.Time `json:"createdAt" db:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updatedAt"`
+}
+
+type CreateSavedViewRequest struct {
+	Name   string  `json:"name" validate:"required,max=100"`
+	Query  string  `json:"query" validate:"required"`
+	TeamID *string `json:"teamId"`
+}
+
+type UpdateSavedViewRequest struct {
+	Name   *string `json:"name" validate:"omitempty,max=100"`
+	Query  *string `json:"query"`
+	TeamID *string `json:"teamId"`
+}
\ No newline at end of file
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Tag is a catalog entry giving a tag a color and description. Tickets may
+// carry tags that are not in the catalog.
+type Tag struct {
+	Name        string    `json:"name" db:"name"`
+	Color       *string   `json:"color" db:"color"`
+	Description *string   `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"createdAt" db:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updatedAt"`
+}
+
+// TagCount reports how many tickets carry a tag.
+type TagCount struct {
+	Name  string  `json:"name" db:"name"`
+	Color *string `json:"color" db:"color"`
+	Total int64   `json:"total" db:"total"`
+	Open  int64   `json:"open" db:"open"` // not yet resolved or closed
+}
+
+type CreateTagRequest struct {
+	Name        string  `json:"name" validate:"required,max=50"`
+	Color       *string `json:"color" validate:"omitempty,len=7"`
+	Description *string `json:"description"`
+}
+
+type UpdateTagRequest struct {
+	Color       *string `json:"color" validate:"omitempty,len=7"`
+	Description *string `json:"description"`
+}
+
+type TicketTagsRequest struct {
+	Tags []string `json:"tags" validate:"required,min=1"`
+}
\ No newline at end of file
@@ -1,9 +1,11 @@
 package models
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type TicketStatus string
@@ -35,75 +37,139 @@ const (
 	TicketTypeContent    TicketType = "content"
 )
 
+var (
+	ticketStatuses   = []TicketStatus{TicketStatusOpen, TicketStatusInProgress, TicketStatusWaitingForCustomer, TicketStatusResolved, TicketStatusClosed}
+	ticketPriorities = []TicketPriority{TicketPriorityLow, TicketPriorityMedium, TicketPriorityHigh, TicketPriorityUrgent}
+	ticketTypes      = []TicketType{TicketTypeGeneral, TicketTypeTechnical, TicketTypeCourse, TicketTypeAssignment, TicketTypeGrading, TicketTypePlatform, TicketTypeContent}
+)
+
+// Valid reports whether s is one of the known ticket statuses.
+func (s TicketStatus) Valid() bool { return slices.Contains(ticketStatuses, s) }
+
+// Values lists the known ticket statuses.
+func (TicketStatus) Values() []string { return enumValues(ticketStatuses) }
+
+// Valid reports whether p is one of the known ticket priorities.
+func (p TicketPriority) Valid() bool { return slices.Contains(ticketPriorities, p) }
+
+// Values lists the known ticket priorities.
+func (TicketPriority) Values() []string { return enumValues(ticketPriorities) }
+
+// Valid reports whether t is one of the known ticket types.
+func (t TicketType) Valid() bool { return slices.Contains(ticketTypes, t) }
+
+// Values lists the known ticket types.
+func (TicketType) Values() []string { return enumValues(ticketTypes) }
+
+func enumValues[T ~string](values []T) []string {
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = string(value)
+	}
+	return names
+}
+
 type Ticket struct {
-	ID               uuid.UUID       `jsonb:"id" db:"id"`
-	TicketNumber     string          `jsonb:"ticketNumber" db:"ticketNumber"`
-	Title            string          `jsonb:"title" db:"title"`
-	Description      string          `jsonb:"description" db:"description"`
-	Status           TicketStatus    `jsonb:"status" db:"status"`
-	Priority         TicketPriority  `jsonb:"priority" db:"priority"`
-	Type             TicketType      `jsonb:"type" db:"type"`
-	StudentID        string          `jsonb:"studentId" db:"studentId"`
-	InstructorID     *string         `jsonb:"instructorId" db:"instructorId"`
-	CourseID         *string         `jsonb:"courseId" db:"courseId"`
-	CategoryID       *uuid.UUID      `jsonb:"categoryId" db:"categoryId"`
-	Metadata         JSONB           `jsonb:"metadata" db:"metadata"`
-	CreatedAt        time.Time       `jsonb:"createdAt" db:"createdAt"`
-	UpdatedAt        time.Time       `jsonb:"updatedAt" db:"updatedAt"`
-	ResolvedAt       *time.Time      `jsonb:"resolvedAt" db:"resolvedAt"`
-	ClosedAt         *time.Time      `jsonb:"closedAt" db:"closedAt"`
+	ID               uuid.UUID       `json:"id" db:"id"`
+	TicketNumber     string          `json:"ticketNumber" db:"ticketNumber"`
+	Title            string          `json:"title" db:"title"`
+	Description      string          `json:"description" db:"description"`
+	Status           TicketStatus    `json:"status" db:"status"`
+	Priority         TicketPriority  `json:"priority" db:"priority"`
+	Type             TicketType      `json:"type" db:"type"`
+	StudentID        string          `json:"studentId" db:"studentId"`
+	InstructorID     *string         `json:"instructorId" db:"instructorId"`
+	CourseID         *string         `json:"courseId" db:"courseId"`
+	CategoryID       *uuid.UUID      `json:"categoryId" db:"categoryId"`
+	Metadata         JSONB           `json:"metadata" db:"metadata"`
+	CreatedAt        time.Time       `json:"createdAt" db:"createdAt"`
+	UpdatedAt        time.Time       `json:"updatedAt" db:"updatedAt"`
+	ResolvedAt       *time.Time      `json:"resolvedAt" db:"resolvedAt"`
+	ClosedAt         *time.Time      `json:"closedAt" db:"closedAt"`
+	SLADueAt         *time.Time      `json:"slaDueAt" db:"slaDueAt"`
+	LastActivityAt   time.Time       `json:"lastActivityAt" db:"lastActivityAt"`
+	MergedIntoID     *uuid.UUID      `json:"mergedIntoId" db:"mergedIntoId"` // set on duplicates, points at the primary ticket
+	IsIncident       bool            `json:"isIncident" db:"isIncident"`
+	Tags             pq.StringArray  `json:"tags" db:"tags"`
+	Version          int             `json:"version" db:"version"` // bumped on every change; the ticket's ETag
+	DeletedAt        *time.Time      `json:"deletedAt,omitempty" db:"deletedAt"` // only set on deleted tickets awaiting restore
+	DeletedBy        *string         `json:"deletedBy,omitempty" db:"deletedBy"`
 	
 	// Related entities (populated via joins)
-	Category       *Category `jsonb:"category,omitempty"`
+	Category       *Category `json:"category,omitempty"`
 }
 
 type CreateTicketRequest struct {
-	Title       string         `jsonb:"title" validate:"required,max=255"`
-	Description string         `jsonb:"description" validate:"required"`
-	Priority    TicketPriority `jsonb:"priority" validate:"required"`
-	Type        TicketType     `jsonb:"type" validate:"required"`
-	CourseID    *string        `jsonb:"courseId"`
-	CategoryID  *uuid.UUID     `jsonb:"categoryId"`
-	Metadata    JSONB          `jsonb:"metadata"`
+	Title       string         `json:"title" validate:"required,max=255"`
+	Description string         `json:"description" validate:"required"`
+	Priority    TicketPriority `json:"priority" validate:"required"`
+	Type        TicketType     `json:"type" validate:"required"`
+	CourseID    *string        `json:"courseId"`
+	CategoryID  *uuid.UUID     `json:"categoryId"`
+	Metadata    JSONB          `json:"metadata"`
+	Fields      JSONB          `json:"fields"` // custom form field values by key, validated against the form definition
 }
 
 type UpdateTicketRequest struct {
-	Title           *string         `jsonb:"title" validate:"omitempty,max=255"`
-	Description     *string         `jsonb:"description"`
-	Status          *TicketStatus   `jsonb:"status"`
-	Priority        *TicketPriority `jsonb:"priority"`
-	Type            *TicketType     `jsonb:"type"`
-	InstructorID    *string         `jsonb:"instructorId"`
-	CourseID        *string         `jsonb:"courseId"`
-	CategoryID      *uuid.UUID      `jsonb:"categoryId"`
-	Metadata        JSONB           `jsonb:"metadata"`
+	Title           *string         `json:"title" validate:"omitempty,max=255"`
+	Description     *string         `json:"description"`
+	Status          *TicketStatus   `json:"status"`
+	Priority        *TicketPriority `json:"priority"`
+	Type            *TicketType     `json:"type"`
+	InstructorID    *string         `json:"instructorId"`
+	CourseID        *string         `json:"courseId"`
+	CategoryID      *uuid.UUID      `json:"categoryId"`
+	Metadata        JSONB           `json:"metadata"`
+}
+
+type AssignTicketRequest struct {
+	InstructorID string `json:"instructorId" validate:"required"`
 }
 
 type TicketComment struct {
-	ID         uuid.UUID `jsonb:"id" db:"id"`
-	TicketID   uuid.UUID `jsonb:"ticketId" db:"ticketId"`
-	UserID     string    `jsonb:"userId" db:"userId"`
-	Content    string    `jsonb:"content" db:"content"`
-	IsInternal bool      `jsonb:"isInternal" db:"isInternal"`
-	Metadata   JSONB     `jsonb:"metadata" db:"metadata"`
-	CreatedAt  time.Time `jsonb:"createdAt" db:"createdAt"`
-	UpdatedAt  time.Time `jsonb:"updatedAt" db:"updatedAt"`
+	ID         uuid.UUID  `json:"id" db:"id"`
+	TicketID   uuid.UUID  `json:"ticketId" db:"ticketId"`
+	UserID     string     `json:"userId" db:"userId"`
+	Content    string     `json:"content" db:"content"`
+	IsInternal bool       `json:"isInternal" db:"isInternal"`
+	Metadata   JSONB      `json:"metadata" db:"metadata"`
+	CreatedAt  time.Time  `json:"createdAt" db:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt" db:"updatedAt"`
+	DeletedAt  *time.Time `json:"deletedAt,omitempty" db:"deletedAt"` // only set on deleted comments awaiting restore
+	DeletedBy  *string    `json:"deletedBy,omitempty" db:"deletedBy"`
+}
+
+// SimilarTicket is a possible duplicate with its similarity score in [0, 1].
+type SimilarTicket struct {
+	Ticket
+	Score float64 `json:"score" db:"score"`
+}
+
+type SimilarTicketsRequest struct {
+	Title       string     `json:"title" validate:"required"`
+	Description string     `json:"description"`
+	Type        TicketType `json:"type"`
+	CourseID    *string    `json:"courseId"`
+}
+
+type MergeTicketsRequest struct {
+	DuplicateIDs []uuid.UUID `json:"duplicateIds" validate:"required,min=1"`
 }
 
 type CreateCommentRequest struct {
-	Content    string `jsonb:"content" validate:"required"`
-	IsInternal bool   `jsonb:"isInternal"`
-	Metadata   JSONB  `jsonb:"metadata"`
+	Content    string `json:"content" validate:"required"`
+	IsInternal bool   `json:"isInternal"`
+	Metadata   JSONB  `json:"metadata"`
 }
 
 type TicketHistory struct {
-	ID          uuid.UUID `jsonb:"id" db:"id"`
-	TicketID    uuid.UUID `jsonb:"ticketId" db:"ticketId"`
-	UserID      string    `jsonb:"userId" db:"userId"`
-	Action      string    `jsonb:"action" db:"action"`
-	OldValue    *string   `jsonb:"oldValue" db:"oldValue"`
-	NewValue    *string   `jsonb:"newValue" db:"newValue"`
-	Description *string   `jsonb:"description" db:"description"`
-	Metadata    JSONB     `jsonb:"metadata" db:"metadata"`
-	CreatedAt   time.Time `jsonb:"createdAt" db:"createdAt"`
+	ID          uuid.UUID `json:"id" db:"id"`
+	TicketID    uuid.UUID `json:"ticketId" db:"ticketId"`
+	UserID      string    `json:"userId" db:"userId"`
+	Action      string    `json:"action" db:"action"`
+	OldValue    *string   `json:"oldValue" db:"oldValue"`
+	NewValue    *string   `json:"newValue" db:"newValue"`
+	Description *string   `json:"description" db:"description"`
+	Metadata    JSONB     `json:"metadata" db:"metadata"`
+	CreatedAt   time.Time `json:"createdAt" db:"createdAt"`
 }
\ No newline at end of file
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WatcherRole string
+type WatcherSource string
+
+const (
+	WatcherRoleStudent           WatcherRole = "student"
+	WatcherRoleGuardian          WatcherRole = "guardian"
+	WatcherRoleInstructor        WatcherRole = "instructor"
+	WatcherRoleTeachingAssistant WatcherRole = "teachingAssistant"
+	WatcherRoleAdmin             WatcherRole = "admin"
+)
+
+const (
+	WatcherSourceCreator WatcherSource = "creator"
+	WatcherSourceManual  WatcherSource = "manual"
+	WatcherSourceSelf    WatcherSource = "self"
+	WatcherSourceComment WatcherSource = "comment"
+)
+
+var watcherRoles = []WatcherRole{WatcherRoleStudent, WatcherRoleGuardian, WatcherRoleInstructor, WatcherRoleTeachingAssistant, WatcherRoleAdmin}
+
+// Valid reports whether r is one of the known watcher roles.
+func (r WatcherRole) Valid() bool { return slices.Contains(watcherRoles, r) }
+
+// Values lists the known watcher roles.
+func (WatcherRole) Values() []string { return enumValues(watcherRoles) }
+
+// TicketWatcher is a user who receives notifications about a ticket without
+// being its student or assigned instructor, e.g. a TA, a second instructor or
+// a parent/guardian.
+type TicketWatcher struct {
+	ID        uuid.UUID     `json:"id" db:"id"`
+	TicketID  uuid.UUID     `json:"ticketId" db:"ticketId"`
+	UserID    string        `json:"userId" db:"userId"`
+	Email     string        `json:"email" db:"email"`
+	Role      WatcherRole   `json:"role" db:"role"`
+	Source    WatcherSource `json:"source" db:"source"`
+	AddedBy   string        `json:"addedBy" db:"addedBy"`
+	CreatedAt time.Time     `json:"createdAt" db:"createdAt"`
+}
+
+// CanSeeInternal reports whether the watcher may be notified about internal
+// comments. Only staff roles can; students and guardians cannot.
+func (w *TicketWatcher) CanSeeInternal() bool {
+	switch w.Role {
+	case WatcherRoleInstructor, WatcherRoleTeachingAssistant, WatcherRoleAdmin:
+		return true
+	}
+	return false
+}
+
+type AddWatcherRequest struct {
+	UserID string      `json:"userId" validate:"required"`
+	Email  string      `json:"email" validate:"required,email"`
+	Role   WatcherRole `json:"role" validate:"required"`
+}
\ No newline at end of file
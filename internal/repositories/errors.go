@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNotFound matches every NotFoundError, for callers that do not care
+	// which entity was missing.
+	ErrNotFound = errors.New("not found")
+
+	// ErrVersionConflict matches every VersionConflictError.
+	ErrVersionConflict = errors.New("version conflict")
+
+	// ErrInvalidTransition matches every TransitionError.
+	ErrInvalidTransition = errors.New("invalid status transition")
+
+	// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+	// or was issued for a different sort order.
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+	// ErrInvalidSort is returned when a listing is asked to sort by a field
+	// that is not whitelisted.
+	ErrInvalidSort = errors.New("unsupported sort field")
+
+	// ErrTicketMerged matches every TicketMergedError, and is returned as is
+	// when a ticket was merged concurrently with the merge being made.
+	ErrTicketMerged = errors.New("ticket has already been merged")
+
+	// ErrRelationExists is returned when two tickets are already linked with
+	// the requested relation type.
+	ErrRelationExists = errors.New("ticket relation already exists")
+
+	// ErrTicketHasParent is returned when linking a child ticket that already
+	// has a parent.
+	ErrTicketHasParent = errors.New("ticket already has a parent")
+
+	// ErrRelationCycle is returned when a parent link would make a ticket its
+	// own ancestor.
+	ErrRelationCycle = errors.New("ticket relation would create a cycle")
+
+	// ErrCategoryInUse is returned when deactivating or deleting a category
+	// that open tickets still use, without a category to move them to.
+	ErrCategoryInUse = errors.New("category is used by open tickets")
+
+	// ErrCategoryHasChildren is returned when deactivating or deleting a
+	// category that still has subcategories.
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+
+	// ErrCategoryExists is returned when a category name is already taken.
+	ErrCategoryExists = errors.New("category name already exists")
+
+	// ErrFormFieldExists is returned when a form field key is already used in
+	// the same category or ticket type.
+	ErrFormFieldExists = errors.New("form field key already exists")
+
+	// ErrTagExists is returned when a catalog tag name is already taken.
+	ErrTagExists = errors.New("tag already exists")
+
+	// ErrSavedViewExists is returned when the owner already has a saved view
+	// with the same name.
+	ErrSavedViewExists = errors.New("a saved view with this name already exists")
+
+	// ErrRoleExists is returned when a role name is already taken.
+	ErrRoleExists = errors.New("role already exists")
+
+	// ErrRoleAssigned is returned when a user already holds the role being
+	// assigned.
+	ErrRoleAssigned = errors.New("user already holds this role")
+)
+
+// NotFoundError is returned by lookups when the requested entity does not
+// exist. Entity is a lowercase noun such as "ticket" or "form field".
+type NotFoundError struct {
+	Entity string
+}
+
+func (e *NotFoundError) Error() string {
+	return strings.ToUpper(e.Entity[:1]) + e.Entity[1:] + " not found"
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// VersionConflictError is returned by updates guarded by a version when the
+// entity has been changed since that version was read.
+type VersionConflictError struct {
+	Entity  string
+	Version int // the version the update expected
+	Current int // the entity's current version, if known
+}
+
+func (e *VersionConflictError) Error() string {
+	return strings.ToUpper(e.Entity[:1]) + e.Entity[1:] + " has been modified since it was read; reload it and try again"
+}
+
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// TransitionError is returned when a ticket cannot move from its current
+// status to the requested one.
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("ticket cannot move from %s to %s", e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// TicketMergedError is returned when a merge involves a ticket that has
+// already been merged into another one.
+type TicketMergedError struct {
+	TicketNumber string
+}
+
+func (e *TicketMergedError) Error() string {
+	return fmt.Sprintf("Ticket #%s has already been merged", e.TicketNumber)
+}
+
+func (e *TicketMergedError) Is(target error) bool {
+	return target == ErrTicketMerged
+}
\ No newline at end of file
@@ -9,6 +9,7 @@ import (
 )
 
 type TicketFilters struct {
+	StudentID    *string
 	Status       *string
 	Priority     *string
 	Type         *string
@@ -18,27 +19,84 @@ type TicketFilters struct {
 	Search       *string
 	FromDate     *time.Time
 	ToDate       *time.Time
+	TagsAny      []string // tickets carrying at least one of these tags
+	TagsAll      []string // tickets carrying every one of these tags
+	Query        *TicketQuery
+	Scope        *TicketScope // limits results to the tickets an instructor may see
+}
+
+// TicketScope restricts ticket lookups to the tickets assigned to an
+// instructor or belonging to one of their courses.
+type TicketScope struct {
+	InstructorID string
+	CourseIDs    []string
 }
 
 type Pagination struct {
-	Page     int
-	PageSize int
-	OrderBy  string
-	OrderDir string // ASC or DESC
+	Limit        int
+	Cursor       string // opaque cursor from a previous page, empty for the first page
+	Sort         Sort   // defaults to DefaultTicketSort when empty
+	IncludeTotal bool   // also count all rows matching the filters
+}
+
+type TicketPage struct {
+	Tickets    []*models.Ticket
+	NextCursor string
+	PrevCursor string
+	Total      *int64
+}
+
+type SimilarTicketQuery struct {
+	Title       string
+	Description string
+	Type        *string
+	CourseID    *string
+	StudentID   *string      // only consider this student's tickets
+	ExcludeID   *uuid.UUID   // the ticket being compared, when it already exists
+	OpenOnly    bool         // skip resolved and closed tickets
+	Scope       *TicketScope // only consider tickets an instructor may see
+	MinScore    float64
+	Limit       int
+}
+
+// ArchiveFilters select archived tickets. Scope limits them like
+// TicketFilters.Scope.
+type ArchiveFilters struct {
+	StudentID    *string
+	CourseID     *string
+	TicketNumber *string
+	Scope        *TicketScope
+	Limit        int
+}
+
+// MacroApplication is the outcome of applying a macro to a ticket, saved
+// atomically by TicketRepository.ApplyMacro.
+type MacroApplication struct {
+	Ticket    *models.Ticket        // with the macro's field changes already applied
+	Comment   *models.TicketComment // nil when the macro has no reply text
+	AddTags   []*models.TicketHistory // a tagAdded entry per tag, saved only if the tag is new
+	History   []*models.TicketHistory
+	AppliedBy string
 }
 
 type TicketRepository interface {
 	Create(ctx context.Context, ticket *models.Ticket) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Ticket, error)
-	GetByStudentID(ctx context.Context, studentID string, filters TicketFilters) ([]*models.Ticket, error)
-	GetByCourseID(ctx context.Context, courseID string, filters TicketFilters) ([]*models.Ticket, error)
-	GetByInstructorID(ctx context.Context, instructorID string, filters TicketFilters) ([]*models.Ticket, error)
+	GetByStudentID(ctx context.Context, studentID string, filters TicketFilters, sort Sort) ([]*models.Ticket, error)
+	GetByCourseID(ctx context.Context, courseID string, filters TicketFilters, sort Sort) ([]*models.Ticket, error)
+	GetByInstructorID(ctx context.Context, instructorID string, filters TicketFilters, sort Sort) ([]*models.Ticket, error)
 	Update(ctx context.Context, ticket *models.Ticket) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, filters TicketFilters, pagination Pagination) ([]*models.Ticket, int64, error)
+	Delete(ctx context.Context, id uuid.UUID, deletedBy string, at time.Time) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	GetDeleted(ctx context.Context, limit int) ([]*models.Ticket, error)
+	List(ctx context.Context, filters TicketFilters, pagination Pagination) (*TicketPage, error)
 	GetByTicketNumber(ctx context.Context, ticketNumber string) (*models.Ticket, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status string, updatedBy string) error
-	AssignInstructor(ctx context.Context, id uuid.UUID, instructorID string, updatedBy string) error
+	TouchActivity(ctx context.Context, id uuid.UUID, at time.Time) error
+	FindSimilar(ctx context.Context, query SimilarTicketQuery) ([]*models.SimilarTicket, error)
+	Merge(ctx context.Context, primaryID uuid.UUID, duplicateIDs []uuid.UUID, mergedBy string, at time.Time) error
+	SetIncident(ctx context.Context, id uuid.UUID, version int, isIncident bool) error
+	ResolveChildren(ctx context.Context, parentID uuid.UUID, resolvedBy string, at time.Time) ([]*models.Ticket, error)
+	ApplyMacro(ctx context.Context, application MacroApplication) error
 }
 
 type CommentRepository interface {
@@ -46,7 +104,9 @@ type CommentRepository interface {
 	GetByTicketID(ctx context.Context, ticketID uuid.UUID, includeInternal bool) ([]*models.TicketComment, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.TicketComment, error)
 	Update(ctx context.Context, comment *models.TicketComment) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, deletedBy string, at time.Time) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	GetDeletedByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*models.TicketComment, error)
 	GetCommentCount(ctx context.Context, ticketID uuid.UUID, includeInternal bool) (int64, error)
 }
 
@@ -58,6 +118,8 @@ type CategoryRepository interface {
 	Update(ctx context.Context, category *models.Category) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, isActive bool) error
+	CountOpenTickets(ctx context.Context, id uuid.UUID) (int64, error)
+	Deactivate(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID, remove bool, userID string, at time.Time) (int64, error)
 }
 
 type AttachmentRepository interface {
@@ -65,9 +127,6 @@ type AttachmentRepository interface {
 	GetByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*models.Attachment, error)
 	GetByCommentID(ctx context.Context, commentID uuid.UUID) ([]*models.Attachment, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Attachment, error)
-	Delete(ctx context.Context, id uuid.UUID) error
-	DeleteByTicketID(ctx context.Context, ticketID uuid.UUID) error
-	DeleteByCommentID(ctx context.Context, commentID uuid.UUID) error
 }
 
 type HistoryRepository interface {
@@ -78,10 +137,153 @@ type HistoryRepository interface {
 	GetChangeHistory(ctx context.Context, ticketID uuid.UUID, actionType *string) ([]*models.TicketHistory, error)
 }
 
+type SavedViewRepository interface {
+	Create(ctx context.Context, view *models.SavedView) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.SavedView, error)
+	GetVisible(ctx context.Context, userID string, teamIDs []string) ([]*models.SavedView, error)
+	Update(ctx context.Context, view *models.SavedView) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type WatcherRepository interface {
+	Create(ctx context.Context, watcher *models.TicketWatcher) error
+	GetByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*models.TicketWatcher, error)
+	GetByTicketAndUser(ctx context.Context, ticketID uuid.UUID, userID string) (*models.TicketWatcher, error)
+	Delete(ctx context.Context, ticketID uuid.UUID, userID string) error
+}
+
+type RelationRepository interface {
+	Create(ctx context.Context, relation *models.TicketRelation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.TicketRelation, error)
+	GetByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*models.TicketRelation, error)
+	GetChildren(ctx context.Context, parentID uuid.UUID, openOnly bool) ([]*models.Ticket, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type MacroRepository interface {
+	Create(ctx context.Context, macro *models.Macro) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Macro, error)
+	GetVisible(ctx context.Context, userID string, activeOnly bool) ([]*models.Macro, error)
+	Update(ctx context.Context, macro *models.Macro) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type TagRepository interface {
+	Create(ctx context.Context, tag *models.Tag) error
+	GetAll(ctx context.Context) ([]*models.Tag, error)
+	GetByName(ctx context.Context, name string) (*models.Tag, error)
+	Update(ctx context.Context, tag *models.Tag) error
+	Delete(ctx context.Context, name string) error
+	AddToTicket(ctx context.Context, ticketID uuid.UUID, tags []string, addedBy string, at time.Time) ([]string, error)
+	RemoveFromTicket(ctx context.Context, ticketID uuid.UUID, tag string, removedBy string, at time.Time) (bool, error)
+	GetCounts(ctx context.Context, fromDate, toDate *time.Time) ([]*models.TagCount, error)
+}
+
+type FormFieldRepository interface {
+	Create(ctx context.Context, field *models.FormField) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.FormField, error)
+	GetAll(ctx context.Context, activeOnly bool) ([]*models.FormField, error)
+	GetApplicable(ctx context.Context, ticketType string, categoryID *uuid.UUID) ([]*models.FormField, error)
+	Update(ctx context.Context, field *models.FormField) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// RevocationRepository stores revoked tokens and per-user revocation
+// cutoffs.
+type RevocationRepository interface {
+	RevokeToken(ctx context.Context, token *models.RevokedToken) error
+	RevokeUserTokens(ctx context.Context, revocation *models.UserTokenRevocation) error
+	GetActiveTokens(ctx context.Context, now time.Time) ([]*models.RevokedToken, error)
+	GetUserRevocations(ctx context.Context) ([]*models.UserTokenRevocation, error)
+	DeleteExpiredTokens(ctx context.Context, now time.Time) (int64, error)
+}
+
+// APIClientRepository stores service API clients and the audit trail of
+// requests made with their keys.
+type APIClientRepository interface {
+	Create(ctx context.Context, client *models.APIClient) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.APIClient, error)
+	GetByKeyPrefix(ctx context.Context, keyPrefix string) (*models.APIClient, error)
+	GetAll(ctx context.Context) ([]*models.APIClient, error)
+	Update(ctx context.Context, client *models.APIClient) error
+	RecordUsage(ctx context.Context, usage *models.APIClientUsage) error
+	GetUsage(ctx context.Context, clientID uuid.UUID, limit int) ([]*models.APIClientUsage, error)
+}
+
+// RoleRepository stores roles and the roles assigned to users.
+type RoleRepository interface {
+	Create(ctx context.Context, role *models.Role) error
+	GetByName(ctx context.Context, name string) (*models.Role, error)
+	GetAll(ctx context.Context) ([]*models.Role, error)
+	Update(ctx context.Context, role *models.Role) error
+	Delete(ctx context.Context, name string) error
+	CreateAssignment(ctx context.Context, assignment *models.RoleAssignment) error
+	GetAssignment(ctx context.Context, id uuid.UUID) (*models.RoleAssignment, error)
+	GetAssignments(ctx context.Context, userID *string) ([]*models.RoleAssignment, error)
+	DeleteAssignment(ctx context.Context, id uuid.UUID) error
+}
+
+// ImpersonationRepository stores admins' impersonation sessions and the
+// requests made within them.
+type ImpersonationRepository interface {
+	Create(ctx context.Context, session *models.ImpersonationSession) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ImpersonationSession, error)
+	GetRecent(ctx context.Context, adminID *string, limit int) ([]*models.ImpersonationSession, error)
+	End(ctx context.Context, id uuid.UUID, endedAt time.Time) error
+	RecordRequest(ctx context.Context, request *models.ImpersonatedRequest) error
+	GetRequests(ctx context.Context, sessionID uuid.UUID) ([]*models.ImpersonatedRequest, error)
+	// SaveClaims stores the claims of a user's latest token, replacing the
+	// previous ones.
+	SaveClaims(ctx context.Context, claims *models.UserClaims) error
+	GetClaims(ctx context.Context, userID string) (*models.UserClaims, error)
+}
+
+// RateLimitRepository stores token buckets shared by every instance.
+type RateLimitRepository interface {
+	// UpdateBucket passes the bucket with the given key to update, holding a
+	// lock on it, and saves the result.
+	UpdateBucket(ctx context.Context, key string, update func(bucket *models.RateLimitBucket)) error
+	DeleteFullBuckets(ctx context.Context, now time.Time) (int64, error)
+}
+
+// IdempotencyRepository stores requests sent with an Idempotency-Key and
+// their responses.
+type IdempotencyRepository interface {
+	Claim(ctx context.Context, record *models.IdempotencyKey) (bool, error)
+	Get(ctx context.Context, userID, key string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, record *models.IdempotencyKey) error
+	Release(ctx context.Context, userID, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// ArchiveRepository moves old closed tickets to the archive and reads them
+// back from it.
+type ArchiveRepository interface {
+	// ArchiveClosed archives up to limit tickets closed before closedBefore
+	// and returns how many it archived.
+	ArchiveClosed(ctx context.Context, closedBefore time.Time, limit int) (int64, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ArchivedTicket, error)
+	List(ctx context.Context, filters ArchiveFilters) ([]*models.ArchivedTicket, error)
+}
+
 type Repository struct {
 	Ticket     TicketRepository
 	Comment    CommentRepository
 	Category   CategoryRepository
 	Attachment AttachmentRepository
 	History    HistoryRepository
+	SavedView  SavedViewRepository
+	Watcher    WatcherRepository
+	Relation   RelationRepository
+	Macro      MacroRepository
+	Tag        TagRepository
+	FormField  FormFieldRepository
+	Revocation RevocationRepository
+	APIClient  APIClientRepository
+	Role       RoleRepository
+
+	Impersonation ImpersonationRepository
+	RateLimit     RateLimitRepository
+	Idempotency   IdempotencyRepository
+	Archive       ArchiveRepository
 }
\ No newline at end of file
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type apiClientRepository struct {
+	db *database.DB
+}
+
+func NewAPIClientRepository(db *database.DB) repositories.APIClientRepository {
+	return &apiClientRepository{db: db}
+}
+
+func (r *apiClientRepository) Create(ctx context.Context, client *models.APIClient) error {
+	query := `
+		INSERT INTO apiClients (
+			id, name, description, keyPrefix, keyHash, scopes,
+			isActive, createdBy, createdAt, updatedAt
+		) VALUES (
+			:id, :name, :description, :keyPrefix, :keyHash, :scopes,
+			:isActive, :createdBy, :createdAt, :updatedAt
+		)`
+	
+	_, err := r.db.NamedExecContext(ctx, query, client)
+	return err
+}
+
+func (r *apiClientRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.APIClient, error) {
+	return r.getOne(ctx, `WHERE id = $1`, id)
+}
+
+func (r *apiClientRepository) GetByKeyPrefix(ctx context.Context, keyPrefix string) (*models.APIClient, error) {
+	return r.getOne(ctx, `WHERE keyPrefix = $1`, keyPrefix)
+}
+
+func (r *apiClientRepository) getOne(ctx context.Context, where string, arg interface{}) (*models.APIClient, error) {
+	var client models.APIClient
+	query := `
+		SELECT 
+			id, name, description, keyPrefix, keyHash, scopes,
+			isActive, createdBy, createdAt, updatedAt, lastUsedAt
+		FROM apiClients 
+		` + where
+	
+	err := r.db.GetContext(ctx, &client, query, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "API client"}
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *apiClientRepository) GetAll(ctx context.Context) ([]*models.APIClient, error) {
+	query := `
+		SELECT 
+			id, name, description, keyPrefix, keyHash, scopes,
+			isActive, createdBy, createdAt, updatedAt, lastUsedAt
+		FROM apiClients 
+		ORDER BY name ASC`
+	
+	var clients []*models.APIClient
+	err := r.db.SelectContext(ctx, &clients, query)
+	return clients, err
+}
+
+// Update saves the client's settings and its key, so rotating a key is an
+// update with a new prefix and hash.
+func (r *apiClientRepository) Update(ctx context.Context, client *models.APIClient) error {
+	query := `
+		UPDATE apiClients SET 
+			name = :name,
+			description = :description,
+			keyPrefix = :keyPrefix,
+			keyHash = :keyHash,
+			scopes = :scopes,
+			isActive = :isActive,
+			updatedAt = :updatedAt
+		WHERE id = :id`
+	
+	_, err := r.db.NamedExecContext(ctx, query, client)
+	return err
+}
+
+// RecordUsage appends to the client's audit trail and bumps its lastUsedAt.
+func (r *apiClientRepository) RecordUsage(ctx context.Context, usage *models.APIClientUsage) error {
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO apiClientUsage (
+				id, clientId, method, path, scope, onBehalfOf,
+				status, requestId, remoteAddr, createdAt
+			) VALUES (
+				:id, :clientId, :method, :path, :scope, :onBehalfOf,
+				:status, :requestId, :remoteAddr, :createdAt
+			)`
+		if _, err := tx.NamedExecContext(ctx, query, usage); err != nil {
+			return err
+		}
+		
+		_, err := tx.ExecContext(ctx, `UPDATE apiClients SET lastUsedAt = $2 WHERE id = $1`, usage.ClientID, usage.CreatedAt)
+		return err
+	})
+}
+
+func (r *apiClientRepository) GetUsage(ctx context.Context, clientID uuid.UUID, limit int) ([]*models.APIClientUsage, error) {
+	query := `
+		SELECT 
+			id, clientId, method, path, scope, onBehalfOf,
+			status, requestId, remoteAddr, createdAt
+		FROM apiClientUsage 
+		WHERE clientId = $1
+		ORDER BY createdAt DESC
+		LIMIT $2`
+	
+	var usage []*models.APIClientUsage
+	err := r.db.SelectContext(ctx, &usage, query, clientID, limit)
+	return usage, err
+}
\ No newline at end of file
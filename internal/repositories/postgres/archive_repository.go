@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type archiveRepository struct {
+	db *database.DB
+}
+
+func NewArchiveRepository(db *database.DB) repositories.ArchiveRepository {
+	return &archiveRepository{db: db}
+}
+
+// archivedTicketRow is an archivedTickets row. The JSON columns hold
+// to_jsonb copies of the original rows, whose keys are the lower-cased
+// column names; encoding/json matches them to the models' camelCase fields
+// case-insensitively.
+type archivedTicketRow struct {
+	Ticket      []byte    `db:"ticket"`
+	Comments    []byte    `db:"comments"`
+	Attachments []byte    `db:"attachments"`
+	History     []byte    `db:"history"`
+	ArchivedAt  time.Time `db:"archivedAt"`
+}
+
+// ArchiveClosed copies each ticket, with its tags, comments (deleted ones
+// included), attachments and history, into archivedTickets and deletes it
+// from tickets in one statement; ON DELETE CASCADE then removes the copied
+// rows. Tickets being archived by another instance are skipped, and so are
+// tickets that duplicates were merged into, since deleting them would clear
+// the duplicates' redirect; they are archived once their duplicates are.
+func (r *archiveRepository) ArchiveClosed(ctx context.Context, closedBefore time.Time, limit int) (int64, error) {
+	query := `
+		WITH due AS (
+			SELECT * FROM tickets
+			WHERE status = 'closed' AND closedAt < $1 AND deletedAt IS NULL
+				AND NOT EXISTS (SELECT 1 FROM tickets d WHERE d.mergedIntoId = tickets.id)
+			ORDER BY closedAt
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		), archived AS (
+			INSERT INTO archivedTickets (
+				id, ticketNumber, studentId, instructorId, courseId, closedAt,
+				ticket, comments, attachments, history, archivedAt
+			)
+			SELECT
+				t.id, t.ticketNumber, t.studentId, t.instructorId, t.courseId, t.closedAt,
+				to_jsonb(t) || jsonb_build_object('tags', ARRAY(SELECT tag FROM ticketTags WHERE ticketId = t.id ORDER BY tag)),
+				COALESCE((SELECT jsonb_agg(to_jsonb(c) ORDER BY c.createdAt) FROM ticketComments c WHERE c.ticketId = t.id), '[]'),
+				COALESCE((SELECT jsonb_agg(to_jsonb(a) ORDER BY a.createdAt) FROM attachments a
+					WHERE a.ticketId = t.id OR a.commentId IN (SELECT id FROM ticketComments WHERE ticketId = t.id)), '[]'),
+				COALESCE((SELECT jsonb_agg(to_jsonb(h) ORDER BY h.createdAt) FROM ticketHistory h WHERE h.ticketId = t.id), '[]'),
+				$3
+			FROM due t
+			RETURNING id
+		)
+		DELETE FROM tickets WHERE id IN (SELECT id FROM archived)`
+	
+	result, err := r.db.ExecContext(ctx, query, closedBefore, limit, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *archiveRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ArchivedTicket, error) {
+	var row archivedTicketRow
+	query := `
+		SELECT ticket, comments, attachments, history, archivedAt
+		FROM archivedTickets
+		WHERE id = $1`
+	
+	err := r.db.GetContext(ctx, &row, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "archived ticket"}
+		}
+		return nil, err
+	}
+	
+	archived := &models.ArchivedTicket{ArchivedAt: row.ArchivedAt}
+	documents := []struct {
+		data   []byte
+		target interface{}
+	}{
+		{row.Ticket, &archived.Ticket},
+		{row.Comments, &archived.Comments},
+		{row.Attachments, &archived.Attachments},
+		{row.History, &archived.History},
+	}
+	for _, document := range documents {
+		if err := json.Unmarshal(document.data, document.target); err != nil {
+			return nil, fmt.Errorf("failed to decode archived ticket %s: %w", id, err)
+		}
+	}
+	return archived, nil
+}
+
+// List returns archived tickets without their comments, attachments and
+// history, most recently closed first.
+func (r *archiveRepository) List(ctx context.Context, filters repositories.ArchiveFilters) ([]*models.ArchivedTicket, error) {
+	builder := newWhereBuilder()
+	if filters.StudentID != nil {
+		builder.where("studentId = " + builder.arg(*filters.StudentID))
+	}
+	if filters.CourseID != nil {
+		builder.where("courseId = " + builder.arg(*filters.CourseID))
+	}
+	if filters.TicketNumber != nil {
+		builder.where("ticketNumber = " + builder.arg(*filters.TicketNumber))
+	}
+	builder.addScope(filters.Scope)
+	
+	baseQuery := `SELECT ticket, archivedAt FROM archivedTickets`
+	query := builder.apply(baseQuery) + fmt.Sprintf(" ORDER BY closedAt DESC LIMIT %d", repositories.NormalizeLimit(filters.Limit))
+	
+	var rows []*archivedTicketRow
+	if err := r.db.SelectContext(ctx, &rows, query, builder.args...); err != nil {
+		return nil, err
+	}
+	
+	archived := make([]*models.ArchivedTicket, len(rows))
+	for i, row := range rows {
+		archived[i] = &models.ArchivedTicket{ArchivedAt: row.ArchivedAt}
+		if err := json.Unmarshal(row.Ticket, &archived[i].Ticket); err != nil {
+			return nil, fmt.Errorf("failed to decode archived ticket: %w", err)
+		}
+	}
+	return archived, nil
+}
\ No newline at end of file
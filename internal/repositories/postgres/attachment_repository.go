@@ -32,67 +32,58 @@ func (r *attachmentRepository) Create(ctx context.Context, attachment *models.At
 	return err
 }
 
+// attachmentColumns are the columns of attachments a, for queries that join
+// the ticket or comment the attachment belongs to.
+const attachmentColumns = `
+	a.id, a.ticketId, a.commentId, a.fileName, a.fileUrl, a.fileType, a.fileSize,
+	a.metadata, a.uploadedBy, a.createdAt`
+
+// GetByID returns an attachment unless its ticket or comment is deleted.
 func (r *attachmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Attachment, error) {
 	var attachment models.Attachment
 	query := `
-		SELECT 
-			id, ticketId, commentId, fileName, fileUrl, fileType, fileSize,
-			metadata, uploadedBy, createdAt
-		FROM attachments 
-		WHERE id = $1`
+		SELECT ` + attachmentColumns + `
+		FROM attachments a
+		LEFT JOIN ticketComments c ON c.id = a.commentId
+		JOIN tickets t ON t.id = COALESCE(a.ticketId, c.ticketId)
+		WHERE a.id = $1 AND c.deletedAt IS NULL AND t.deletedAt IS NULL`
 	
 	err := r.db.GetContext(ctx, &attachment, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil, &repositories.NotFoundError{Entity: "attachment"}
 		}
 		return nil, err
 	}
 	return &attachment, nil
 }
 
+// GetByTicketID returns the attachments of a ticket that is not deleted.
 func (r *attachmentRepository) GetByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*models.Attachment, error) {
 	query := `
-		SELECT 
-			id, ticketId, commentId, fileName, fileUrl, fileType, fileSize,
-			metadata, uploadedBy, createdAt
-		FROM attachments 
-		WHERE ticketId = $1
-		ORDER BY createdAt ASC`
+		SELECT ` + attachmentColumns + `
+		FROM attachments a
+		JOIN tickets t ON t.id = a.ticketId
+		WHERE a.ticketId = $1 AND t.deletedAt IS NULL
+		ORDER BY a.createdAt ASC`
 	
 	var attachments []*models.Attachment
 	err := r.db.SelectContext(ctx, &attachments, query, ticketID)
 	return attachments, err
 }
 
+// GetByCommentID returns the attachments of a comment unless the comment or
+// its ticket is deleted.
 func (r *attachmentRepository) GetByCommentID(ctx context.Context, commentID uuid.UUID) ([]*models.Attachment, error) {
 	query := `
-		SELECT 
-			id, ticketId, commentId, fileName, fileUrl, fileType, fileSize,
-			metadata, uploadedBy, createdAt
-		FROM attachments 
-		WHERE commentId = $1
-		ORDER BY createdAt ASC`
+		SELECT ` + attachmentColumns + `
+		FROM attachments a
+		JOIN ticketComments c ON c.id = a.commentId
+		JOIN tickets t ON t.id = c.ticketId
+		WHERE a.commentId = $1 AND c.deletedAt IS NULL AND t.deletedAt IS NULL
+		ORDER BY a.createdAt ASC`
 	
 	var attachments []*models.Attachment
 	err := r.db.SelectContext(ctx, &attachments, query, commentID)
 	return attachments, err
-}
-
-func (r *attachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM attachments WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
-}
-
-func (r *attachmentRepository) DeleteByTicketID(ctx context.Context, ticketID uuid.UUID) error {
-	query := `DELETE FROM attachments WHERE ticketId = $1`
-	_, err := r.db.ExecContext(ctx, query, ticketID)
-	return err
-}
-
-func (r *attachmentRepository) DeleteByCommentID(ctx context.Context, commentID uuid.UUID) error {
-	query := `DELETE FROM attachments WHERE commentId = $1`
-	_, err := r.db.ExecContext(ctx, query, commentID)
-	return err
 }
\ No newline at end of file
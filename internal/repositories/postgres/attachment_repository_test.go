@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestAttachmentRepositorySkipsDeletedOwners(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewAttachmentRepository(db)
+	ticketID := uuid.New()
+	commentID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM attachments a JOIN tickets t ON t.id = a.ticketId WHERE a.ticketId = $1 AND t.deletedAt IS NULL")).
+		WithArgs(ticketID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM attachments a JOIN ticketComments c ON c.id = a.commentId JOIN tickets t ON t.id = c.ticketId WHERE a.commentId = $1 AND c.deletedAt IS NULL AND t.deletedAt IS NULL")).
+		WithArgs(commentID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := repo.GetByTicketID(context.Background(), ticketID); err != nil {
+		t.Fatalf("GetByTicketID returned error: %v", err)
+	}
+	if _, err := repo.GetByCommentID(context.Background(), commentID); err != nil {
+		t.Fatalf("GetByCommentID returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -3,8 +3,13 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"community-support-service/internal/database"
 	"community-support-service/internal/models"
 	"community-support-service/internal/repositories"
@@ -21,22 +26,22 @@ func NewCategoryRepository(db *database.DB) repositories.CategoryRepository {
 func (r *categoryRepository) Create(ctx context.Context, category *models.Category) error {
 	query := `
 		INSERT INTO categories (
-			id, name, description, type, isActive, metadata,
+			id, name, description, color, type, parentId, isActive,
 			createdAt, updatedAt
 		) VALUES (
-			:id, :name, :description, :type, :isActive, :metadata,
+			:id, :name, :description, :color, :type, :parentId, :isActive,
 			:createdAt, :updatedAt
 		)`
 	
 	_, err := r.db.NamedExecContext(ctx, query, category)
-	return err
+	return categoryError(err)
 }
 
 func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
 	var category models.Category
 	query := `
 		SELECT 
-			id, name, description, type, isActive, metadata,
+			id, name, description, color, type, parentId, isActive,
 			createdAt, updatedAt
 		FROM categories 
 		WHERE id = $1`
@@ -44,7 +49,7 @@ func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 	err := r.db.GetContext(ctx, &category, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil, &repositories.NotFoundError{Entity: "category"}
 		}
 		return nil, err
 	}
@@ -54,7 +59,7 @@ func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 func (r *categoryRepository) GetAll(ctx context.Context, activeOnly bool) ([]*models.Category, error) {
 	query := `
 		SELECT 
-			id, name, description, type, isActive, metadata,
+			id, name, description, color, type, parentId, isActive,
 			createdAt, updatedAt
 		FROM categories`
 	
@@ -73,7 +78,7 @@ func (r *categoryRepository) GetAll(ctx context.Context, activeOnly bool) ([]*mo
 func (r *categoryRepository) GetByType(ctx context.Context, categoryType string, activeOnly bool) ([]*models.Category, error) {
 	query := `
 		SELECT 
-			id, name, description, type, isActive, metadata,
+			id, name, description, color, type, parentId, isActive,
 			createdAt, updatedAt
 		FROM categories 
 		WHERE type = $1`
@@ -96,14 +101,15 @@ func (r *categoryRepository) Update(ctx context.Context, category *models.Catego
 		UPDATE categories SET 
 			name = :name,
 			description = :description,
+			color = :color,
 			type = :type,
+			parentId = :parentId,
 			isActive = :isActive,
-			metadata = :metadata,
 			updatedAt = :updatedAt
 		WHERE id = :id`
 	
 	_, err := r.db.NamedExecContext(ctx, query, category)
-	return err
+	return categoryError(err)
 }
 
 func (r *categoryRepository) UpdateStatus(ctx context.Context, id uuid.UUID, isActive bool) error {
@@ -121,4 +127,97 @@ func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM categories WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
+}
+
+func (r *categoryRepository) CountOpenTickets(ctx context.Context, id uuid.UUID) (int64, error) {
+	query := `
+		SELECT COUNT(*) FROM tickets 
+		WHERE categoryId = $1 AND status NOT IN ('resolved', 'closed') AND deletedAt IS NULL`
+	
+	var count int64
+	err := r.db.GetContext(ctx, &count, query, id)
+	return count, err
+}
+
+// Deactivate marks a category inactive, or deletes it when remove is set.
+// Open tickets using it are moved to reassignTo, each with a history entry;
+// without a reassignment target the call fails with ErrCategoryInUse while
+// any open ticket remains. Deleted tickets do not count, but are moved along
+// so they keep a valid category if restored. Categories with active
+// subcategories are refused.
+func (r *categoryRepository) Deactivate(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID, remove bool, userID string, at time.Time) (int64, error) {
+	var moved int64
+	err := r.db.WithTx(func(tx *sqlx.Tx) error {
+		var name string
+		if err := tx.GetContext(ctx, &name, `SELECT name FROM categories WHERE id = $1 FOR UPDATE`, id); err != nil {
+			return err
+		}
+		
+		var children int64
+		childQuery := `SELECT COUNT(*) FROM categories WHERE parentId = $1`
+		if !remove {
+			childQuery += ` AND isActive = true`
+		}
+		if err := tx.GetContext(ctx, &children, childQuery, id); err != nil {
+			return err
+		}
+		if children > 0 {
+			return repositories.ErrCategoryHasChildren
+		}
+		
+		if reassignTo == nil {
+			var open int64
+			err := tx.GetContext(ctx, &open, `
+				SELECT COUNT(*) FROM tickets 
+				WHERE categoryId = $1 AND status NOT IN ('resolved', 'closed') AND deletedAt IS NULL`, id)
+			if err != nil {
+				return err
+			}
+			if open > 0 {
+				return fmt.Errorf("%w: %d open tickets", repositories.ErrCategoryInUse, open)
+			}
+		} else {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO ticketHistory (id, ticketId, userId, action, oldValue, newValue, description, metadata, createdAt)
+				SELECT uuid_generate_v4(), t.id, $1, 'categoryChanged', $2, $3, $4, '{}', $5
+				FROM tickets t
+				WHERE t.categoryId = $6 AND t.status NOT IN ('resolved', 'closed')`,
+				userID, id.String(), reassignTo.String(), fmt.Sprintf("Category reassigned from %s on deactivation", name), at, id)
+			if err != nil {
+				return err
+			}
+			
+			result, err := tx.ExecContext(ctx, `
+				UPDATE tickets SET 
+					categoryId = $1,
+					updatedAt = $2,
+					version = version + 1
+				WHERE categoryId = $3 AND status NOT IN ('resolved', 'closed')`,
+				*reassignTo, at, id)
+			if err != nil {
+				return err
+			}
+			if moved, err = result.RowsAffected(); err != nil {
+				return err
+			}
+		}
+		
+		if remove {
+			_, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE categories SET isActive = false, updatedAt = $1 WHERE id = $2`, at, id)
+		return err
+	})
+	return moved, err
+}
+
+// categoryError maps a unique violation on the category name to
+// ErrCategoryExists.
+func categoryError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return repositories.ErrCategoryExists
+	}
+	return err
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"community-support-service/internal/database"
@@ -18,17 +19,17 @@ func NewCommentRepository(db *database.DB) repositories.CommentRepository {
 	return &commentRepository{db: db}
 }
 
+const insertCommentQuery = `
+	INSERT INTO ticketComments (
+		id, ticketId, userId, content, isInternal,
+		metadata, createdAt, updatedAt
+	) VALUES (
+		:id, :ticketId, :userId, :content, :isInternal,
+		:metadata, :createdAt, :updatedAt
+	)`
+
 func (r *commentRepository) Create(ctx context.Context, comment *models.TicketComment) error {
-	query := `
-		INSERT INTO ticketComments (
-			id, ticketId, commentText, isInternal, authorId, authorType,
-			metadata, createdAt, updatedAt
-		) VALUES (
-			:id, :ticketId, :commentText, :isInternal, :authorId, :authorType,
-			:metadata, :createdAt, :updatedAt
-		)`
-	
-	_, err := r.db.NamedExecContext(ctx, query, comment)
+	_, err := r.db.NamedExecContext(ctx, insertCommentQuery, comment)
 	return err
 }
 
@@ -36,15 +37,15 @@ func (r *commentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 	var comment models.TicketComment
 	query := `
 		SELECT 
-			id, ticketId, commentText, isInternal, authorId, authorType,
+			id, ticketId, userId, content, isInternal,
 			metadata, createdAt, updatedAt
 		FROM ticketComments 
-		WHERE id = $1`
+		WHERE id = $1 AND deletedAt IS NULL`
 	
 	err := r.db.GetContext(ctx, &comment, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil, &repositories.NotFoundError{Entity: "comment"}
 		}
 		return nil, err
 	}
@@ -54,10 +55,10 @@ func (r *commentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 func (r *commentRepository) GetByTicketID(ctx context.Context, ticketID uuid.UUID, includeInternal bool) ([]*models.TicketComment, error) {
 	query := `
 		SELECT 
-			id, ticketId, commentText, isInternal, authorId, authorType,
+			id, ticketId, userId, content, isInternal,
 			metadata, createdAt, updatedAt
 		FROM ticketComments 
-		WHERE ticketId = $1`
+		WHERE ticketId = $1 AND deletedAt IS NULL`
 	
 	args := []interface{}{ticketID}
 	
@@ -73,7 +74,7 @@ func (r *commentRepository) GetByTicketID(ctx context.Context, ticketID uuid.UUI
 }
 
 func (r *commentRepository) GetCommentCount(ctx context.Context, ticketID uuid.UUID, includeInternal bool) (int64, error) {
-	query := `SELECT COUNT(*) FROM ticketComments WHERE ticketId = $1`
+	query := `SELECT COUNT(*) FROM ticketComments WHERE ticketId = $1 AND deletedAt IS NULL`
 	args := []interface{}{ticketID}
 	
 	if !includeInternal {
@@ -88,18 +89,59 @@ func (r *commentRepository) GetCommentCount(ctx context.Context, ticketID uuid.U
 func (r *commentRepository) Update(ctx context.Context, comment *models.TicketComment) error {
 	query := `
 		UPDATE ticketComments SET 
-			commentText = :commentText,
+			content = :content,
 			isInternal = :isInternal,
 			metadata = :metadata,
 			updatedAt = :updatedAt
-		WHERE id = :id`
+		WHERE id = :id AND deletedAt IS NULL`
 	
 	_, err := r.db.NamedExecContext(ctx, query, comment)
 	return err
 }
 
-func (r *commentRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM ticketComments WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+// Delete marks the comment deleted, keeping it and its attachments until
+// restored.
+func (r *commentRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy string, at time.Time) error {
+	query := `
+		UPDATE ticketComments SET 
+			deletedAt = $1,
+			deletedBy = $2
+		WHERE id = $3 AND deletedAt IS NULL`
+	
+	result, err := r.db.ExecContext(ctx, query, at, deletedBy, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, "comment")
+}
+
+// Restore undoes Delete.
+func (r *commentRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE ticketComments SET 
+			deletedAt = NULL,
+			deletedBy = NULL
+		WHERE id = $1 AND deletedAt IS NOT NULL`
+	
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result, "deleted comment")
+}
+
+// GetDeletedByTicketID returns a ticket's deleted comments, internal ones
+// included.
+func (r *commentRepository) GetDeletedByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*models.TicketComment, error) {
+	query := `
+		SELECT 
+			id, ticketId, userId, content, isInternal,
+			metadata, createdAt, updatedAt, deletedAt, deletedBy
+		FROM ticketComments 
+		WHERE ticketId = $1 AND deletedAt IS NOT NULL
+		ORDER BY createdAt ASC`
+	
+	var comments []*models.TicketComment
+	err := r.db.SelectContext(ctx, &comments, query, ticketID)
+	return comments, err
 }
\ No newline at end of file
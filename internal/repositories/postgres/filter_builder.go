@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"community-support-service/internal/repositories"
+	"github.com/lib/pq"
+)
+
+// ticketQueryColumns whitelists the columns a ticket query may reference.
+// Field names from parsed queries are only ever looked up here, so user input
+// never reaches the SQL text.
+var ticketQueryColumns = map[string]string{
+	repositories.QueryFieldStatus:   "status",
+	repositories.QueryFieldPriority: "priority",
+	repositories.QueryFieldType:     "type",
+	repositories.QueryFieldCourse:   "courseId",
+	repositories.QueryFieldCategory: "categoryId",
+	repositories.QueryFieldAssigned: "instructorId",
+	repositories.QueryFieldStudent:  "studentId",
+	repositories.QueryFieldCreated:  "createdAt",
+	repositories.QueryFieldUpdated:  "updatedAt",
+}
+
+// whereBuilder accumulates parameterized conditions and their arguments.
+type whereBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+func newWhereBuilder(args ...interface{}) *whereBuilder {
+	return &whereBuilder{args: args}
+}
+
+// arg registers a bind parameter and returns its placeholder.
+func (b *whereBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *whereBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// apply appends the accumulated conditions to query as its WHERE clause.
+// query must not have a top-level WHERE of its own; conditions it always
+// needs are added to the builder instead.
+func (b *whereBuilder) apply(query string) string {
+	if len(b.conditions) == 0 {
+		return query
+	}
+	return query + " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// addTicketFilters translates TicketFilters, including any parsed query
+// expression, into conditions on the tickets table. Deleted tickets are
+// always left out.
+func (b *whereBuilder) addTicketFilters(filters repositories.TicketFilters) error {
+	b.where("deletedAt IS NULL")
+	if filters.StudentID != nil {
+		b.where("studentId = " + b.arg(*filters.StudentID))
+	}
+	if filters.Status != nil {
+		b.where("status = " + b.arg(*filters.Status))
+	}
+	if filters.Priority != nil {
+		b.where("priority = " + b.arg(*filters.Priority))
+	}
+	if filters.Type != nil {
+		b.where("type = " + b.arg(*filters.Type))
+	}
+	if filters.CategoryID != nil {
+		b.where("categoryId = " + b.arg(*filters.CategoryID))
+	}
+	if filters.CourseID != nil {
+		b.where("courseId = " + b.arg(*filters.CourseID))
+	}
+	if filters.InstructorID != nil {
+		b.where("instructorId = " + b.arg(*filters.InstructorID))
+	}
+	if filters.FromDate != nil {
+		b.where("createdAt >= " + b.arg(*filters.FromDate))
+	}
+	if filters.ToDate != nil {
+		b.where("createdAt <= " + b.arg(*filters.ToDate))
+	}
+	if len(filters.TagsAny) > 0 {
+		b.where(fmt.Sprintf("EXISTS (SELECT 1 FROM ticketTags tt WHERE tt.ticketId = tickets.id AND tt.tag = ANY(%s))", b.arg(pq.Array(filters.TagsAny))))
+	}
+	if len(filters.TagsAll) > 0 {
+		b.where(fmt.Sprintf("(SELECT COUNT(DISTINCT tt.tag) FROM ticketTags tt WHERE tt.ticketId = tickets.id AND tt.tag = ANY(%s)) = %s",
+			b.arg(pq.Array(filters.TagsAll)), b.arg(len(uniqueStrings(filters.TagsAll)))))
+	}
+	if filters.Search != nil && *filters.Search != "" {
+		b.addSearchTerm(*filters.Search)
+	}
+	b.addScope(filters.Scope)
+
+	if filters.Query == nil {
+		return nil
+	}
+	for _, term := range filters.Query.Terms {
+		b.addSearchTerm(term)
+	}
+	for _, condition := range filters.Query.Conditions {
+		if err := b.addQueryCondition(condition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addScope limits the query to the tickets within scope, if any.
+func (b *whereBuilder) addScope(scope *repositories.TicketScope) {
+	if scope == nil {
+		return
+	}
+	b.where(fmt.Sprintf("(instructorId = %s OR courseId = ANY(%s))", b.arg(scope.InstructorID), b.arg(pq.Array(scope.CourseIDs))))
+}
+
+func (b *whereBuilder) addSearchTerm(term string) {
+	placeholder := b.arg("%" + term + "%")
+	b.where(fmt.Sprintf("(title ILIKE %s OR description ILIKE %s OR ticketNumber ILIKE %s)", placeholder, placeholder, placeholder))
+}
+
+func (b *whereBuilder) addQueryCondition(condition repositories.QueryCondition) error {
+	if condition.Field == repositories.QueryFieldTag {
+		return b.addTagCondition(condition)
+	}
+
+	var column string
+	if condition.Field == repositories.QueryFieldMetadata {
+		column = "metadata->>" + b.arg(condition.Key)
+	} else {
+		var ok bool
+		column, ok = ticketQueryColumns[condition.Field]
+		if !ok {
+			return fmt.Errorf("unsupported query field %q", condition.Field)
+		}
+	}
+
+	switch condition.Op {
+	case repositories.QueryOpIn:
+		b.where(fmt.Sprintf("%s::text = ANY(%s)", column, b.arg(pq.Array(condition.Values))))
+	case repositories.QueryOpNotIn:
+		b.where(fmt.Sprintf("(%s IS NULL OR NOT (%s::text = ANY(%s)))", column, column, b.arg(pq.Array(condition.Values))))
+	case repositories.QueryOpIsNull:
+		b.where(column + " IS NULL")
+	case repositories.QueryOpNotNull:
+		b.where(column + " IS NOT NULL")
+	case repositories.QueryOpGt, repositories.QueryOpGte, repositories.QueryOpLt, repositories.QueryOpLte:
+		if condition.Time == nil {
+			return fmt.Errorf("comparison on %q requires a time value", condition.Field)
+		}
+		b.where(fmt.Sprintf("%s %s %s", column, condition.Op, b.arg(*condition.Time)))
+	default:
+		return fmt.Errorf("unsupported query operator %q", condition.Op)
+	}
+	return nil
+}
+
+// addTagCondition matches tickets carrying any of the listed tags, or none of
+// them for a negated term.
+func (b *whereBuilder) addTagCondition(condition repositories.QueryCondition) error {
+	exists := fmt.Sprintf("EXISTS (SELECT 1 FROM ticketTags tt WHERE tt.ticketId = tickets.id AND tt.tag = ANY(%s))", b.arg(pq.Array(condition.Values)))
+	switch condition.Op {
+	case repositories.QueryOpIn:
+		b.where(exists)
+	case repositories.QueryOpNotIn:
+		b.where("NOT " + exists)
+	default:
+		return fmt.Errorf("unsupported operator %q for tags", condition.Op)
+	}
+	return nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type formFieldRepository struct {
+	db *database.DB
+}
+
+func NewFormFieldRepository(db *database.DB) repositories.FormFieldRepository {
+	return &formFieldRepository{db: db}
+}
+
+func (r *formFieldRepository) Create(ctx context.Context, field *models.FormField) error {
+	query := `
+		INSERT INTO ticketFormFields (
+			id, key, label, fieldType, required, options, helpText,
+			categoryId, ticketType, sortOrder, isActive, createdAt, updatedAt
+		) VALUES (
+			:id, :key, :label, :fieldType, :required, :options, :helpText,
+			:categoryId, :ticketType, :sortOrder, :isActive, :createdAt, :updatedAt
+		)`
+	
+	_, err := r.db.NamedExecContext(ctx, query, field)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return repositories.ErrFormFieldExists
+	}
+	return err
+}
+
+func (r *formFieldRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.FormField, error) {
+	var field models.FormField
+	query := `
+		SELECT 
+			id, key, label, fieldType, required, options, helpText,
+			categoryId, ticketType, sortOrder, isActive, createdAt, updatedAt
+		FROM ticketFormFields 
+		WHERE id = $1`
+	
+	err := r.db.GetContext(ctx, &field, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "form field"}
+		}
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (r *formFieldRepository) GetAll(ctx context.Context, activeOnly bool) ([]*models.FormField, error) {
+	query := `
+		SELECT 
+			id, key, label, fieldType, required, options, helpText,
+			categoryId, ticketType, sortOrder, isActive, createdAt, updatedAt
+		FROM ticketFormFields`
+	
+	if activeOnly {
+		query += " WHERE isActive = true"
+	}
+	
+	query += " ORDER BY sortOrder ASC, label ASC"
+	
+	var fields []*models.FormField
+	err := r.db.SelectContext(ctx, &fields, query)
+	return fields, err
+}
+
+// GetApplicable returns the active fields for a ticket of the given type in
+// the given category, including fields inherited from parent categories.
+// Category fields come first, nearest category first, then type fields, so
+// callers keeping the first field per key get the most specific definition.
+func (r *formFieldRepository) GetApplicable(ctx context.Context, ticketType string, categoryID *uuid.UUID) ([]*models.FormField, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT id, parentId, 0 AS depth FROM categories WHERE id = $2
+			UNION ALL
+			SELECT c.id, c.parentId, chain.depth + 1 FROM categories c
+			JOIN chain ON c.id = chain.parentId
+			WHERE chain.depth < 32
+		)
+		SELECT 
+			f.id, f.key, f.label, f.fieldType, f.required, f.options, f.helpText,
+			f.categoryId, f.ticketType, f.sortOrder, f.isActive, f.createdAt, f.updatedAt
+		FROM ticketFormFields f
+		LEFT JOIN chain ON chain.id = f.categoryId
+		WHERE f.isActive = true AND (f.ticketType = $1 OR chain.id IS NOT NULL)
+		ORDER BY COALESCE(chain.depth, 2147483647) ASC, f.sortOrder ASC, f.label ASC`
+	
+	var fields []*models.FormField
+	err := r.db.SelectContext(ctx, &fields, query, ticketType, categoryID)
+	return fields, err
+}
+
+func (r *formFieldRepository) Update(ctx context.Context, field *models.FormField) error {
+	query := `
+		UPDATE ticketFormFields SET 
+			label = :label,
+			required = :required,
+			options = :options,
+			helpText = :helpText,
+			sortOrder = :sortOrder,
+			isActive = :isActive,
+			updatedAt = :updatedAt
+		WHERE id = :id`
+	
+	_, err := r.db.NamedExecContext(ctx, query, field)
+	return err
+}
+
+func (r *formFieldRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM ticketFormFields WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
\ No newline at end of file
@@ -18,17 +18,17 @@ func NewHistoryRepository(db *database.DB) repositories.HistoryRepository {
 	return &historyRepository{db: db}
 }
 
+const insertHistoryQuery = `
+	INSERT INTO ticketHistory (
+		id, ticketId, userId, action, description,
+		oldValue, newValue, metadata, createdAt
+	) VALUES (
+		:id, :ticketId, :userId, :action, :description,
+		:oldValue, :newValue, :metadata, :createdAt
+	)`
+
 func (r *historyRepository) Create(ctx context.Context, history *models.TicketHistory) error {
-	query := `
-		INSERT INTO ticketHistory (
-			id, ticketId, actionType, description, changedBy, changedByType,
-			oldValue, newValue, metadata, createdAt
-		) VALUES (
-			:id, :ticketId, :actionType, :description, :changedBy, :changedByType,
-			:oldValue, :newValue, :metadata, :createdAt
-		)`
-	
-	_, err := r.db.NamedExecContext(ctx, query, history)
+	_, err := r.db.NamedExecContext(ctx, insertHistoryQuery, history)
 	return err
 }
 
@@ -36,7 +36,7 @@ func (r *historyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 	var history models.TicketHistory
 	query := `
 		SELECT 
-			id, ticketId, actionType, description, changedBy, changedByType,
+			id, ticketId, userId, action, description,
 			oldValue, newValue, metadata, createdAt
 		FROM ticketHistory 
 		WHERE id = $1`
@@ -44,7 +44,7 @@ func (r *historyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 	err := r.db.GetContext(ctx, &history, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil, &repositories.NotFoundError{Entity: "history entry"}
 		}
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func (r *historyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 func (r *historyRepository) GetByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*models.TicketHistory, error) {
 	query := `
 		SELECT 
-			id, ticketId, actionType, description, changedBy, changedByType,
+			id, ticketId, userId, action, description,
 			oldValue, newValue, metadata, createdAt
 		FROM ticketHistory 
 		WHERE ticketId = $1
@@ -68,7 +68,7 @@ func (r *historyRepository) GetByTicketID(ctx context.Context, ticketID uuid.UUI
 func (r *historyRepository) GetChangeHistory(ctx context.Context, ticketID uuid.UUID, actionType *string) ([]*models.TicketHistory, error) {
 	query := `
 		SELECT 
-			id, ticketId, actionType, description, changedBy, changedByType,
+			id, ticketId, userId, action, description,
 			oldValue, newValue, metadata, createdAt
 		FROM ticketHistory 
 		WHERE ticketId = $1`
@@ -76,7 +76,7 @@ func (r *historyRepository) GetChangeHistory(ctx context.Context, ticketID uuid.
 	args := []interface{}{ticketID}
 	
 	if actionType != nil {
-		query += " AND actionType = $2"
+		query += " AND action = $2"
 		args = append(args, *actionType)
 	}
 	
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type idempotencyRepository struct {
+	db *database.DB
+}
+
+func NewIdempotencyRepository(db *database.DB) repositories.IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Claim stores record unless the user already holds the key, taking over
+// keys that have expired. It reports whether record was stored.
+func (r *idempotencyRepository) Claim(ctx context.Context, record *models.IdempotencyKey) (bool, error) {
+	query := `
+		INSERT INTO idempotencyKeys (
+			userId, key, fingerprint, createdAt, expiresAt
+		) VALUES (
+			:userId, :key, :fingerprint, :createdAt, :expiresAt
+		)
+		ON CONFLICT (userId, key) DO UPDATE SET 
+			fingerprint = EXCLUDED.fingerprint,
+			status = NULL,
+			contentType = NULL,
+			etag = NULL,
+			body = NULL,
+			createdAt = EXCLUDED.createdAt,
+			expiresAt = EXCLUDED.expiresAt
+		WHERE idempotencyKeys.expiresAt <= EXCLUDED.createdAt`
+	
+	result, err := r.db.NamedExecContext(ctx, query, record)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows == 1, err
+}
+
+func (r *idempotencyRepository) Get(ctx context.Context, userID, key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	query := `
+		SELECT 
+			userId, key, fingerprint, status, contentType, etag, body,
+			createdAt, expiresAt
+		FROM idempotencyKeys 
+		WHERE userId = $1 AND key = $2`
+	
+	err := r.db.GetContext(ctx, &record, query, userID, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "idempotency key"}
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Complete stores the response of a claimed key and how long to keep it.
+func (r *idempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyKey) error {
+	query := `
+		UPDATE idempotencyKeys SET 
+			status = :status,
+			contentType = :contentType,
+			etag = :etag,
+			body = :body,
+			expiresAt = :expiresAt
+		WHERE userId = :userId AND key = :key AND fingerprint = :fingerprint`
+	
+	_, err := r.db.NamedExecContext(ctx, query, record)
+	return err
+}
+
+// Release drops a key that is still in flight, so the request can be retried.
+func (r *idempotencyRepository) Release(ctx context.Context, userID, key string) error {
+	query := `DELETE FROM idempotencyKeys WHERE userId = $1 AND key = $2 AND status IS NULL`
+	
+	_, err := r.db.ExecContext(ctx, query, userID, key)
+	return err
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotencyKeys WHERE expiresAt <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
\ No newline at end of file
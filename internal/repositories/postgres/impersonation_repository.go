@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type impersonationRepository struct {
+	db *database.DB
+}
+
+func NewImpersonationRepository(db *database.DB) repositories.ImpersonationRepository {
+	return &impersonationRepository{db: db}
+}
+
+func (r *impersonationRepository) Create(ctx context.Context, session *models.ImpersonationSession) error {
+	query := `
+		INSERT INTO impersonationSessions (
+			id, adminId, userId, reason, email, teams, courses,
+			expiresAt, createdAt
+		) VALUES (
+			:id, :adminId, :userId, :reason, :email, :teams, :courses,
+			:expiresAt, :createdAt
+		)`
+	
+	_, err := r.db.NamedExecContext(ctx, query, session)
+	return err
+}
+
+func (r *impersonationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ImpersonationSession, error) {
+	var session models.ImpersonationSession
+	query := `
+		SELECT id, adminId, userId, reason, email, teams, courses, expiresAt, endedAt, createdAt
+		FROM impersonationSessions 
+		WHERE id = $1`
+	
+	err := r.db.GetContext(ctx, &session, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "Impersonation session"}
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetRecent returns the most recent sessions, newest first, optionally only
+// those started by adminID.
+func (r *impersonationRepository) GetRecent(ctx context.Context, adminID *string, limit int) ([]*models.ImpersonationSession, error) {
+	query := `
+		SELECT id, adminId, userId, reason, email, teams, courses, expiresAt, endedAt, createdAt
+		FROM impersonationSessions 
+		WHERE ($1::text IS NULL OR adminId = $1)
+		ORDER BY createdAt DESC
+		LIMIT $2`
+	
+	var sessions []*models.ImpersonationSession
+	err := r.db.SelectContext(ctx, &sessions, query, adminID, limit)
+	return sessions, err
+}
+
+// End marks a session as ended at endedAt unless it has already ended.
+func (r *impersonationRepository) End(ctx context.Context, id uuid.UUID, endedAt time.Time) error {
+	query := `UPDATE impersonationSessions SET endedAt = $2 WHERE id = $1 AND endedAt IS NULL`
+	
+	_, err := r.db.ExecContext(ctx, query, id, endedAt)
+	return err
+}
+
+func (r *impersonationRepository) RecordRequest(ctx context.Context, request *models.ImpersonatedRequest) error {
+	query := `
+		INSERT INTO impersonatedRequests (
+			id, sessionId, adminId, userId, method, path,
+			status, requestId, createdAt
+		) VALUES (
+			:id, :sessionId, :adminId, :userId, :method, :path,
+			:status, :requestId, :createdAt
+		)`
+	
+	_, err := r.db.NamedExecContext(ctx, query, request)
+	return err
+}
+
+func (r *impersonationRepository) GetRequests(ctx context.Context, sessionID uuid.UUID) ([]*models.ImpersonatedRequest, error) {
+	query := `
+		SELECT 
+			id, sessionId, adminId, userId, method, path,
+			status, requestId, createdAt
+		FROM impersonatedRequests 
+		WHERE sessionId = $1
+		ORDER BY createdAt ASC`
+	
+	var requests []*models.ImpersonatedRequest
+	err := r.db.SelectContext(ctx, &requests, query, sessionID)
+	return requests, err
+}
+
+func (r *impersonationRepository) SaveClaims(ctx context.Context, claims *models.UserClaims) error {
+	query := `
+		INSERT INTO userClaims (userId, email, teams, courses, updatedAt)
+		VALUES (:userId, :email, :teams, :courses, :updatedAt)
+		ON CONFLICT (userId) DO UPDATE SET
+			email = EXCLUDED.email,
+			teams = EXCLUDED.teams,
+			courses = EXCLUDED.courses,
+			updatedAt = EXCLUDED.updatedAt`
+	
+	_, err := r.db.NamedExecContext(ctx, query, claims)
+	return err
+}
+
+func (r *impersonationRepository) GetClaims(ctx context.Context, userID string) (*models.UserClaims, error) {
+	var claims models.UserClaims
+	query := `
+		SELECT userId, email, teams, courses, updatedAt
+		FROM userClaims
+		WHERE userId = $1`
+	
+	err := r.db.GetContext(ctx, &claims, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "user claims"}
+		}
+		return nil, err
+	}
+	return &claims, nil
+}
\ No newline at end of file
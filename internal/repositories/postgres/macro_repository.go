@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type macroRepository struct {
+	db *database.DB
+}
+
+func NewMacroRepository(db *database.DB) repositories.MacroRepository {
+	return &macroRepository{db: db}
+}
+
+func (r *macroRepository) Create(ctx context.Context, macro *models.Macro) error {
+	query := `
+		INSERT INTO macros (
+			id, name, content, isInternal, actions, ownerId, createdBy,
+			isActive, createdAt, updatedAt
+		) VALUES (
+			:id, :name, :content, :isInternal, :actions, :ownerId, :createdBy,
+			:isActive, :createdAt, :updatedAt
+		)`
+	
+	_, err := r.db.NamedExecContext(ctx, query, macro)
+	return err
+}
+
+func (r *macroRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Macro, error) {
+	var macro models.Macro
+	query := `
+		SELECT 
+			id, name, content, isInternal, actions, ownerId, createdBy,
+			isActive, createdAt, updatedAt
+		FROM macros 
+		WHERE id = $1`
+	
+	err := r.db.GetContext(ctx, &macro, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "macro"}
+		}
+		return nil, err
+	}
+	return &macro, nil
+}
+
+// GetVisible returns the shared macros plus the user's personal ones.
+func (r *macroRepository) GetVisible(ctx context.Context, userID string, activeOnly bool) ([]*models.Macro, error) {
+	query := `
+		SELECT 
+			id, name, content, isInternal, actions, ownerId, createdBy,
+			isActive, createdAt, updatedAt
+		FROM macros 
+		WHERE (ownerId IS NULL OR ownerId = $1)`
+	if activeOnly {
+		query += ` AND isActive = true`
+	}
+	query += ` ORDER BY ownerId NULLS FIRST, name ASC`
+	
+	var macros []*models.Macro
+	err := r.db.SelectContext(ctx, &macros, query, userID)
+	return macros, err
+}
+
+func (r *macroRepository) Update(ctx context.Context, macro *models.Macro) error {
+	query := `
+		UPDATE macros SET 
+			name = :name,
+			content = :content,
+			isInternal = :isInternal,
+			actions = :actions,
+			isActive = :isActive,
+			updatedAt = :updatedAt
+		WHERE id = :id`
+	
+	_, err := r.db.NamedExecContext(ctx, query, macro)
+	return err
+}
+
+func (r *macroRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM macros WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type rateLimitRepository struct {
+	db *database.DB
+}
+
+func NewRateLimitRepository(db *database.DB) repositories.RateLimitRepository {
+	return &rateLimitRepository{db: db}
+}
+
+// UpdateBucket locks the bucket, creating it unused if needed, so that
+// instances sharing it take tokens one after another.
+func (r *rateLimitRepository) UpdateBucket(ctx context.Context, key string, update func(bucket *models.RateLimitBucket)) error {
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		insert := `
+			INSERT INTO rateLimitBuckets (key, tokens, updatedAt, fullAt)
+			VALUES ($1, 0, $2, $2)
+			ON CONFLICT (key) DO NOTHING`
+		if _, err := tx.ExecContext(ctx, insert, key, time.Time{}); err != nil {
+			return err
+		}
+		
+		var bucket models.RateLimitBucket
+		query := `SELECT key, tokens, updatedAt, fullAt FROM rateLimitBuckets WHERE key = $1 FOR UPDATE`
+		if err := tx.GetContext(ctx, &bucket, query, key); err != nil {
+			return err
+		}
+		
+		update(&bucket)
+		
+		save := `
+			UPDATE rateLimitBuckets SET 
+				tokens = :tokens,
+				updatedAt = :updatedAt,
+				fullAt = :fullAt
+			WHERE key = :key`
+		_, err := tx.NamedExecContext(ctx, save, &bucket)
+		return err
+	})
+}
+
+// DeleteFullBuckets drops buckets that have refilled by now; they behave
+// exactly like buckets that were never used.
+func (r *rateLimitRepository) DeleteFullBuckets(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM rateLimitBuckets WHERE fullAt < $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
\ No newline at end of file
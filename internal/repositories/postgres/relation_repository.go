@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type relationRepository struct {
+	db *database.DB
+}
+
+func NewRelationRepository(db *database.DB) repositories.RelationRepository {
+	return &relationRepository{db: db}
+}
+
+// Create links two tickets. Parent links are checked against the existing
+// hierarchy so that a ticket can never become its own ancestor.
+func (r *relationRepository) Create(ctx context.Context, relation *models.TicketRelation) error {
+	err := r.db.WithTx(func(tx *sqlx.Tx) error {
+		if relation.Type == models.RelationTypeParent {
+			// Serialize parent links so concurrent requests cannot build a
+			// cycle between them
+			if _, err := tx.ExecContext(ctx, `LOCK TABLE ticketRelations IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+				return err
+			}
+			
+			var cycle bool
+			err := tx.GetContext(ctx, &cycle, `
+				WITH RECURSIVE ancestors AS (
+					SELECT sourceTicketId AS id FROM ticketRelations
+					WHERE targetTicketId = $1 AND type = 'parent'
+					UNION
+					SELECT rel.sourceTicketId FROM ticketRelations rel
+					JOIN ancestors a ON rel.targetTicketId = a.id
+					WHERE rel.type = 'parent'
+				)
+				SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)`,
+				relation.SourceTicketID, relation.TargetTicketID)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return repositories.ErrRelationCycle
+			}
+		}
+		
+		query := `
+			INSERT INTO ticketRelations (
+				id, sourceTicketId, targetTicketId, type, createdBy, createdAt
+			) VALUES (
+				:id, :sourceTicketId, :targetTicketId, :type, :createdBy, :createdAt
+			)`
+		_, err := tx.NamedExecContext(ctx, query, relation)
+		return err
+	})
+	
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		if pqErr.Constraint == "uq_ticket_relations_single_parent" {
+			return repositories.ErrTicketHasParent
+		}
+		return repositories.ErrRelationExists
+	}
+	return err
+}
+
+// liveRelations are the relations whose tickets are both not deleted.
+const liveRelations = `
+	SELECT 
+		rel.id, rel.sourceTicketId, rel.targetTicketId, rel.type, rel.createdBy, rel.createdAt
+	FROM ticketRelations rel
+	JOIN tickets source ON source.id = rel.sourceTicketId AND source.deletedAt IS NULL
+	JOIN tickets target ON target.id = rel.targetTicketId AND target.deletedAt IS NULL`
+
+// GetByID returns a relation unless one of its tickets is deleted.
+func (r *relationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.TicketRelation, error) {
+	var relation models.TicketRelation
+	query := liveRelations + `
+		WHERE rel.id = $1`
+	
+	err := r.db.GetContext(ctx, &relation, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "relation"}
+		}
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// GetByTicketID returns relations in either direction involving the ticket,
+// leaving out those with a deleted ticket at the other end.
+func (r *relationRepository) GetByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*models.TicketRelation, error) {
+	query := liveRelations + `
+		WHERE rel.sourceTicketId = $1 OR rel.targetTicketId = $1
+		ORDER BY rel.createdAt ASC`
+	
+	var relations []*models.TicketRelation
+	err := r.db.SelectContext(ctx, &relations, query, ticketID)
+	return relations, err
+}
+
+func (r *relationRepository) GetChildren(ctx context.Context, parentID uuid.UUID, openOnly bool) ([]*models.Ticket, error) {
+	query := `
+		SELECT 
+			t.id, t.ticketNumber, t.title, t.description, t.status, t.priority, t.type,
+			t.studentId, t.courseId, t.instructorId, t.categoryId, t.metadata,
+			t.createdAt, t.updatedAt, t.resolvedAt, t.closedAt, t.slaDueAt, t.lastActivityAt,
+			t.mergedIntoId, t.isIncident, t.version
+		FROM ticketRelations rel
+		JOIN tickets t ON t.id = rel.targetTicketId
+		WHERE rel.sourceTicketId = $1 AND rel.type = 'parent' AND t.deletedAt IS NULL`
+	if openOnly {
+		query += ` AND t.status NOT IN ('resolved', 'closed')`
+	}
+	query += ` ORDER BY t.createdAt ASC`
+	
+	var tickets []*models.Ticket
+	err := r.db.SelectContext(ctx, &tickets, query, parentID)
+	return tickets, err
+}
+
+func (r *relationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM ticketRelations WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestRelationRepositorySkipsDeletedTickets(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewRelationRepository(db)
+	ticketID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("JOIN tickets source ON source.id = rel.sourceTicketId AND source.deletedAt IS NULL JOIN tickets target ON target.id = rel.targetTicketId AND target.deletedAt IS NULL WHERE rel.sourceTicketId = $1 OR rel.targetTicketId = $1")).
+		WithArgs(ticketID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := repo.GetByTicketID(context.Background(), ticketID); err != nil {
+		t.Fatalf("GetByTicketID returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
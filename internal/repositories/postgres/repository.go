@@ -12,5 +12,19 @@ func NewRepository(db *database.DB) *repositories.Repository {
 		Category:   NewCategoryRepository(db),
 		Attachment: NewAttachmentRepository(db),
 		History:    NewHistoryRepository(db),
+		SavedView:  NewSavedViewRepository(db),
+		Watcher:    NewWatcherRepository(db),
+		Relation:   NewRelationRepository(db),
+		Macro:      NewMacroRepository(db),
+		Tag:        NewTagRepository(db),
+		FormField:  NewFormFieldRepository(db),
+		Revocation: NewRevocationRepository(db),
+		APIClient:  NewAPIClientRepository(db),
+		Role:       NewRoleRepository(db),
+
+		Impersonation: NewImpersonationRepository(db),
+		RateLimit:     NewRateLimitRepository(db),
+		Idempotency:   NewIdempotencyRepository(db),
+		Archive:       NewArchiveRepository(db),
 	}
 }
\ No newline at end of file
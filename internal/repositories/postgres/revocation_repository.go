@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type revocationRepository struct {
+	db *database.DB
+}
+
+func NewRevocationRepository(db *database.DB) repositories.RevocationRepository {
+	return &revocationRepository{db: db}
+}
+
+func (r *revocationRepository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	query := `
+		INSERT INTO revokedTokens (
+			jti, expiresAt, reason, revokedBy, revokedAt
+		) VALUES (
+			:jti, :expiresAt, :reason, :revokedBy, :revokedAt
+		)
+		ON CONFLICT (jti) DO NOTHING`
+	
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	return err
+}
+
+// RevokeUserTokens records the user's cutoff. A later cutoff replaces an
+// earlier one, never the other way round.
+func (r *revocationRepository) RevokeUserTokens(ctx context.Context, revocation *models.UserTokenRevocation) error {
+	query := `
+		INSERT INTO userTokenRevocations (
+			userId, revokedBefore, reason, revokedBy, updatedAt
+		) VALUES (
+			:userId, :revokedBefore, :reason, :revokedBy, :updatedAt
+		)
+		ON CONFLICT (userId) DO UPDATE SET
+			revokedBefore = EXCLUDED.revokedBefore,
+			reason = EXCLUDED.reason,
+			revokedBy = EXCLUDED.revokedBy,
+			updatedAt = EXCLUDED.updatedAt
+		WHERE userTokenRevocations.revokedBefore < EXCLUDED.revokedBefore`
+	
+	_, err := r.db.NamedExecContext(ctx, query, revocation)
+	return err
+}
+
+func (r *revocationRepository) GetActiveTokens(ctx context.Context, now time.Time) ([]*models.RevokedToken, error) {
+	query := `
+		SELECT 
+			jti, expiresAt, reason, revokedBy, revokedAt
+		FROM revokedTokens 
+		WHERE expiresAt > $1`
+	
+	var tokens []*models.RevokedToken
+	err := r.db.SelectContext(ctx, &tokens, query, now)
+	return tokens, err
+}
+
+func (r *revocationRepository) GetUserRevocations(ctx context.Context) ([]*models.UserTokenRevocation, error) {
+	query := `
+		SELECT 
+			userId, revokedBefore, reason, revokedBy, updatedAt
+		FROM userTokenRevocations`
+	
+	var revocations []*models.UserTokenRevocation
+	err := r.db.SelectContext(ctx, &revocations, query)
+	return revocations, err
+}
+
+func (r *revocationRepository) DeleteExpiredTokens(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM revokedTokens WHERE expiresAt <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
\ No newline at end of file
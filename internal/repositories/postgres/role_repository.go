@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type roleRepository struct {
+	db *database.DB
+}
+
+func NewRoleRepository(db *database.DB) repositories.RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *models.Role) error {
+	query := `
+		INSERT INTO roles (
+			name, description, permissions, courseScoped, builtIn, createdAt, updatedAt
+		) VALUES (
+			:name, :description, :permissions, :courseScoped, :builtIn, :createdAt, :updatedAt
+		)`
+	
+	_, err := r.db.NamedExecContext(ctx, query, role)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return repositories.ErrRoleExists
+	}
+	return err
+}
+
+func (r *roleRepository) GetByName(ctx context.Context, name string) (*models.Role, error) {
+	var role models.Role
+	query := `
+		SELECT 
+			name, description, permissions, courseScoped, builtIn, createdAt, updatedAt
+		FROM roles 
+		WHERE name = $1`
+	
+	err := r.db.GetContext(ctx, &role, query, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "role"}
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) GetAll(ctx context.Context) ([]*models.Role, error) {
+	query := `
+		SELECT 
+			name, description, permissions, courseScoped, builtIn, createdAt, updatedAt
+		FROM roles 
+		ORDER BY name ASC`
+	
+	var roles []*models.Role
+	err := r.db.SelectContext(ctx, &roles, query)
+	return roles, err
+}
+
+func (r *roleRepository) Update(ctx context.Context, role *models.Role) error {
+	query := `
+		UPDATE roles SET 
+			description = :description,
+			permissions = :permissions,
+			courseScoped = :courseScoped,
+			updatedAt = :updatedAt
+		WHERE name = :name`
+	
+	_, err := r.db.NamedExecContext(ctx, query, role)
+	return err
+}
+
+// Delete removes a role along with its assignments.
+func (r *roleRepository) Delete(ctx context.Context, name string) error {
+	query := `DELETE FROM roles WHERE name = $1`
+	_, err := r.db.ExecContext(ctx, query, name)
+	return err
+}
+
+func (r *roleRepository) CreateAssignment(ctx context.Context, assignment *models.RoleAssignment) error {
+	query := `
+		INSERT INTO roleAssignments (
+			id, userId, role, courseIds, createdBy, createdAt
+		) VALUES (
+			:id, :userId, :role, :courseIds, :createdBy, :createdAt
+		)`
+	
+	_, err := r.db.NamedExecContext(ctx, query, assignment)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return repositories.ErrRoleAssigned
+	}
+	return err
+}
+
+func (r *roleRepository) GetAssignment(ctx context.Context, id uuid.UUID) (*models.RoleAssignment, error) {
+	var assignment models.RoleAssignment
+	query := `
+		SELECT 
+			id, userId, role, courseIds, createdBy, createdAt
+		FROM roleAssignments 
+		WHERE id = $1`
+	
+	err := r.db.GetContext(ctx, &assignment, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "role assignment"}
+		}
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// GetAssignments returns the assignments of one user, or of every user when
+// userID is nil.
+func (r *roleRepository) GetAssignments(ctx context.Context, userID *string) ([]*models.RoleAssignment, error) {
+	query := `
+		SELECT 
+			id, userId, role, courseIds, createdBy, createdAt
+		FROM roleAssignments 
+		WHERE $1::text IS NULL OR userId = $1
+		ORDER BY userId ASC, role ASC`
+	
+	var assignments []*models.RoleAssignment
+	err := r.db.SelectContext(ctx, &assignments, query, userID)
+	return assignments, err
+}
+
+func (r *roleRepository) DeleteAssignment(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM roleAssignments WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type savedViewRepository struct {
+	db *database.DB
+}
+
+func NewSavedViewRepository(db *database.DB) repositories.SavedViewRepository {
+	return &savedViewRepository{db: db}
+}
+
+// Create stores a view, failing with ErrSavedViewExists when the owner already
+// has a view of that name.
+func (r *savedViewRepository) Create(ctx context.Context, view *models.SavedView) error {
+	query := `
+		INSERT INTO savedViews (
+			id, name, query, ownerId, teamId, createdAt, updatedAt
+		) VALUES (
+			:id, :name, :query, :ownerId, :teamId, :createdAt, :updatedAt
+		)`
+	
+	_, err := r.db.NamedExecContext(ctx, query, view)
+	return savedViewError(err)
+}
+
+func (r *savedViewRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SavedView, error) {
+	var view models.SavedView
+	query := `
+		SELECT 
+			id, name, query, ownerId, teamId, createdAt, updatedAt
+		FROM savedViews 
+		WHERE id = $1`
+	
+	err := r.db.GetContext(ctx, &view, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "saved view"}
+		}
+		return nil, err
+	}
+	return &view, nil
+}
+
+// GetVisible returns the user's own views plus views shared with any of the
+// given teams.
+func (r *savedViewRepository) GetVisible(ctx context.Context, userID string, teamIDs []string) ([]*models.SavedView, error) {
+	query := `
+		SELECT 
+			id, name, query, ownerId, teamId, createdAt, updatedAt
+		FROM savedViews 
+		WHERE ownerId = $1 OR teamId = ANY($2)
+		ORDER BY name ASC`
+	
+	var views []*models.SavedView
+	err := r.db.SelectContext(ctx, &views, query, userID, pq.Array(teamIDs))
+	return views, err
+}
+
+// Update saves a view, failing with ErrSavedViewExists when it is renamed to
+// the name of another of the owner's views.
+func (r *savedViewRepository) Update(ctx context.Context, view *models.SavedView) error {
+	query := `
+		UPDATE savedViews SET 
+			name = :name,
+			query = :query,
+			teamId = :teamId,
+			updatedAt = :updatedAt
+		WHERE id = :id`
+	
+	_, err := r.db.NamedExecContext(ctx, query, view)
+	return savedViewError(err)
+}
+
+func (r *savedViewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM savedViews WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// savedViewError maps a violation of the unique view name per owner to
+// ErrSavedViewExists.
+func savedViewError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return repositories.ErrSavedViewExists
+	}
+	return err
+}
\ No newline at end of file
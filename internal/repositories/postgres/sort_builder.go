@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+// ticketSortExpressions maps each whitelisted sort field to the SQL expression
+// it orders by. Priority sorts by weight (see repositories.PriorityWeight) and
+// tickets without an SLA deadline sort after all others.
+var ticketSortExpressions = map[repositories.SortField]string{
+	repositories.SortFieldCreatedAt:    "createdAt",
+	repositories.SortFieldUpdatedAt:    "updatedAt",
+	repositories.SortFieldLastActivity: "lastActivityAt",
+	repositories.SortFieldSLADueAt:     "COALESCE(slaDueAt, 'infinity'::timestamptz)",
+	repositories.SortFieldPriority:     "CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END",
+	repositories.SortFieldTicketNumber: "ticketNumber",
+}
+
+// ticketOrderBy renders an ORDER BY clause for sort with id as the final
+// tiebreaker, following the direction of the last key. reverse flips every
+// key, which is used to scan backwards from a cursor.
+func ticketOrderBy(sort repositories.Sort, reverse bool) (string, error) {
+	parts := make([]string, 0, len(sort)+1)
+	for _, key := range sort {
+		expr, ok := ticketSortExpressions[key.Field]
+		if !ok {
+			return "", fmt.Errorf("%w %q", repositories.ErrInvalidSort, key.Field)
+		}
+		parts = append(parts, expr+" "+sortDirection(key.Descending != reverse))
+	}
+	parts = append(parts, "id "+sortDirection(idDescending(sort) != reverse))
+	return " ORDER BY " + strings.Join(parts, ", "), nil
+}
+
+// addTicketKeyset restricts the query to rows strictly after the cursor in
+// sort order, or strictly before it when the cursor pages backwards. Keys may
+// mix directions, so the bound is expanded into
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... OR (k1 = v1 AND ... AND id > vid).
+func (b *whereBuilder) addTicketKeyset(sort repositories.Sort, cursor *repositories.Cursor) error {
+	if len(cursor.Values) != len(sort) {
+		return repositories.ErrInvalidCursor
+	}
+
+	exprs := make([]string, 0, len(sort)+1)
+	descending := make([]bool, 0, len(sort)+1)
+	for _, key := range sort {
+		expr, ok := ticketSortExpressions[key.Field]
+		if !ok {
+			return fmt.Errorf("%w %q", repositories.ErrInvalidSort, key.Field)
+		}
+		exprs = append(exprs, expr)
+		descending = append(descending, key.Descending)
+	}
+	exprs = append(exprs, "id")
+	descending = append(descending, idDescending(sort))
+
+	placeholders := make([]string, len(exprs))
+	for i, value := range cursor.Values {
+		placeholders[i] = b.arg(value)
+	}
+	placeholders[len(exprs)-1] = b.arg(cursor.ID)
+
+	var branches []string
+	for i := range exprs {
+		var terms []string
+		for j := 0; j < i; j++ {
+			terms = append(terms, fmt.Sprintf("%s = %s", exprs[j], placeholders[j]))
+		}
+		comparison := ">"
+		if descending[i] != cursor.Backward {
+			comparison = "<"
+		}
+		terms = append(terms, fmt.Sprintf("%s %s %s", exprs[i], comparison, placeholders[i]))
+		branches = append(branches, "("+strings.Join(terms, " AND ")+")")
+	}
+
+	b.where("(" + strings.Join(branches, " OR ") + ")")
+	return nil
+}
+
+// ticketSortValues returns the cursor values of a ticket for each sort key, in
+// the same form the sort expressions produce.
+func ticketSortValues(ticket *models.Ticket, sort repositories.Sort) []string {
+	values := make([]string, len(sort))
+	for i, key := range sort {
+		switch key.Field {
+		case repositories.SortFieldUpdatedAt:
+			values[i] = ticket.UpdatedAt.Format(time.RFC3339Nano)
+		case repositories.SortFieldLastActivity:
+			values[i] = ticket.LastActivityAt.Format(time.RFC3339Nano)
+		case repositories.SortFieldSLADueAt:
+			if ticket.SLADueAt == nil {
+				values[i] = "infinity"
+			} else {
+				values[i] = ticket.SLADueAt.Format(time.RFC3339Nano)
+			}
+		case repositories.SortFieldPriority:
+			values[i] = strconv.Itoa(repositories.PriorityWeight(ticket.Priority))
+		case repositories.SortFieldTicketNumber:
+			values[i] = ticket.TicketNumber
+		default:
+			values[i] = ticket.CreatedAt.Format(time.RFC3339Nano)
+		}
+	}
+	return values
+}
+
+func idDescending(sort repositories.Sort) bool {
+	return len(sort) > 0 && sort[len(sort)-1].Descending
+}
+
+func sortDirection(descending bool) string {
+	if descending {
+		return "DESC"
+	}
+	return "ASC"
+}
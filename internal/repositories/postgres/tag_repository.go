@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type tagRepository struct {
+	db *database.DB
+}
+
+func NewTagRepository(db *database.DB) repositories.TagRepository {
+	return &tagRepository{db: db}
+}
+
+// Create adds a tag to the catalog, failing with ErrTagExists when the name is
+// taken.
+func (r *tagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	query := `
+		INSERT INTO tags (
+			name, color, description, createdAt, updatedAt
+		) VALUES (
+			:name, :color, :description, :createdAt, :updatedAt
+		)`
+	
+	_, err := r.db.NamedExecContext(ctx, query, tag)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return repositories.ErrTagExists
+	}
+	return err
+}
+
+func (r *tagRepository) GetAll(ctx context.Context) ([]*models.Tag, error) {
+	query := `
+		SELECT 
+			name, color, description, createdAt, updatedAt
+		FROM tags 
+		ORDER BY name ASC`
+	
+	var tags []*models.Tag
+	err := r.db.SelectContext(ctx, &tags, query)
+	return tags, err
+}
+
+func (r *tagRepository) GetByName(ctx context.Context, name string) (*models.Tag, error) {
+	var tag models.Tag
+	query := `
+		SELECT 
+			name, color, description, createdAt, updatedAt
+		FROM tags 
+		WHERE name = $1`
+	
+	err := r.db.GetContext(ctx, &tag, query, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "tag"}
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	query := `
+		UPDATE tags SET 
+			color = :color,
+			description = :description,
+			updatedAt = :updatedAt
+		WHERE name = :name`
+	
+	_, err := r.db.NamedExecContext(ctx, query, tag)
+	return err
+}
+
+// Delete removes a tag from the catalog. Tickets keep the tag; it just loses
+// its color.
+func (r *tagRepository) Delete(ctx context.Context, name string) error {
+	query := `DELETE FROM tags WHERE name = $1`
+	_, err := r.db.ExecContext(ctx, query, name)
+	return err
+}
+
+// AddToTicket tags a ticket and records a history entry for each tag it did
+// not already carry. It returns the newly added tags.
+func (r *tagRepository) AddToTicket(ctx context.Context, ticketID uuid.UUID, tags []string, addedBy string, at time.Time) ([]string, error) {
+	var added []string
+	err := r.db.WithTx(func(tx *sqlx.Tx) error {
+		for _, tag := range tags {
+			var inserted string
+			err := tx.GetContext(ctx, &inserted, `
+				INSERT INTO ticketTags (ticketId, tag, addedBy, createdAt)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (ticketId, tag) DO NOTHING
+				RETURNING tag`, ticketID, tag, addedBy, at)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			
+			if err := insertTagHistory(ctx, tx, ticketID, "tagAdded", nil, &inserted, addedBy, at); err != nil {
+				return err
+			}
+			added = append(added, inserted)
+		}
+		
+		if len(added) == 0 {
+			return nil
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE tickets SET updatedAt = $1, version = version + 1 WHERE id = $2`, at, ticketID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// RemoveFromTicket removes a tag from a ticket, recording the change, and
+// reports whether the ticket carried it.
+func (r *tagRepository) RemoveFromTicket(ctx context.Context, ticketID uuid.UUID, tag string, removedBy string, at time.Time) (bool, error) {
+	removed := false
+	err := r.db.WithTx(func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM ticketTags WHERE ticketId = $1 AND tag = $2`, ticketID, tag)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil || rows == 0 {
+			return err
+		}
+		removed = true
+		
+		if err := insertTagHistory(ctx, tx, ticketID, "tagRemoved", &tag, nil, removedBy, at); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE tickets SET updatedAt = $1, version = version + 1 WHERE id = $2`, at, ticketID)
+		return err
+	})
+	return removed, err
+}
+
+// GetCounts counts tickets per tag, optionally limited to tickets created in
+// a date range. Tags are ordered by usage, most used first.
+func (r *tagRepository) GetCounts(ctx context.Context, fromDate, toDate *time.Time) ([]*models.TagCount, error) {
+	baseQuery := `
+		SELECT 
+			tt.tag AS name, c.color,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE tickets.status NOT IN ('resolved', 'closed')) AS open
+		FROM ticketTags tt
+		JOIN tickets ON tickets.id = tt.ticketId
+		LEFT JOIN tags c ON c.name = tt.tag`
+	
+	// Qualified explicitly since every joined table has a createdAt column
+	builder := newWhereBuilder()
+	builder.where("tickets.deletedAt IS NULL")
+	if fromDate != nil {
+		builder.where("tickets.createdAt >= " + builder.arg(*fromDate))
+	}
+	if toDate != nil {
+		builder.where("tickets.createdAt <= " + builder.arg(*toDate))
+	}
+	query := builder.apply(baseQuery) + ` GROUP BY tt.tag, c.color ORDER BY total DESC, name ASC`
+	
+	var counts []*models.TagCount
+	err := r.db.SelectContext(ctx, &counts, query, builder.args...)
+	return counts, err
+}
+
+func insertTagHistory(ctx context.Context, tx *sqlx.Tx, ticketID uuid.UUID, action string, oldValue, newValue *string, userID string, at time.Time) error {
+	history := &models.TicketHistory{
+		ID:        uuid.New(),
+		TicketID:  ticketID,
+		UserID:    userID,
+		Action:    action,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		Metadata:  make(map[string]interface{}),
+		CreatedAt: at,
+	}
+	_, err := tx.NamedExecContext(ctx, insertHistoryQuery, history)
+	return err
+}
\ No newline at end of file
@@ -4,10 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"community-support-service/internal/database"
 	"community-support-service/internal/models"
 	"community-support-service/internal/repositories"
@@ -26,11 +26,11 @@ func (r *ticketRepository) Create(ctx context.Context, ticket *models.Ticket) er
 		INSERT INTO tickets (
 			id, ticketNumber, title, description, status, priority, type,
 			studentId, courseId, instructorId, categoryId, metadata,
-			createdAt, updatedAt
+			createdAt, updatedAt, slaDueAt, lastActivityAt, version
 		) VALUES (
 			:id, :ticketNumber, :title, :description, :status, :priority, :type,
 			:studentId, :courseId, :instructorId, :categoryId, :metadata,
-			:createdAt, :updatedAt
+			:createdAt, :updatedAt, :slaDueAt, :lastActivityAt, :version
 		)`
 	
 	_, err := r.db.NamedExecContext(ctx, query, ticket)
@@ -43,14 +43,16 @@ func (r *ticketRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.T
 		SELECT 
 			id, ticketNumber, title, description, status, priority, type,
 			studentId, courseId, instructorId, categoryId, metadata,
-			createdAt, updatedAt
+			createdAt, updatedAt, resolvedAt, closedAt, slaDueAt, lastActivityAt,
+			mergedIntoId, isIncident, version,
+			ARRAY(SELECT tag FROM ticketTags WHERE ticketId = tickets.id ORDER BY tag) AS tags
 		FROM tickets 
-		WHERE id = $1`
+		WHERE id = $1 AND deletedAt IS NULL`
 	
 	err := r.db.GetContext(ctx, &ticket, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil, &repositories.NotFoundError{Entity: "ticket"}
 		}
 		return nil, err
 	}
@@ -63,224 +65,560 @@ func (r *ticketRepository) GetByTicketNumber(ctx context.Context, ticketNumber s
 		SELECT 
 			id, ticketNumber, title, description, status, priority, type,
 			studentId, courseId, instructorId, categoryId, metadata,
-			createdAt, updatedAt
+			createdAt, updatedAt, resolvedAt, closedAt, slaDueAt, lastActivityAt,
+			mergedIntoId, isIncident, version,
+			ARRAY(SELECT tag FROM ticketTags WHERE ticketId = tickets.id ORDER BY tag) AS tags
 		FROM tickets 
-		WHERE ticketNumber = $1`
+		WHERE ticketNumber = $1 AND deletedAt IS NULL`
 	
 	err := r.db.GetContext(ctx, &ticket, query, ticketNumber)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil, &repositories.NotFoundError{Entity: "ticket"}
 		}
 		return nil, err
 	}
 	return &ticket, nil
 }
 
-func (r *ticketRepository) GetByStudentID(ctx context.Context, studentID string, filters repositories.TicketFilters) ([]*models.Ticket, error) {
+func (r *ticketRepository) GetByStudentID(ctx context.Context, studentID string, filters repositories.TicketFilters, sort repositories.Sort) ([]*models.Ticket, error) {
 	baseQuery := `
 		SELECT 
 			id, ticketNumber, title, description, status, priority, type,
 			studentId, courseId, instructorId, categoryId, metadata,
-			createdAt, updatedAt
-		FROM tickets 
-		WHERE studentId = $1`
+			createdAt, updatedAt, resolvedAt, closedAt, slaDueAt, lastActivityAt,
+			mergedIntoId, isIncident, version,
+			ARRAY(SELECT tag FROM ticketTags WHERE ticketId = tickets.id ORDER BY tag) AS tags
+		FROM tickets`
 	
-	args := []interface{}{studentID}
-	query, args := r.applyFilters(baseQuery, filters, args)
+	builder := newWhereBuilder()
+	builder.where("studentId = " + builder.arg(studentID))
+	if err := builder.addTicketFilters(filters); err != nil {
+		return nil, err
+	}
 	
-	var tickets []*models.Ticket
-	err := r.db.SelectContext(ctx, &tickets, query, args...)
-	return tickets, err
+	return r.selectSorted(ctx, builder.apply(baseQuery), sort, builder.args)
 }
 
-func (r *ticketRepository) GetByCourseID(ctx context.Context, courseID string, filters repositories.TicketFilters) ([]*models.Ticket, error) {
+func (r *ticketRepository) GetByCourseID(ctx context.Context, courseID string, filters repositories.TicketFilters, sort repositories.Sort) ([]*models.Ticket, error) {
 	baseQuery := `
 		SELECT 
 			id, ticketNumber, title, description, status, priority, type,
 			studentId, courseId, instructorId, categoryId, metadata,
-			createdAt, updatedAt
-		FROM tickets 
-		WHERE courseId = $1`
+			createdAt, updatedAt, resolvedAt, closedAt, slaDueAt, lastActivityAt,
+			mergedIntoId, isIncident, version,
+			ARRAY(SELECT tag FROM ticketTags WHERE ticketId = tickets.id ORDER BY tag) AS tags
+		FROM tickets`
 	
-	args := []interface{}{courseID}
-	query, args := r.applyFilters(baseQuery, filters, args)
+	builder := newWhereBuilder()
+	builder.where("courseId = " + builder.arg(courseID))
+	if err := builder.addTicketFilters(filters); err != nil {
+		return nil, err
+	}
 	
-	var tickets []*models.Ticket
-	err := r.db.SelectContext(ctx, &tickets, query, args...)
-	return tickets, err
+	return r.selectSorted(ctx, builder.apply(baseQuery), sort, builder.args)
 }
 
-func (r *ticketRepository) GetByInstructorID(ctx context.Context, instructorID string, filters repositories.TicketFilters) ([]*models.Ticket, error) {
+func (r *ticketRepository) GetByInstructorID(ctx context.Context, instructorID string, filters repositories.TicketFilters, sort repositories.Sort) ([]*models.Ticket, error) {
 	baseQuery := `
 		SELECT 
 			id, ticketNumber, title, description, status, priority, type,
 			studentId, courseId, instructorId, categoryId, metadata,
-			createdAt, updatedAt
-		FROM tickets 
-		WHERE instructorId = $1`
+			createdAt, updatedAt, resolvedAt, closedAt, slaDueAt, lastActivityAt,
+			mergedIntoId, isIncident, version,
+			ARRAY(SELECT tag FROM ticketTags WHERE ticketId = tickets.id ORDER BY tag) AS tags
+		FROM tickets`
 	
-	args := []interface{}{instructorID}
-	query, args := r.applyFilters(baseQuery, filters, args)
+	builder := newWhereBuilder()
+	builder.where("instructorId = " + builder.arg(instructorID))
+	if err := builder.addTicketFilters(filters); err != nil {
+		return nil, err
+	}
 	
-	var tickets []*models.Ticket
-	err := r.db.SelectContext(ctx, &tickets, query, args...)
-	return tickets, err
+	return r.selectSorted(ctx, builder.apply(baseQuery), sort, builder.args)
 }
 
-func (r *ticketRepository) List(ctx context.Context, filters repositories.TicketFilters, pagination repositories.Pagination) ([]*models.Ticket, int64, error) {
+func (r *ticketRepository) List(ctx context.Context, filters repositories.TicketFilters, pagination repositories.Pagination) (*repositories.TicketPage, error) {
 	baseQuery := `
 		SELECT 
 			id, ticketNumber, title, description, status, priority, type,
 			studentId, courseId, instructorId, categoryId, metadata,
-			createdAt, updatedAt
+			createdAt, updatedAt, resolvedAt, closedAt, slaDueAt, lastActivityAt,
+			mergedIntoId, isIncident, version,
+			ARRAY(SELECT tag FROM ticketTags WHERE ticketId = tickets.id ORDER BY tag) AS tags
 		FROM tickets`
 	
-	var args []interface{}
-	query, args := r.applyFilters(baseQuery, filters, args)
+	sort := pagination.Sort
+	if len(sort) == 0 {
+		sort = repositories.DefaultTicketSort
+	}
+	limit := repositories.NormalizeLimit(pagination.Limit)
+	
+	builder := newWhereBuilder()
+	if err := builder.addTicketFilters(filters); err != nil {
+		return nil, err
+	}
+	
+	page := &repositories.TicketPage{}
+	
+	// The total is counted with the same filters but without the keyset
+	// condition, so it covers the whole listing rather than the current page.
+	if pagination.IncludeTotal {
+		var total int64
+		countQuery := builder.apply("SELECT COUNT(*) FROM tickets")
+		if err := r.db.GetContext(ctx, &total, countQuery, builder.args...); err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
 	
-	// Get total count
-	countQuery := strings.Replace(query, "SELECT id, ticketNumber, title, description, status, priority, type, studentId, courseId, instructorId, categoryId, metadata, createdAt, updatedAt", "SELECT COUNT(*)", 1)
-	var total int64
-	err := r.db.GetContext(ctx, &total, countQuery, args...)
+	var cursor *repositories.Cursor
+	if pagination.Cursor != "" {
+		var err error
+		cursor, err = repositories.DecodeCursor(pagination.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Sort != sort.String() {
+			return nil, repositories.ErrInvalidCursor
+		}
+		if err := builder.addTicketKeyset(sort, cursor); err != nil {
+			return nil, err
+		}
+	}
+	
+	backward := cursor != nil && cursor.Backward
+	orderBy, err := ticketOrderBy(sort, backward)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+	query := builder.apply(baseQuery) + orderBy + fmt.Sprintf(" LIMIT %d", limit+1)
+	
+	var tickets []*models.Ticket
+	if err := r.db.SelectContext(ctx, &tickets, query, builder.args...); err != nil {
+		return nil, err
 	}
 	
-	// Apply pagination
-	orderBy := "createdAt"
-	orderDir := "DESC"
-	if pagination.OrderBy != "" {
-		orderBy = pagination.OrderBy
+	hasMore := len(tickets) > limit
+	if hasMore {
+		tickets = tickets[:limit]
 	}
-	if pagination.OrderDir != "" && (pagination.OrderDir == "ASC" || pagination.OrderDir == "DESC") {
-		orderDir = pagination.OrderDir
+	if backward {
+		for i, j := 0, len(tickets)-1; i < j; i, j = i+1, j-1 {
+			tickets[i], tickets[j] = tickets[j], tickets[i]
+		}
 	}
+	page.Tickets = tickets
 	
-	query += fmt.Sprintf(" ORDER BY %s %s", orderBy, orderDir)
+	if len(tickets) == 0 {
+		return page, nil
+	}
 	
-	if pagination.PageSize > 0 {
-		offset := (pagination.Page - 1) * pagination.PageSize
-		query += fmt.Sprintf(" LIMIT %d OFFSET %d", pagination.PageSize, offset)
+	first, last := tickets[0], tickets[len(tickets)-1]
+	if hasMore || backward {
+		page.NextCursor = repositories.Cursor{Sort: sort.String(), Values: ticketSortValues(last, sort), ID: last.ID}.Encode()
+	}
+	if cursor != nil && (hasMore || !backward) {
+		page.PrevCursor = repositories.Cursor{Sort: sort.String(), Values: ticketSortValues(first, sort), ID: first.ID, Backward: true}.Encode()
+	}
+	
+	return page, nil
+}
+
+// selectSorted runs an unpaginated ticket query ordered by sort.
+func (r *ticketRepository) selectSorted(ctx context.Context, query string, sort repositories.Sort, args []interface{}) ([]*models.Ticket, error) {
+	if len(sort) == 0 {
+		sort = repositories.DefaultTicketSort
+	}
+	orderBy, err := ticketOrderBy(sort, false)
+	if err != nil {
+		return nil, err
 	}
 	
 	var tickets []*models.Ticket
-	err = r.db.SelectContext(ctx, &tickets, query, args...)
-	return tickets, total, err
+	err = r.db.SelectContext(ctx, &tickets, query+orderBy, args...)
+	return tickets, err
 }
 
+const updateTicketQuery = `
+	UPDATE tickets SET 
+		title = :title,
+		description = :description,
+		status = :status,
+		priority = :priority,
+		type = :type,
+		instructorId = :instructorId,
+		courseId = :courseId,
+		categoryId = :categoryId,
+		metadata = :metadata,
+		updatedAt = :updatedAt,
+		resolvedAt = :resolvedAt,
+		closedAt = :closedAt,
+		slaDueAt = :slaDueAt,
+		lastActivityAt = :lastActivityAt,
+		version = version + 1
+	WHERE id = :id AND version = :version AND deletedAt IS NULL`
+
+// Update saves the ticket if it is still at ticket.Version, and bumps the
+// version. A ticket changed since it was read yields a VersionConflictError.
 func (r *ticketRepository) Update(ctx context.Context, ticket *models.Ticket) error {
-	query := `
-		UPDATE tickets SET 
-			title = :title,
-			description = :description,
-			status = :status,
-			priority = :priority,
-			type = :type,
-			instructorId = :instructorId,
-			categoryId = :categoryId,
-			metadata = :metadata,
-			updatedAt = :updatedAt
-		WHERE id = :id`
-	
-	_, err := r.db.NamedExecContext(ctx, query, ticket)
-	return err
+	result, err := r.db.NamedExecContext(ctx, updateTicketQuery, ticket)
+	if err != nil {
+		return err
+	}
+	if err := checkVersioned(ctx, r.db, result, ticket.ID, ticket.Version); err != nil {
+		return err
+	}
+	ticket.Version++
+	return nil
 }
 
-func (r *ticketRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string, updatedBy string) error {
-	query := `
-		UPDATE tickets SET 
-			status = $1,
-			updatedAt = $2
-		WHERE id = $3`
+// checkVersioned tells why an update guarded by version matched no row: the
+// ticket is gone or has moved on to another version. q must be the
+// transaction the update ran in, if any, so that it sees the same rows.
+func checkVersioned(ctx context.Context, q sqlx.QueryerContext, result sql.Result, id uuid.UUID, version int) error {
+	rows, err := result.RowsAffected()
+	if err != nil || rows > 0 {
+		return err
+	}
 	
-	_, err := r.db.ExecContext(ctx, query, status, time.Now(), id)
-	return err
+	var current int
+	err = sqlx.GetContext(ctx, q, &current, `SELECT version FROM tickets WHERE id = $1 AND deletedAt IS NULL`, id)
+	if err == sql.ErrNoRows {
+		return &repositories.NotFoundError{Entity: "ticket"}
+	}
+	if err != nil {
+		return err
+	}
+	return &repositories.VersionConflictError{Entity: "ticket", Version: version, Current: current}
 }
 
-func (r *ticketRepository) AssignInstructor(ctx context.Context, id uuid.UUID, instructorID string, updatedBy string) error {
-	query := `
-		UPDATE tickets SET 
-			instructorId = $1,
-			updatedAt = $2
-		WHERE id = $3`
-	
-	_, err := r.db.ExecContext(ctx, query, instructorID, time.Now(), id)
-	return err
+// requireRow returns a NotFoundError for entity when result matched no row.
+func requireRow(result sql.Result, entity string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return &repositories.NotFoundError{Entity: entity}
+	}
+	return nil
 }
 
-func (r *ticketRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM tickets WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+// ApplyMacro saves the ticket's changed fields, the macro's comment and tags,
+// and the history entries describing them in a single transaction. Tags the
+// ticket already carries are skipped along with their history. Like Update, it
+// fails with a VersionConflictError if the ticket has changed.
+func (r *ticketRepository) ApplyMacro(ctx context.Context, application repositories.MacroApplication) error {
+	err := r.db.WithTx(func(tx *sqlx.Tx) error {
+		result, err := tx.NamedExecContext(ctx, updateTicketQuery, application.Ticket)
+		if err != nil {
+			return err
+		}
+		if err := checkVersioned(ctx, tx, result, application.Ticket.ID, application.Ticket.Version); err != nil {
+			return err
+		}
+		
+		if application.Comment != nil {
+			if _, err := tx.NamedExecContext(ctx, insertCommentQuery, application.Comment); err != nil {
+				return err
+			}
+		}
+		
+		for _, history := range application.AddTags {
+			var inserted string
+			err := tx.GetContext(ctx, &inserted, `
+				INSERT INTO ticketTags (ticketId, tag, addedBy, createdAt)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (ticketId, tag) DO NOTHING
+				RETURNING tag`,
+				application.Ticket.ID, *history.NewValue, application.AppliedBy, application.Ticket.UpdatedAt)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := tx.NamedExecContext(ctx, insertHistoryQuery, history); err != nil {
+				return err
+			}
+		}
+		
+		for _, history := range application.History {
+			if _, err := tx.NamedExecContext(ctx, insertHistoryQuery, history); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	application.Ticket.Version++
+	return nil
+}
+
+// TouchActivity records activity on a ticket, such as a new comment, without
+// changing any of its fields. The version is left alone, so that a comment
+// does not invalidate the ETags clients hold for the ticket.
+func (r *ticketRepository) TouchActivity(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE tickets SET lastActivityAt = GREATEST(lastActivityAt, $1) WHERE id = $2 AND deletedAt IS NULL`
+	_, err := r.db.ExecContext(ctx, query, at, id)
 	return err
 }
 
-func (r *ticketRepository) applyFilters(baseQuery string, filters repositories.TicketFilters, args []interface{}) (string, []interface{}) {
-	var conditions []string
-	argIndex := len(args) + 1
+// FindSimilar ranks tickets by trigram similarity of their title and
+// description to the given text, weighting the title more heavily.
+func (r *ticketRepository) FindSimilar(ctx context.Context, query repositories.SimilarTicketQuery) ([]*models.SimilarTicket, error) {
+	builder := newWhereBuilder(query.Title, query.Description)
+	score := "(0.6 * similarity(title, $1) + 0.4 * similarity(description, $2))"
 	
-	if filters.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
-		args = append(args, *filters.Status)
-		argIndex++
+	builder.where("(title % $1 OR description % $2)")
+	builder.where("mergedIntoId IS NULL")
+	builder.where("deletedAt IS NULL")
+	builder.where(score + " >= " + builder.arg(query.MinScore))
+	if query.Type != nil {
+		builder.where("type = " + builder.arg(*query.Type))
 	}
-	
-	if filters.Priority != nil {
-		conditions = append(conditions, fmt.Sprintf("priority = $%d", argIndex))
-		args = append(args, *filters.Priority)
-		argIndex++
+	if query.CourseID != nil {
+		builder.where("courseId = " + builder.arg(*query.CourseID))
 	}
-	
-	if filters.Type != nil {
-		conditions = append(conditions, fmt.Sprintf("type = $%d", argIndex))
-		args = append(args, *filters.Type)
-		argIndex++
+	if query.StudentID != nil {
+		builder.where("studentId = " + builder.arg(*query.StudentID))
 	}
-	
-	if filters.CategoryID != nil {
-		conditions = append(conditions, fmt.Sprintf("categoryId = $%d", argIndex))
-		args = append(args, *filters.CategoryID)
-		argIndex++
+	if query.ExcludeID != nil {
+		builder.where("id <> " + builder.arg(*query.ExcludeID))
 	}
-	
-	if filters.CourseID != nil {
-		conditions = append(conditions, fmt.Sprintf("courseId = $%d", argIndex))
-		args = append(args, *filters.CourseID)
-		argIndex++
+	builder.addScope(query.Scope)
+	if query.OpenOnly {
+		builder.where("status NOT IN ('resolved', 'closed')")
 	}
 	
-	if filters.InstructorID != nil {
-		conditions = append(conditions, fmt.Sprintf("instructorId = $%d", argIndex))
-		args = append(args, *filters.InstructorID)
-		argIndex++
-	}
+	baseQuery := `
+		SELECT 
+			id, ticketNumber, title, description, status, priority, type,
+			studentId, courseId, instructorId, categoryId, metadata,
+			createdAt, updatedAt, resolvedAt, closedAt, slaDueAt, lastActivityAt,
+			mergedIntoId, isIncident, version, ` + score + ` AS score
+		FROM tickets`
 	
-	if filters.FromDate != nil {
-		conditions = append(conditions, fmt.Sprintf("createdAt >= $%d", argIndex))
-		args = append(args, *filters.FromDate)
-		argIndex++
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 5
 	}
+	sqlQuery := builder.apply(baseQuery) + fmt.Sprintf(" ORDER BY score DESC, createdAt DESC LIMIT %d", limit)
 	
-	if filters.ToDate != nil {
-		conditions = append(conditions, fmt.Sprintf("createdAt <= $%d", argIndex))
-		args = append(args, *filters.ToDate)
-		argIndex++
+	var tickets []*models.SimilarTicket
+	err := r.db.SelectContext(ctx, &tickets, sqlQuery, builder.args...)
+	return tickets, err
+}
+
+// Merge folds duplicates into the primary ticket in a single transaction:
+// comments, attachments and history move to the primary, watchers are copied
+// over, and each duplicate is closed with mergedIntoId pointing at the
+// primary. Both sides get a history entry. A primary that does not exist or
+// is deleted yields a NotFoundError, one that was merged a TicketMergedError,
+// and a duplicate that was merged or deleted meanwhile ErrTicketMerged.
+func (r *ticketRepository) Merge(ctx context.Context, primaryID uuid.UUID, duplicateIDs []uuid.UUID, mergedBy string, at time.Time) error {
+	return r.db.WithTx(func(tx *sqlx.Tx) error {
+		var primary struct {
+			TicketNumber string     `db:"ticketNumber"`
+			MergedIntoID *uuid.UUID `db:"mergedIntoId"`
+		}
+		err := tx.GetContext(ctx, &primary, `SELECT ticketNumber, mergedIntoId FROM tickets WHERE id = $1 AND deletedAt IS NULL FOR UPDATE`, primaryID)
+		if err == sql.ErrNoRows {
+			return &repositories.NotFoundError{Entity: "ticket"}
+		}
+		if err != nil {
+			return err
+		}
+		if primary.MergedIntoID != nil {
+			return &repositories.TicketMergedError{TicketNumber: primary.TicketNumber}
+		}
+		primaryNumber := primary.TicketNumber
+		
+		for _, duplicateID := range duplicateIDs {
+			var duplicateNumber string
+			err := tx.GetContext(ctx, &duplicateNumber, `
+				UPDATE tickets SET 
+					status = 'closed',
+					closedAt = $1,
+					updatedAt = $1,
+					lastActivityAt = $1,
+					mergedIntoId = $2,
+					version = version + 1
+				WHERE id = $3 AND mergedIntoId IS NULL AND deletedAt IS NULL
+				RETURNING ticketNumber`, at, primaryID, duplicateID)
+			if err == sql.ErrNoRows {
+				return repositories.ErrTicketMerged
+			}
+			if err != nil {
+				return err
+			}
+			
+			moves := []string{
+				`UPDATE ticketComments SET ticketId = $1 WHERE ticketId = $2`,
+				`UPDATE attachments SET ticketId = $1 WHERE ticketId = $2`,
+				`UPDATE ticketHistory SET ticketId = $1 WHERE ticketId = $2`,
+				`INSERT INTO ticketWatchers (id, ticketId, userId, email, role, source, addedBy, createdAt)
+					SELECT uuid_generate_v4(), $1, userId, email, role, source, addedBy, createdAt
+					FROM ticketWatchers WHERE ticketId = $2
+					ON CONFLICT (ticketId, userId) DO NOTHING`,
+			}
+			for _, move := range moves {
+				if _, err := tx.ExecContext(ctx, move, primaryID, duplicateID); err != nil {
+					return err
+				}
+			}
+			
+			entries := []struct {
+				ticketID    uuid.UUID
+				action      string
+				newValue    string
+				description string
+			}{
+				{duplicateID, "merged", primaryNumber, fmt.Sprintf("Merged into ticket #%s", primaryNumber)},
+				{primaryID, "mergedFrom", duplicateNumber, fmt.Sprintf("Ticket #%s merged into this ticket", duplicateNumber)},
+			}
+			for _, entry := range entries {
+				_, err := tx.ExecContext(ctx, `
+					INSERT INTO ticketHistory (
+						id, ticketId, userId, action, newValue, description, metadata, createdAt
+					) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+					uuid.New(), entry.ticketID, mergedBy, entry.action, entry.newValue, entry.description,
+					models.JSONB{"primaryTicketId": primaryID.String(), "duplicateTicketId": duplicateID.String()}, at)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		
+		_, err = tx.ExecContext(ctx, `UPDATE tickets SET updatedAt = $1, lastActivityAt = $1, version = version + 1 WHERE id = $2`, at, primaryID)
+		return err
+	})
+}
+
+// SetIncident changes the incident flag of the ticket if it is still at
+// version, failing with a VersionConflictError otherwise.
+func (r *ticketRepository) SetIncident(ctx context.Context, id uuid.UUID, version int, isIncident bool) error {
+	query := `
+		UPDATE tickets SET 
+			isIncident = $1,
+			updatedAt = $2,
+			version = version + 1
+		WHERE id = $3 AND version = $4 AND deletedAt IS NULL`
+	
+	result, err := r.db.ExecContext(ctx, query, isIncident, time.Now(), id, version)
+	if err != nil {
+		return err
 	}
+	return checkVersioned(ctx, r.db, result, id, version)
+}
+
+// ResolveChildren resolves every open child of parentID in one transaction,
+// recording a history entry on each, and returns the tickets it resolved. A
+// deleted parent resolves nothing and yields a NotFoundError; deleted
+// children are left alone.
+func (r *ticketRepository) ResolveChildren(ctx context.Context, parentID uuid.UUID, resolvedBy string, at time.Time) ([]*models.Ticket, error) {
+	var resolved []*models.Ticket
+	err := r.db.WithTx(func(tx *sqlx.Tx) error {
+		var parentNumber string
+		err := tx.GetContext(ctx, &parentNumber, `SELECT ticketNumber FROM tickets WHERE id = $1 AND deletedAt IS NULL FOR SHARE`, parentID)
+		if err == sql.ErrNoRows {
+			return &repositories.NotFoundError{Entity: "ticket"}
+		}
+		if err != nil {
+			return err
+		}
+		
+		query := `
+			UPDATE tickets t SET 
+				status = 'resolved',
+				resolvedAt = $1,
+				updatedAt = $1,
+				lastActivityAt = $1,
+				version = t.version + 1
+			FROM ticketRelations rel
+			WHERE rel.sourceTicketId = $2 AND rel.type = 'parent' AND rel.targetTicketId = t.id
+				AND t.status NOT IN ('resolved', 'closed') AND t.deletedAt IS NULL
+			RETURNING 
+				t.id, t.ticketNumber, t.title, t.description, t.status, t.priority, t.type,
+				t.studentId, t.courseId, t.instructorId, t.categoryId, t.metadata,
+				t.createdAt, t.updatedAt, t.resolvedAt, t.closedAt, t.slaDueAt, t.lastActivityAt,
+				t.mergedIntoId, t.isIncident, t.version`
+		if err := tx.SelectContext(ctx, &resolved, query, at, parentID); err != nil {
+			return err
+		}
+		
+		for _, child := range resolved {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO ticketHistory (
+					id, ticketId, userId, action, newValue, description, metadata, createdAt
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+				uuid.New(), child.ID, resolvedBy, "resolvedByParent", string(models.TicketStatusResolved),
+				fmt.Sprintf("Resolved with parent ticket #%s", parentNumber),
+				models.JSONB{"parentTicketId": parentID.String()}, at)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// Delete marks the ticket deleted. Its comments, attachments and history
+// are kept, and it disappears from every query until restored.
+func (r *ticketRepository) Delete(ctx context.Context, id uuid.UUID, deletedBy string, at time.Time) error {
+	query := `
+		UPDATE tickets SET 
+			deletedAt = $1,
+			deletedBy = $2,
+			updatedAt = $1,
+			version = version + 1
+		WHERE id = $3 AND deletedAt IS NULL`
 	
-	if filters.Search != nil && *filters.Search != "" {
-		searchTerm := "%" + *filters.Search + "%"
-		conditions = append(conditions, fmt.Sprintf("(title ILIKE $%d OR description ILIKE $%d OR ticketNumber ILIKE $%d)", argIndex, argIndex+1, argIndex+2))
-		args = append(args, searchTerm, searchTerm, searchTerm)
-		argIndex += 3
+	result, err := r.db.ExecContext(ctx, query, at, deletedBy, id)
+	if err != nil {
+		return err
 	}
+	return requireRow(result, "ticket")
+}
+
+// Restore undoes Delete.
+func (r *ticketRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE tickets SET 
+			deletedAt = NULL,
+			deletedBy = NULL,
+			version = version + 1
+		WHERE id = $1 AND deletedAt IS NOT NULL`
 	
-	if len(conditions) > 0 {
-		connector := " WHERE "
-		if strings.Contains(baseQuery, "WHERE") {
-			connector = " AND "
-		}
-		baseQuery += connector + strings.Join(conditions, " AND ")
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
 	}
+	return requireRow(result, "deleted ticket")
+}
+
+// GetDeleted returns the most recently deleted tickets first.
+func (r *ticketRepository) GetDeleted(ctx context.Context, limit int) ([]*models.Ticket, error) {
+	query := `
+		SELECT 
+			id, ticketNumber, title, description, status, priority, type,
+			studentId, courseId, instructorId, categoryId, metadata,
+			createdAt, updatedAt, resolvedAt, closedAt, slaDueAt, lastActivityAt,
+			mergedIntoId, isIncident, version, deletedAt, deletedBy,
+			ARRAY(SELECT tag FROM ticketTags WHERE ticketId = tickets.id ORDER BY tag) AS tags
+		FROM tickets 
+		WHERE deletedAt IS NOT NULL
+		ORDER BY deletedAt DESC
+		LIMIT $1`
 	
-	return baseQuery, args
-}
\ No newline at end of file
+	var tickets []*models.Ticket
+	err := r.db.SelectContext(ctx, &tickets, query, limit)
+	return tickets, err
+}
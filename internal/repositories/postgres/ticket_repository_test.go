@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+// newMockDB returns a database backed by sqlmock whose expectations are
+// matched against queries with their whitespace collapsed.
+func newMockDB(t *testing.T) (*database.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	matcher := sqlmock.QueryMatcherFunc(func(expected, actual string) error {
+		if !regexp.MustCompile(expected).MatchString(strings.Join(strings.Fields(actual), " ")) {
+			return &queryMismatchError{expected: expected, actual: actual}
+		}
+		return nil
+	})
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &database.DB{DB: sqlx.NewDb(db, "postgres")}, mock
+}
+
+type queryMismatchError struct {
+	expected string
+	actual   string
+}
+
+func (e *queryMismatchError) Error() string {
+	return "query " + strings.Join(strings.Fields(e.actual), " ") + " does not match " + e.expected
+}
+
+func TestWhereBuilderApply(t *testing.T) {
+	builder := newWhereBuilder()
+	if got := builder.apply("SELECT id FROM tickets"); got != "SELECT id FROM tickets" {
+		t.Errorf("apply without conditions = %q", got)
+	}
+
+	builder.where("deletedAt IS NULL")
+	builder.where("status = " + builder.arg("open"))
+	query := builder.apply("SELECT ARRAY(SELECT tag FROM ticketTags WHERE ticketId = tickets.id) AS tags FROM tickets")
+	want := "SELECT ARRAY(SELECT tag FROM ticketTags WHERE ticketId = tickets.id) AS tags FROM tickets WHERE deletedAt IS NULL AND status = $1"
+	if query != want {
+		t.Errorf("apply = %q, want %q", query, want)
+	}
+}
+
+func TestTicketRepositoryListWithFilters(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTicketRepository(db)
+
+	status := "open"
+	courseID := "course-1"
+	filters := repositories.TicketFilters{
+		Status:   &status,
+		CourseID: &courseID,
+		TagsAny:  []string{"exam"},
+	}
+	where := regexp.QuoteMeta(" WHERE deletedAt IS NULL AND status = $1 AND courseId = $2 AND EXISTS (")
+
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM tickets` + where).
+		WithArgs(status, courseID, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`AS tags FROM tickets` + where + `.* ORDER BY .* LIMIT 2$`).
+		WithArgs(status, courseID, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ticketNumber", "status", "courseId"}).
+			AddRow(uuid.New(), "T-0002", status, courseID))
+
+	page, err := repo.List(context.Background(), filters, repositories.Pagination{Limit: 1, IncludeTotal: true})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if page.Total == nil || *page.Total != 2 {
+		t.Errorf("Total = %v, want 2", page.Total)
+	}
+	if len(page.Tickets) != 1 || page.Tickets[0].TicketNumber != "T-0002" {
+		t.Errorf("Tickets = %+v, want the single ticket T-0002", page.Tickets)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTicketRepositoryGetByStudentIDWithFilters(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTicketRepository(db)
+
+	status := "resolved"
+	mock.ExpectQuery(`AS tags FROM tickets` + regexp.QuoteMeta(" WHERE studentId = $1 AND deletedAt IS NULL AND status = $2 ORDER BY ")).
+		WithArgs("student-1", status).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ticketNumber"}))
+
+	if _, err := repo.GetByStudentID(context.Background(), "student-1", repositories.TicketFilters{Status: &status}, nil); err != nil {
+		t.Fatalf("GetByStudentID returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+func TestTicketRepositoryApplyMacroVersionConflict(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTicketRepository(db)
+
+	ticket := &models.Ticket{ID: uuid.New(), Version: 3, Metadata: models.JSONB{}}
+
+	// The current version is read inside the transaction, before it is
+	// rolled back
+	mock.ExpectBegin()
+	mock.ExpectExec(`^UPDATE tickets SET .* WHERE id = \$\d+ AND version = \$\d+ AND deletedAt IS NULL$`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version FROM tickets WHERE id = $1 AND deletedAt IS NULL`)).
+		WithArgs(ticket.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+	mock.ExpectRollback()
+
+	err := repo.ApplyMacro(context.Background(), repositories.MacroApplication{Ticket: ticket, AppliedBy: "instructor-1"})
+	var conflict *repositories.VersionConflictError
+	if !errors.As(err, &conflict) || conflict.Version != 3 || conflict.Current != 4 {
+		t.Fatalf("ApplyMacro = %v, want a VersionConflictError from version 3 to 4", err)
+	}
+	if ticket.Version != 3 {
+		t.Errorf("ticket.Version = %d after a conflict, want 3", ticket.Version)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTicketRepositoryApplyMacroSkipsExistingTags(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTicketRepository(db)
+
+	ticket := &models.Ticket{ID: uuid.New(), Version: 3, Metadata: models.JSONB{}}
+	tagAdded := func(tag string) *models.TicketHistory {
+		return &models.TicketHistory{ID: uuid.New(), TicketID: ticket.ID, Action: "tagAdded", NewValue: &tag, Metadata: models.JSONB{}}
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`^UPDATE tickets SET .* WHERE id = \$\d+ AND version = \$\d+ AND deletedAt IS NULL$`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	insertTag := regexp.QuoteMeta(`INSERT INTO ticketTags (ticketId, tag, addedBy, createdAt) VALUES ($1, $2, $3, $4) ON CONFLICT (ticketId, tag) DO NOTHING RETURNING tag`)
+	mock.ExpectQuery(insertTag).
+		WithArgs(ticket.ID, "exam", "instructor-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"tag"}))
+	mock.ExpectQuery(insertTag).
+		WithArgs(ticket.ID, "retake", "instructor-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"tag"}).AddRow("retake"))
+	// Only the tag the ticket did not carry gets a history entry
+	mock.ExpectExec(`^INSERT INTO ticketHistory`).
+		WithArgs(sqlmock.AnyArg(), ticket.ID, sqlmock.AnyArg(), "tagAdded", sqlmock.AnyArg(), sqlmock.AnyArg(), "retake", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.ApplyMacro(context.Background(), repositories.MacroApplication{
+		Ticket:    ticket,
+		AddTags:   []*models.TicketHistory{tagAdded("exam"), tagAdded("retake")},
+		AppliedBy: "instructor-1",
+	})
+	if err != nil {
+		t.Fatalf("ApplyMacro returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTicketRepositoryResolveChildrenOfDeletedParent(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTicketRepository(db)
+	parentID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT ticketNumber FROM tickets WHERE id = $1 AND deletedAt IS NULL FOR SHARE`)).
+		WithArgs(parentID).
+		WillReturnRows(sqlmock.NewRows([]string{"ticketNumber"}))
+	mock.ExpectRollback()
+
+	resolved, err := repo.ResolveChildren(context.Background(), parentID, "instructor-1", time.Now())
+	var notFound *repositories.NotFoundError
+	if !errors.As(err, &notFound) || len(resolved) != 0 {
+		t.Fatalf("ResolveChildren = %v, %v; want a NotFoundError and no children", resolved, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"community-support-service/internal/database"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+)
+
+type watcherRepository struct {
+	db *database.DB
+}
+
+func NewWatcherRepository(db *database.DB) repositories.WatcherRepository {
+	return &watcherRepository{db: db}
+}
+
+// Create adds a watcher. Adding a user who already watches the ticket is a
+// no-op so that auto-watching is idempotent.
+func (r *watcherRepository) Create(ctx context.Context, watcher *models.TicketWatcher) error {
+	query := `
+		INSERT INTO ticketWatchers (
+			id, ticketId, userId, email, role, source, addedBy, createdAt
+		) VALUES (
+			:id, :ticketId, :userId, :email, :role, :source, :addedBy, :createdAt
+		)
+		ON CONFLICT (ticketId, userId) DO NOTHING`
+	
+	_, err := r.db.NamedExecContext(ctx, query, watcher)
+	return err
+}
+
+func (r *watcherRepository) GetByTicketID(ctx context.Context, ticketID uuid.UUID) ([]*models.TicketWatcher, error) {
+	query := `
+		SELECT 
+			id, ticketId, userId, email, role, source, addedBy, createdAt
+		FROM ticketWatchers 
+		WHERE ticketId = $1
+		ORDER BY createdAt ASC`
+	
+	var watchers []*models.TicketWatcher
+	err := r.db.SelectContext(ctx, &watchers, query, ticketID)
+	return watchers, err
+}
+
+func (r *watcherRepository) GetByTicketAndUser(ctx context.Context, ticketID uuid.UUID, userID string) (*models.TicketWatcher, error) {
+	var watcher models.TicketWatcher
+	query := `
+		SELECT 
+			id, ticketId, userId, email, role, source, addedBy, createdAt
+		FROM ticketWatchers 
+		WHERE ticketId = $1 AND userId = $2`
+	
+	err := r.db.GetContext(ctx, &watcher, query, ticketID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &repositories.NotFoundError{Entity: "watcher"}
+		}
+		return nil, err
+	}
+	return &watcher, nil
+}
+
+func (r *watcherRepository) Delete(ctx context.Context, ticketID uuid.UUID, userID string) error {
+	query := `DELETE FROM ticketWatchers WHERE ticketId = $1 AND userId = $2`
+	_, err := r.db.ExecContext(ctx, query, ticketID, userID)
+	return err
+}
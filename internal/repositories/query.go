@@ -0,0 +1,382 @@
+package repositories
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"community-support-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type QueryOperator string
+
+const (
+	QueryOpIn      QueryOperator = "in"
+	QueryOpNotIn   QueryOperator = "notIn"
+	QueryOpIsNull  QueryOperator = "isNull"
+	QueryOpNotNull QueryOperator = "notNull"
+	QueryOpGt      QueryOperator = ">"
+	QueryOpGte     QueryOperator = ">="
+	QueryOpLt      QueryOperator = "<"
+	QueryOpLte     QueryOperator = "<="
+)
+
+// Query fields understood by ParseTicketQuery. Repositories map these to
+// columns through their own whitelist, never by interpolating user input.
+const (
+	QueryFieldStatus   = "status"
+	QueryFieldPriority = "priority"
+	QueryFieldType     = "type"
+	QueryFieldCourse   = "course"
+	QueryFieldCategory = "category"
+	QueryFieldAssigned = "assigned"
+	QueryFieldStudent  = "student"
+	QueryFieldCreated  = "created"
+	QueryFieldUpdated  = "updated"
+	QueryFieldTag      = "tag"
+	QueryFieldMetadata = "metadata"
+)
+
+// QueryCondition is a single parsed "field:value" term of a ticket query.
+type QueryCondition struct {
+	Field  string
+	Key    string // metadata key for metadata.<key> terms
+	Op     QueryOperator
+	Values []string
+	Time   *time.Time
+}
+
+// TicketQuery is the parsed form of a ticket filter expression such as
+// `status:open,inProgress priority:>=high assigned:none created:>7d`.
+type TicketQuery struct {
+	Conditions []QueryCondition
+	Terms      []string // free-text terms matched against title, description and ticket number
+}
+
+var (
+	priorityRank = []models.TicketPriority{
+		models.TicketPriorityLow,
+		models.TicketPriorityMedium,
+		models.TicketPriorityHigh,
+		models.TicketPriorityUrgent,
+	}
+
+	validStatuses = map[string]bool{
+		string(models.TicketStatusOpen):               true,
+		string(models.TicketStatusInProgress):         true,
+		string(models.TicketStatusWaitingForCustomer): true,
+		string(models.TicketStatusResolved):           true,
+		string(models.TicketStatusClosed):             true,
+	}
+
+	validTypes = map[string]bool{
+		string(models.TicketTypeGeneral):    true,
+		string(models.TicketTypeTechnical):  true,
+		string(models.TicketTypeCourse):     true,
+		string(models.TicketTypeAssignment): true,
+		string(models.TicketTypeGrading):    true,
+		string(models.TicketTypePlatform):   true,
+		string(models.TicketTypeContent):    true,
+	}
+
+	metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`)
+	relativeAgePattern = regexp.MustCompile(`^(\d+)([hdwm])$`)
+)
+
+// ParseTicketQuery parses a filter expression into a TicketQuery.
+//
+// Terms are separated by whitespace; values may be double-quoted. Supported
+// forms:
+//
+//	status:open,inProgress      any of the listed values
+//	status:!closed              none of the listed values
+//	priority:>=high             ordinal comparison (low < medium < high < urgent)
+//	assigned:none | any | me    unassigned, assigned, or assigned to userID
+//	created:>7d                 older than 7 days (h, d, w, m units)
+//	created:>=2024-01-31        on or after an absolute date
+//	metadata.assignmentId:42    JSONB metadata equality
+//	tag:billing,login           tagged with any of the tags; repeat to require all
+//	"login error"               free-text search
+func ParseTicketQuery(expr string, userID string, now time.Time) (*TicketQuery, error) {
+	tokens, err := tokenizeQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &TicketQuery{}
+	for _, token := range tokens {
+		if !token.hasField {
+			query.Terms = append(query.Terms, token.value)
+			continue
+		}
+
+		condition, err := parseCondition(token.field, token.value, userID, now)
+		if err != nil {
+			return nil, err
+		}
+		query.Conditions = append(query.Conditions, condition)
+	}
+
+	return query, nil
+}
+
+type queryToken struct {
+	field    string
+	value    string
+	hasField bool
+}
+
+func tokenizeQuery(expr string) ([]queryToken, error) {
+	var tokens []queryToken
+	var current strings.Builder
+	inQuotes := false
+
+	flush := func() {
+		raw := current.String()
+		current.Reset()
+
+		token := queryToken{value: raw}
+		if idx := strings.Index(raw, ":"); idx > 0 && !strings.HasPrefix(raw, `"`) {
+			token.field = raw[:idx]
+			token.value = raw[idx+1:]
+			token.hasField = true
+		}
+		token.value = strings.ReplaceAll(token.value, `"`, "")
+		if token.value != "" || token.hasField {
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t' || r == '\n') && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in query")
+	}
+	flush()
+
+	return tokens, nil
+}
+
+func parseCondition(field, value, userID string, now time.Time) (QueryCondition, error) {
+	if value == "" {
+		return QueryCondition{}, fmt.Errorf("missing value for %q", field)
+	}
+
+	// Field names are case-insensitive; metadata keys are not
+	if strings.HasPrefix(strings.ToLower(field), QueryFieldMetadata+".") {
+		key := field[len(QueryFieldMetadata)+1:]
+		if !metadataKeyPattern.MatchString(key) {
+			return QueryCondition{}, fmt.Errorf("invalid metadata key %q", key)
+		}
+		condition, err := parseListCondition(QueryFieldMetadata, value)
+		condition.Key = key
+		return condition, err
+	}
+
+	field = strings.ToLower(field)
+	switch field {
+	case QueryFieldStatus:
+		return parseEnumCondition(field, value, validStatuses)
+	case QueryFieldType:
+		return parseEnumCondition(field, value, validTypes)
+	case QueryFieldPriority:
+		return parsePriorityCondition(value)
+	case QueryFieldCourse, QueryFieldStudent:
+		return parseListCondition(field, value)
+	case QueryFieldCategory:
+		condition, err := parseListCondition(field, value)
+		for _, v := range condition.Values {
+			if _, parseErr := uuid.Parse(v); parseErr != nil {
+				return QueryCondition{}, fmt.Errorf("invalid category ID %q", v)
+			}
+		}
+		return condition, err
+	case QueryFieldTag:
+		// Tags are stored lowercased; repeat the field to require several tags
+		return parseListCondition(field, strings.ToLower(value))
+	case QueryFieldAssigned:
+		return parseAssignedCondition(value, userID)
+	case QueryFieldCreated, QueryFieldUpdated:
+		return parseTimeCondition(field, value, now)
+	default:
+		return QueryCondition{}, fmt.Errorf("unknown query field %q", field)
+	}
+}
+
+// splitValues turns "a,b" into an IN list and "!a,b" into a NOT IN list.
+func splitValues(value string) (QueryOperator, []string) {
+	op := QueryOpIn
+	if strings.HasPrefix(value, "!") {
+		op = QueryOpNotIn
+		value = strings.TrimPrefix(value, "!")
+	}
+
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return op, values
+}
+
+func parseListCondition(field, value string) (QueryCondition, error) {
+	op, values := splitValues(value)
+	if len(values) == 0 {
+		return QueryCondition{}, fmt.Errorf("missing value for %q", field)
+	}
+	return QueryCondition{Field: field, Op: op, Values: values}, nil
+}
+
+func parseEnumCondition(field, value string, allowed map[string]bool) (QueryCondition, error) {
+	condition, err := parseListCondition(field, value)
+	if err != nil {
+		return QueryCondition{}, err
+	}
+	for _, v := range condition.Values {
+		if !allowed[v] {
+			return QueryCondition{}, fmt.Errorf("invalid %s %q", field, v)
+		}
+	}
+	return condition, nil
+}
+
+// parsePriorityCondition expands ordinal comparisons into an explicit list of
+// priorities so that "priority:>=high" means high or urgent rather than an
+// alphabetical comparison.
+func parsePriorityCondition(value string) (QueryCondition, error) {
+	for _, prefix := range []QueryOperator{QueryOpGte, QueryOpLte, QueryOpGt, QueryOpLt} {
+		if !strings.HasPrefix(value, string(prefix)) {
+			continue
+		}
+
+		target := strings.TrimPrefix(value, string(prefix))
+		rank := -1
+		for i, p := range priorityRank {
+			if string(p) == target {
+				rank = i
+			}
+		}
+		if rank < 0 {
+			return QueryCondition{}, fmt.Errorf("invalid priority %q", target)
+		}
+
+		var values []string
+		for i, p := range priorityRank {
+			if (prefix == QueryOpGte && i >= rank) ||
+				(prefix == QueryOpGt && i > rank) ||
+				(prefix == QueryOpLte && i <= rank) ||
+				(prefix == QueryOpLt && i < rank) {
+				values = append(values, string(p))
+			}
+		}
+		if len(values) == 0 {
+			return QueryCondition{}, fmt.Errorf("priority %q matches nothing", value)
+		}
+		return QueryCondition{Field: QueryFieldPriority, Op: QueryOpIn, Values: values}, nil
+	}
+
+	allowed := make(map[string]bool, len(priorityRank))
+	for _, p := range priorityRank {
+		allowed[string(p)] = true
+	}
+	return parseEnumCondition(QueryFieldPriority, value, allowed)
+}
+
+func parseAssignedCondition(value, userID string) (QueryCondition, error) {
+	switch value {
+	case "none":
+		return QueryCondition{Field: QueryFieldAssigned, Op: QueryOpIsNull}, nil
+	case "any":
+		return QueryCondition{Field: QueryFieldAssigned, Op: QueryOpNotNull}, nil
+	}
+
+	condition, err := parseListCondition(QueryFieldAssigned, value)
+	if err != nil {
+		return QueryCondition{}, err
+	}
+	for i, v := range condition.Values {
+		if v == "me" {
+			if userID == "" {
+				return QueryCondition{}, fmt.Errorf("assigned:me requires an authenticated user")
+			}
+			condition.Values[i] = userID
+		}
+	}
+	return condition, nil
+}
+
+// parseTimeCondition accepts either a relative age ("7d", meaning seven days
+// ago) or an absolute RFC 3339 timestamp / YYYY-MM-DD date. Relative ages
+// compare by age, so "created:>7d" selects tickets older than seven days and a
+// bare "created:7d" selects tickets from the last seven days.
+func parseTimeCondition(field, value string, now time.Time) (QueryCondition, error) {
+	op := QueryOperator("")
+	for _, prefix := range []QueryOperator{QueryOpGte, QueryOpLte, QueryOpGt, QueryOpLt} {
+		if strings.HasPrefix(value, string(prefix)) {
+			op = prefix
+			value = strings.TrimPrefix(value, string(prefix))
+			break
+		}
+	}
+
+	if match := relativeAgePattern.FindStringSubmatch(value); match != nil {
+		amount, _ := strconv.Atoi(match[1])
+		var age time.Duration
+		switch match[2] {
+		case "h":
+			age = time.Duration(amount) * time.Hour
+		case "d":
+			age = time.Duration(amount) * 24 * time.Hour
+		case "w":
+			age = time.Duration(amount) * 7 * 24 * time.Hour
+		case "m":
+			age = time.Duration(amount) * 30 * 24 * time.Hour
+		}
+		if op == "" {
+			op = QueryOpLte
+		}
+		at := now.Add(-age)
+		return QueryCondition{Field: field, Op: invertComparison(op), Time: &at}, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		at, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			return QueryCondition{}, fmt.Errorf("invalid %s value %q", field, value)
+		}
+	}
+	if op == "" {
+		op = QueryOpGte
+	}
+	return QueryCondition{Field: field, Op: op, Time: &at}, nil
+}
+
+// invertComparison flips an age comparison into a timestamp comparison:
+// older than (>) a duration means earlier than (<) the resulting instant.
+func invertComparison(op QueryOperator) QueryOperator {
+	switch op {
+	case QueryOpGt:
+		return QueryOpLt
+	case QueryOpGte:
+		return QueryOpLte
+	case QueryOpLt:
+		return QueryOpGt
+	case QueryOpLte:
+		return QueryOpGte
+	}
+	return op
+}
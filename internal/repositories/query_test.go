@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenizeQuery(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    []queryToken
+		wantErr bool
+	}{
+		{expr: "", want: nil},
+		{expr: "  \t\n ", want: nil},
+		{
+			expr: "status:open login",
+			want: []queryToken{{field: "status", value: "open", hasField: true}, {value: "login"}},
+		},
+		{
+			expr: `"login error" tag:"two words"`,
+			want: []queryToken{{value: "login error"}, {field: "tag", value: "two words", hasField: true}},
+		},
+		{
+			// A quoted term keeps its colon as text
+			expr: `"error: 500"`,
+			want: []queryToken{{value: "error: 500"}},
+		},
+		{
+			// An empty value is kept so that the parser can reject it
+			expr: "status:",
+			want: []queryToken{{field: "status", hasField: true}},
+		},
+		{
+			// A leading colon is not a field separator
+			expr: ":open",
+			want: []queryToken{{value: ":open"}},
+		},
+		{expr: `status:open "login`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			tokens, err := tokenizeQuery(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("tokenizeQuery(%q) = %+v, want an error", tt.expr, tokens)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("tokenizeQuery(%q) returned error: %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(tokens, tt.want) {
+				t.Errorf("tokenizeQuery(%q) = %+v, want %+v", tt.expr, tokens, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTicketQuery(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	at := func(age time.Duration) *time.Time {
+		t := now.Add(-age)
+		return &t
+	}
+	date := func(value string) *time.Time {
+		t, _ := time.Parse(time.RFC3339, value)
+		return &t
+	}
+	day := 24 * time.Hour
+
+	tests := []struct {
+		expr string
+		want QueryCondition
+	}{
+		{"status:open,inProgress", QueryCondition{Field: QueryFieldStatus, Op: QueryOpIn, Values: []string{"open", "inProgress"}}},
+		{"status:!closed", QueryCondition{Field: QueryFieldStatus, Op: QueryOpNotIn, Values: []string{"closed"}}},
+		{"STATUS:open", QueryCondition{Field: QueryFieldStatus, Op: QueryOpIn, Values: []string{"open"}}},
+		{"type:grading", QueryCondition{Field: QueryFieldType, Op: QueryOpIn, Values: []string{"grading"}}},
+
+		// Priorities compare by rank, not alphabetically
+		{"priority:>=high", QueryCondition{Field: QueryFieldPriority, Op: QueryOpIn, Values: []string{"high", "urgent"}}},
+		{"priority:>medium", QueryCondition{Field: QueryFieldPriority, Op: QueryOpIn, Values: []string{"high", "urgent"}}},
+		{"priority:<=medium", QueryCondition{Field: QueryFieldPriority, Op: QueryOpIn, Values: []string{"low", "medium"}}},
+		{"priority:<high", QueryCondition{Field: QueryFieldPriority, Op: QueryOpIn, Values: []string{"low", "medium"}}},
+		{"priority:urgent,low", QueryCondition{Field: QueryFieldPriority, Op: QueryOpIn, Values: []string{"urgent", "low"}}},
+
+		{"assigned:none", QueryCondition{Field: QueryFieldAssigned, Op: QueryOpIsNull}},
+		{"assigned:any", QueryCondition{Field: QueryFieldAssigned, Op: QueryOpNotNull}},
+		{"assigned:me,instructor-2", QueryCondition{Field: QueryFieldAssigned, Op: QueryOpIn, Values: []string{"user-1", "instructor-2"}}},
+
+		// Relative ages compare by age: older than 7 days is before now-7d
+		{"created:>7d", QueryCondition{Field: QueryFieldCreated, Op: QueryOpLt, Time: at(7 * day)}},
+		{"created:>=7d", QueryCondition{Field: QueryFieldCreated, Op: QueryOpLte, Time: at(7 * day)}},
+		{"created:<12h", QueryCondition{Field: QueryFieldCreated, Op: QueryOpGt, Time: at(12 * time.Hour)}},
+		{"created:7d", QueryCondition{Field: QueryFieldCreated, Op: QueryOpGte, Time: at(7 * day)}},
+		{"updated:<=2w", QueryCondition{Field: QueryFieldUpdated, Op: QueryOpGte, Time: at(14 * day)}},
+		{"updated:>1m", QueryCondition{Field: QueryFieldUpdated, Op: QueryOpLt, Time: at(30 * day)}},
+
+		// Absolute times compare as written
+		{"created:>=2024-01-31", QueryCondition{Field: QueryFieldCreated, Op: QueryOpGte, Time: date("2024-01-31T00:00:00Z")}},
+		{"created:<2024-01-31T10:00:00Z", QueryCondition{Field: QueryFieldCreated, Op: QueryOpLt, Time: date("2024-01-31T10:00:00Z")}},
+		{"created:2024-01-31", QueryCondition{Field: QueryFieldCreated, Op: QueryOpGte, Time: date("2024-01-31T00:00:00Z")}},
+
+		{"tag:Billing,LOGIN", QueryCondition{Field: QueryFieldTag, Op: QueryOpIn, Values: []string{"billing", "login"}}},
+		{"course:cs101,cs102", QueryCondition{Field: QueryFieldCourse, Op: QueryOpIn, Values: []string{"cs101", "cs102"}}},
+		{"category:0b8e8a2e-4a6f-4d39-9c4c-6a0d1e2f3a4b", QueryCondition{Field: QueryFieldCategory, Op: QueryOpIn, Values: []string{"0b8e8a2e-4a6f-4d39-9c4c-6a0d1e2f3a4b"}}},
+
+		// Metadata keys keep their case
+		{"metadata.assignmentId:42", QueryCondition{Field: QueryFieldMetadata, Key: "assignmentId", Op: QueryOpIn, Values: []string{"42"}}},
+		{"Metadata.lms_course:!7,8", QueryCondition{Field: QueryFieldMetadata, Key: "lms_course", Op: QueryOpNotIn, Values: []string{"7", "8"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			query, err := ParseTicketQuery(tt.expr, "user-1", now)
+			if err != nil {
+				t.Fatalf("ParseTicketQuery(%q) returned error: %v", tt.expr, err)
+			}
+			if len(query.Conditions) != 1 || len(query.Terms) != 0 {
+				t.Fatalf("ParseTicketQuery(%q) = %+v, want one condition", tt.expr, query)
+			}
+			if got := query.Conditions[0]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTicketQuery(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTicketQueryTerms(t *testing.T) {
+	query, err := ParseTicketQuery(`status:open "login error" T-0042 tag:exam tag:retake`, "user-1", time.Now())
+	if err != nil {
+		t.Fatalf("ParseTicketQuery returned error: %v", err)
+	}
+
+	if want := []string{"login error", "T-0042"}; !reflect.DeepEqual(query.Terms, want) {
+		t.Errorf("Terms = %q, want %q", query.Terms, want)
+	}
+	// Repeated tag fields stay separate conditions, so all of them must match
+	if len(query.Conditions) != 3 || query.Conditions[1].Field != QueryFieldTag || query.Conditions[2].Field != QueryFieldTag {
+		t.Errorf("Conditions = %+v, want status and two tag conditions", query.Conditions)
+	}
+}
+
+func TestParseTicketQueryErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		userID  string
+		wantErr string
+	}{
+		{`status:open "login`, "user-1", "unterminated quote"},
+		{"status:", "user-1", `missing value for "status"`},
+		{"status:,", "user-1", `missing value for "status"`},
+		{"status:pending", "user-1", `invalid status "pending"`},
+		{"type:other", "user-1", `invalid type "other"`},
+		{"priority:>=critical", "user-1", `invalid priority "critical"`},
+		{"priority:>urgent", "user-1", "matches nothing"},
+		{"priority:<low", "user-1", "matches nothing"},
+		{"assigned:me", "", "requires an authenticated user"},
+		{"created:>7y", "user-1", `invalid created value "7y"`},
+		{"updated:yesterday", "user-1", `invalid updated value "yesterday"`},
+		{"category:billing", "user-1", `invalid category ID "billing"`},
+		{"metadata.:1", "user-1", `invalid metadata key ""`},
+		{"metadata.a-b:1", "user-1", `invalid metadata key "a-b"`},
+		{"metadata.a'||'b:1", "user-1", "invalid metadata key"},
+		{"metadata." + strings.Repeat("k", 65) + ":1", "user-1", "invalid metadata key"},
+		{"owner:me", "user-1", `unknown query field "owner"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			query, err := ParseTicketQuery(tt.expr, tt.userID, time.Now())
+			if err == nil {
+				t.Fatalf("ParseTicketQuery(%q) = %+v, want an error", tt.expr, query)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseTicketQuery(%q) error = %q, want it to contain %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
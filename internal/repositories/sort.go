@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"community-support-service/internal/models"
+)
+
+type SortField string
+
+const (
+	SortFieldCreatedAt    SortField = "createdAt"
+	SortFieldUpdatedAt    SortField = "updatedAt"
+	SortFieldLastActivity SortField = "lastActivityAt"
+	SortFieldSLADueAt     SortField = "slaDueAt"
+	SortFieldPriority     SortField = "priority" // by weight: urgent > high > medium > low
+	SortFieldTicketNumber SortField = "ticketNumber"
+)
+
+var ticketSortFields = map[SortField]bool{
+	SortFieldCreatedAt:    true,
+	SortFieldUpdatedAt:    true,
+	SortFieldLastActivity: true,
+	SortFieldSLADueAt:     true,
+	SortFieldPriority:     true,
+	SortFieldTicketNumber: true,
+}
+
+// SortKey orders a listing by one field.
+type SortKey struct {
+	Field      SortField
+	Descending bool
+}
+
+// Sort is an ordered list of sort keys; earlier keys take precedence.
+// Repositories always append the row ID as a final tiebreaker.
+type Sort []SortKey
+
+// DefaultTicketSort lists newest tickets first.
+var DefaultTicketSort = Sort{{Field: SortFieldCreatedAt, Descending: true}}
+
+// ParseTicketSort parses a comma-separated sort specification such as
+// "-priority,slaDueAt", where a leading "-" sorts that key descending. An
+// empty specification yields DefaultTicketSort.
+func ParseTicketSort(spec string) (Sort, error) {
+	if strings.TrimSpace(spec) == "" {
+		return DefaultTicketSort, nil
+	}
+
+	var sort Sort
+	seen := make(map[SortField]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{}
+		if strings.HasPrefix(part, "-") {
+			key.Descending = true
+			part = strings.TrimPrefix(part, "-")
+		} else {
+			part = strings.TrimPrefix(part, "+")
+		}
+
+		key.Field = SortField(part)
+		if !ticketSortFields[key.Field] {
+			return nil, fmt.Errorf("%w %q", ErrInvalidSort, part)
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("%w %q: listed more than once", ErrInvalidSort, part)
+		}
+		seen[key.Field] = true
+		sort = append(sort, key)
+	}
+
+	return sort, nil
+}
+
+// String returns the canonical specification, as accepted by ParseTicketSort.
+func (s Sort) String() string {
+	parts := make([]string, len(s))
+	for i, key := range s {
+		if key.Descending {
+			parts[i] = "-" + string(key.Field)
+		} else {
+			parts[i] = string(key.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// PriorityWeight ranks priorities for sorting: urgent (4) > high (3) >
+// medium (2) > low (1). Unknown priorities weigh 0.
+func PriorityWeight(priority models.TicketPriority) int {
+	for i, p := range priorityRank {
+		if p == priority {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"community-support-service/pkg/middleware"
+	"github.com/google/uuid"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,49}$`)
+
+// AccessService manages roles and role assignments and resolves the grants
+// of authenticated users. Roles and assignments are cached in memory and
+// reloaded once the cache is older than the TTL; changes made through this
+// instance apply at once, changes made elsewhere within a TTL.
+type AccessService struct {
+	repo repositories.RoleRepository
+	ttl  time.Duration
+
+	mu          sync.RWMutex
+	roles       map[string]*models.Role
+	assignments map[string][]*models.RoleAssignment // by user ID
+	loadedAt    time.Time
+}
+
+func NewAccessService(repo repositories.RoleRepository, ttl time.Duration) *AccessService {
+	return &AccessService{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+// Grants returns the user's grants: one for the role named by their token,
+// limited to the token's courses when the role is course-scoped, and one for
+// each role assigned to them. Unknown roles grant nothing.
+func (s *AccessService) Grants(ctx context.Context, user *middleware.UserContext) ([]middleware.Grant, error) {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var grants []middleware.Grant
+	if role, ok := s.roles[user.Role]; ok {
+		grant := middleware.Grant{Role: role.Name, Permissions: role.Permissions, AllCourses: !role.CourseScoped}
+		if role.CourseScoped {
+			grant.CourseIDs = user.Courses
+		}
+		grants = append(grants, grant)
+	}
+	// API clients hold only their own role, service or student when acting on
+	// behalf of one, never the roles assigned to that student
+	if user.ClientID != "" {
+		return grants, nil
+	}
+
+	for _, assignment := range s.assignments[user.UserID] {
+		role, ok := s.roles[assignment.Role]
+		if !ok {
+			continue
+		}
+		grants = append(grants, middleware.Grant{
+			Role:        role.Name,
+			Permissions: role.Permissions,
+			CourseIDs:   assignment.CourseIDs,
+			AllCourses:  len(assignment.CourseIDs) == 0,
+		})
+	}
+	return grants, nil
+}
+
+// CreateRole adds a role with the given permissions.
+func (s *AccessService) CreateRole(ctx context.Context, req *models.CreateRoleRequest) (*models.Role, error) {
+	if !roleNamePattern.MatchString(req.Name) {
+		return nil, &InputError{Message: "Role names must start with a lowercase letter and contain at most 50 lowercase letters, digits, '_' or '-'"}
+	}
+	permissions, err := validPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	role := &models.Role{
+		Name:         req.Name,
+		Description:  req.Description,
+		Permissions:  permissions,
+		CourseScoped: req.CourseScoped,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return role, nil
+}
+
+// UpdateRole changes a role's description, permissions or course scoping.
+// The admin role cannot be changed.
+func (s *AccessService) UpdateRole(ctx context.Context, name string, req *models.UpdateRoleRequest) (*models.Role, error) {
+	role, err := s.repo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == models.RoleAdmin {
+		return nil, &ForbiddenError{Message: "The admin role cannot be changed"}
+	}
+
+	if req.Description != nil {
+		role.Description = req.Description
+	}
+	if req.Permissions != nil {
+		permissions, err := validPermissions(req.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		role.Permissions = permissions
+	}
+	if req.CourseScoped != nil {
+		role.CourseScoped = *req.CourseScoped
+	}
+	role.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return role, nil
+}
+
+// DeleteRole removes a role and its assignments. Built-in roles cannot be
+// deleted.
+func (s *AccessService) DeleteRole(ctx context.Context, name string) error {
+	role, err := s.repo.GetByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if role.BuiltIn {
+		return &ForbiddenError{Message: "Built-in roles cannot be deleted"}
+	}
+
+	if err := s.repo.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// Assign gives a user a role, for the listed courses or, without courses,
+// for all of them.
+func (s *AccessService) Assign(ctx context.Context, req *models.CreateRoleAssignmentRequest, createdBy string) (*models.RoleAssignment, error) {
+	userID := strings.TrimSpace(req.UserID)
+	if userID == "" {
+		return nil, &InputError{Message: "User ID is required"}
+	}
+	if _, err := s.repo.GetByName(ctx, req.Role); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, &InputError{Message: fmt.Sprintf("Unknown role %q", req.Role)}
+		}
+		return nil, err
+	}
+
+	courseIDs := make([]string, 0, len(req.CourseIDs))
+	for _, courseID := range req.CourseIDs {
+		courseID = strings.TrimSpace(courseID)
+		if courseID != "" && !slices.Contains(courseIDs, courseID) {
+			courseIDs = append(courseIDs, courseID)
+		}
+	}
+
+	assignment := &models.RoleAssignment{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Role:      req.Role,
+		CourseIDs: courseIDs,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateAssignment(ctx, assignment); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return assignment, nil
+}
+
+// Unassign removes a role assignment.
+func (s *AccessService) Unassign(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.repo.GetAssignment(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteAssignment(ctx, id); err != nil {
+		return err
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// invalidate makes the next lookup reload roles and assignments.
+func (s *AccessService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *AccessService) ensureLoaded(ctx context.Context) error {
+	s.mu.RLock()
+	fresh := s.roles != nil && time.Since(s.loadedAt) < s.ttl
+	s.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	roleList, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return s.staleOrError(fmt.Errorf("failed to load roles: %w", err))
+	}
+	assignmentList, err := s.repo.GetAssignments(ctx, nil)
+	if err != nil {
+		return s.staleOrError(fmt.Errorf("failed to load role assignments: %w", err))
+	}
+
+	roles := make(map[string]*models.Role, len(roleList))
+	for _, role := range roleList {
+		roles[role.Name] = role
+	}
+	assignments := make(map[string][]*models.RoleAssignment)
+	for _, assignment := range assignmentList {
+		assignments[assignment.UserID] = append(assignments[assignment.UserID], assignment)
+	}
+
+	s.mu.Lock()
+	s.roles = roles
+	s.assignments = assignments
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// staleOrError keeps serving previously loaded roles when a reload fails,
+// and only reports the error when nothing was ever loaded.
+func (s *AccessService) staleOrError(err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.roles == nil {
+		return err
+	}
+	// Retry after another TTL rather than on every request
+	s.loadedAt = time.Now()
+	fmt.Printf("%v\n", err)
+	return nil
+}
+
+func validPermissions(permissions []string) ([]string, error) {
+	valid := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !slices.Contains(models.Permissions, permission) {
+			return nil, &InputError{Message: fmt.Sprintf("Unknown permission %q", permission)}
+		}
+		if !slices.Contains(valid, permission) {
+			valid = append(valid, permission)
+		}
+	}
+	return valid, nil
+}
\ No newline at end of file
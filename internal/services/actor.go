@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"slices"
+
+	"community-support-service/internal/models"
+)
+
+type actorContextKey struct{}
+
+// Actor is the user an operation is performed on behalf of. HTTP handlers
+// take it from the authenticated request; the Slack webhook, email ingestion
+// and background workers attach their own.
+type Actor struct {
+	UserID   string
+	Email    string
+	Role     string
+	Name     string
+	FullName string
+	Teams    []string
+	Courses  []string // courses an instructor is assigned to
+	Grants   []Grant
+}
+
+// Grant is the set of permissions an actor holds through one role, for every
+// course or only for the listed ones.
+type Grant struct {
+	Role        string
+	Permissions []string
+	CourseIDs   []string
+	AllCourses  bool
+}
+
+// HasPermission reports whether any of the actor's grants holds permission,
+// whatever courses it covers.
+func (a *Actor) HasPermission(permission string) bool {
+	for _, grant := range a.Grants {
+		if slices.Contains(grant.Permissions, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// DisplayName returns the actor's full name from the token, falling back to
+// their user name.
+func (a *Actor) DisplayName() string {
+	if a.FullName != "" {
+		return a.FullName
+	}
+	return a.Name
+}
+
+// WithActor returns a copy of ctx carrying actor.
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor attached by WithActor.
+func ActorFromContext(ctx context.Context) (*Actor, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(*Actor)
+	return actor, ok && actor != nil && actor.UserID != ""
+}
+
+// WatcherRoleFor is the watcher role used when actor subscribes to a ticket.
+// Anyone who may read internal notes watches as staff, so that they are
+// notified of them.
+func WatcherRoleFor(actor *Actor) models.WatcherRole {
+	switch {
+	case actor.Role == models.RoleAdmin:
+		return models.WatcherRoleAdmin
+	case actor.HasPermission(models.PermTicketsInternal):
+		return models.WatcherRoleInstructor
+	default:
+		return models.WatcherRoleStudent
+	}
+}
\ No newline at end of file
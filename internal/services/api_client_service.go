@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"community-support-service/pkg/middleware"
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefix marks the service's API keys, so leaked keys are easy to spot.
+// A key is "css_<keyPrefix>_<secret>"; keyPrefix identifies the client.
+const apiKeyPrefix = "css_"
+
+// APIClientService manages service API clients and implements the
+// middleware's APIClientStore.
+type APIClientService struct {
+	repo repositories.APIClientRepository
+}
+
+func NewAPIClientService(repo repositories.APIClientRepository) *APIClientService {
+	return &APIClientService{repo: repo}
+}
+
+// Create registers a client and returns it with its key. The key is only
+// ever available here and from RotateKey.
+func (s *APIClientService) Create(ctx context.Context, req *models.CreateAPIClientRequest, createdBy string) (*models.APIClient, string, error) {
+	scopes, err := validScopes(req.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, "", &InputError{Message: "Client name is required"}
+	}
+
+	key, prefix, hash, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	client := &models.APIClient{
+		ID:          uuid.New(),
+		Name:        name,
+		Description: req.Description,
+		KeyPrefix:   prefix,
+		KeyHash:     hash,
+		Scopes:      scopes,
+		IsActive:    true,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, client); err != nil {
+		return nil, "", err
+	}
+	return client, key, nil
+}
+
+// Update changes a client's name, description, scopes or active flag.
+// Deactivating a client rejects its key from the next request on.
+func (s *APIClientService) Update(ctx context.Context, id uuid.UUID, req *models.UpdateAPIClientRequest) (*models.APIClient, error) {
+	client, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, &InputError{Message: "Client name is required"}
+		}
+		client.Name = name
+	}
+	if req.Description != nil {
+		client.Description = req.Description
+	}
+	if req.Scopes != nil {
+		scopes, err := validScopes(req.Scopes)
+		if err != nil {
+			return nil, err
+		}
+		client.Scopes = scopes
+	}
+	if req.IsActive != nil {
+		client.IsActive = *req.IsActive
+	}
+	client.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(ctx, client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// RotateKey replaces a client's key; the old key stops working at once.
+func (s *APIClientService) RotateKey(ctx context.Context, id uuid.UUID) (*models.APIClient, string, error) {
+	client, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key, prefix, hash, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	client.KeyPrefix = prefix
+	client.KeyHash = hash
+	client.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(ctx, client); err != nil {
+		return nil, "", err
+	}
+	return client, key, nil
+}
+
+// AuthenticateKey returns the active client owning key, or nil if there is
+// none.
+func (s *APIClientService) AuthenticateKey(ctx context.Context, key string) (*middleware.APIClient, error) {
+	rest, ok := strings.CutPrefix(key, apiKeyPrefix)
+	if !ok {
+		return nil, nil
+	}
+	prefix, _, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" {
+		return nil, nil
+	}
+
+	client, err := s.repo.GetByKeyPrefix(ctx, prefix)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !client.IsActive || subtle.ConstantTimeCompare([]byte(hashAPIKey(key)), []byte(client.KeyHash)) != 1 {
+		return nil, nil
+	}
+
+	return &middleware.APIClient{
+		ID:     client.ID.String(),
+		Name:   client.Name,
+		Scopes: client.Scopes,
+	}, nil
+}
+
+// RecordUsage adds a request to the client's audit trail. Failures are
+// logged rather than failing a request that has already been answered.
+func (s *APIClientService) RecordUsage(ctx context.Context, usage *middleware.APIClientUsage) {
+	clientID, err := uuid.Parse(usage.ClientID)
+	if err != nil {
+		fmt.Printf("Failed to record API client usage: %v\n", err)
+		return
+	}
+
+	record := &models.APIClientUsage{
+		ID:         uuid.New(),
+		ClientID:   clientID,
+		Method:     usage.Method,
+		Path:       usage.Path,
+		Scope:      optionalString(usage.Scope),
+		OnBehalfOf: optionalString(usage.OnBehalfOf),
+		Status:     usage.Status,
+		RequestID:  optionalString(usage.RequestID),
+		RemoteAddr: optionalString(usage.RemoteAddr),
+		CreatedAt:  usage.At,
+	}
+	if err := s.repo.RecordUsage(ctx, record); err != nil {
+		fmt.Printf("Failed to record API client usage: %v\n", err)
+	}
+}
+
+func validScopes(scopes []string) ([]string, error) {
+	valid := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !slices.Contains(models.APIScopes, scope) {
+			return nil, &InputError{Message: fmt.Sprintf("Unknown scope %q", scope)}
+		}
+		if !slices.Contains(valid, scope) {
+			valid = append(valid, scope)
+		}
+	}
+	if len(valid) == 0 {
+		return nil, &InputError{Message: "At least one scope is required"}
+	}
+	return valid, nil
+}
+
+// generateAPIKey returns a new key with its lookup prefix and hash. Keys are
+// random 256-bit secrets, so a plain SHA-256 is enough to store them.
+func generateAPIKey() (key, prefix, hash string, err error) {
+	id := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(id)
+	key = apiKeyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return key, prefix, hashAPIKey(key), nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
\ No newline at end of file
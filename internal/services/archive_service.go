@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// archiveBatchSize is how many tickets each archiving statement moves, so a
+// large backlog is archived in short transactions.
+const archiveBatchSize = 200
+
+// ArchiveService moves tickets that have been closed for a number of months
+// to the archive, and reads archived tickets back for the actors who may see
+// them.
+type ArchiveService struct {
+	repo        repositories.ArchiveRepository
+	afterMonths int
+}
+
+// NewArchiveService archives tickets closed for afterMonths months; 0 turns
+// archiving off.
+func NewArchiveService(repo repositories.ArchiveRepository, afterMonths int) *ArchiveService {
+	return &ArchiveService{
+		repo:        repo,
+		afterMonths: afterMonths,
+	}
+}
+
+// Run archives due tickets now and then every interval until ctx is done.
+// Failures are logged and retried on the next run.
+func (s *ArchiveService) Run(ctx context.Context, interval time.Duration) {
+	if s.afterMonths <= 0 || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.ArchiveClosed(ctx, time.Now()); err != nil {
+			fmt.Printf("Failed to archive closed tickets: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ArchiveClosed archives every ticket closed more than the configured number
+// of months before now and returns how many it archived.
+func (s *ArchiveService) ArchiveClosed(ctx context.Context, now time.Time) (int64, error) {
+	closedBefore := now.AddDate(0, -s.afterMonths, 0)
+
+	var total int64
+	for {
+		archived, err := s.repo.ArchiveClosed(ctx, closedBefore, archiveBatchSize)
+		total += archived
+		if err != nil || archived < archiveBatchSize {
+			return total, err
+		}
+	}
+}
+
+// Get returns an archived ticket the actor may view. Internal comments and
+// the ticket's history are only included for actors who may read internal
+// notes on the ticket; deleted comments and their attachments never are.
+func (s *ArchiveService) Get(ctx context.Context, id uuid.UUID) (*models.ArchivedTicket, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	archived, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := Authorize(actor, ActionView, archived.Ticket); err != nil {
+		return nil, err
+	}
+
+	includeInternal := Can(actor, ActionViewInternal, archived.Ticket)
+	visible := make(map[uuid.UUID]bool, len(archived.Comments))
+	comments := make([]*models.TicketComment, 0, len(archived.Comments))
+	for _, comment := range archived.Comments {
+		if comment.DeletedAt == nil && (includeInternal || !comment.IsInternal) {
+			visible[comment.ID] = true
+			comments = append(comments, comment)
+		}
+	}
+	attachments := make([]*models.Attachment, 0, len(archived.Attachments))
+	for _, attachment := range archived.Attachments {
+		if attachment.CommentID == nil || visible[*attachment.CommentID] {
+			attachments = append(attachments, attachment)
+		}
+	}
+
+	archived.Comments = comments
+	archived.Attachments = attachments
+	if !includeInternal {
+		archived.History = nil
+	}
+	return archived, nil
+}
+
+// List returns archived tickets matching filters, limited to the ones the
+// actor may view in the same way as TicketService.List.
+func (s *ArchiveService) List(ctx context.Context, filters repositories.ArchiveFilters) ([]*models.ArchivedTicket, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	if filters.StudentID == nil || *filters.StudentID != actor.UserID {
+		if !actor.HasPermission(models.PermTicketsRead) {
+			filters.StudentID = &actor.UserID
+		}
+		filters.Scope = ScopeFor(actor)
+	}
+	return s.repo.List(ctx, filters)
+}
\ No newline at end of file
@@ -0,0 +1,51 @@
+package services
+
+import "errors"
+
+var (
+	// ErrUnauthenticated is returned when an operation needs an actor and the
+	// context carries none.
+	ErrUnauthenticated = errors.New("user information not found in request")
+
+	// ErrForbidden matches every ForbiddenError.
+	ErrForbidden = errors.New("access denied")
+
+	// ErrInvalidInput matches every InputError.
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrNotAnIncident is returned when an incident update is broadcast from
+	// a ticket that is not marked as an incident.
+	ErrNotAnIncident = errors.New("only incident tickets can broadcast updates")
+
+	// ErrAlreadyWatching is returned when adding a watcher who already
+	// follows the ticket.
+	ErrAlreadyWatching = errors.New("user is already watching this ticket")
+)
+
+// ForbiddenError is returned when the actor may not perform an operation.
+// Message explains why and is safe to show to the caller.
+type ForbiddenError struct {
+	Message string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+func (e *ForbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// InputError is returned when an operation's input refers to something that
+// does not exist or breaks a business rule the request validator cannot see.
+type InputError struct {
+	Message string
+}
+
+func (e *InputError) Error() string {
+	return e.Message
+}
+
+func (e *InputError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
\ No newline at end of file
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"community-support-service/pkg/middleware"
+)
+
+const (
+	// idempotencyClaimTimeout bounds how long a key stays claimed by a
+	// request that never completes, e.g. because its instance stopped.
+	idempotencyClaimTimeout = 5 * time.Minute
+	// idempotencyPurgeInterval is how often expired keys are deleted.
+	idempotencyPurgeInterval = 10 * time.Minute
+)
+
+// IdempotencyService keeps the responses to requests sent with an
+// Idempotency-Key for the TTL and implements the middleware's
+// IdempotencyStore. Keys are stored in Postgres, so a retry reaching another
+// instance is still recognised.
+type IdempotencyService struct {
+	repo repositories.IdempotencyRepository
+	ttl  time.Duration
+
+	mu       sync.Mutex
+	purgedAt time.Time
+}
+
+func NewIdempotencyService(repo repositories.IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo:     repo,
+		ttl:      ttl,
+		purgedAt: time.Now(),
+	}
+}
+
+// Begin claims the key for a request, or returns the stored response of the
+// request that claimed it first.
+func (s *IdempotencyService) Begin(ctx context.Context, owner, key, fingerprint string) (*middleware.IdempotentResponse, error) {
+	now := time.Now()
+	s.purgeExpired(ctx, now)
+
+	claimed, err := s.repo.Claim(ctx, &models.IdempotencyKey{
+		UserID:      owner,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(idempotencyClaimTimeout),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	record, err := s.repo.Get(ctx, owner, key)
+	if errors.Is(err, repositories.ErrNotFound) {
+		// Released by the first request just now; the client may retry
+		return nil, middleware.ErrIdempotencyInFlight
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.Fingerprint != fingerprint {
+		return nil, middleware.ErrIdempotencyMismatch
+	}
+	if record.Status == nil {
+		return nil, middleware.ErrIdempotencyInFlight
+	}
+
+	response := &middleware.IdempotentResponse{
+		Status: *record.Status,
+		Body:   record.Body,
+	}
+	if record.ContentType != nil {
+		response.ContentType = *record.ContentType
+	}
+	if record.ETag != nil {
+		response.ETag = *record.ETag
+	}
+	return response, nil
+}
+
+// Finish stores the response for the TTL, or releases the key when there is
+// no response to keep. Failures are logged; the worst outcome is a retry
+// running the request again.
+func (s *IdempotencyService) Finish(ctx context.Context, owner, key, fingerprint string, response *middleware.IdempotentResponse) {
+	if response == nil {
+		if err := s.repo.Release(ctx, owner, key); err != nil {
+			fmt.Printf("Failed to release idempotency key: %v\n", err)
+		}
+		return
+	}
+
+	record := &models.IdempotencyKey{
+		UserID:      owner,
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      &response.Status,
+		ContentType: optionalString(response.ContentType),
+		ETag:        optionalString(response.ETag),
+		Body:        response.Body,
+		ExpiresAt:   time.Now().Add(s.ttl),
+	}
+	if err := s.repo.Complete(ctx, record); err != nil {
+		fmt.Printf("Failed to store idempotent response: %v\n", err)
+	}
+}
+
+// purgeExpired deletes expired keys once per purge interval.
+func (s *IdempotencyService) purgeExpired(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	due := now.Sub(s.purgedAt) >= idempotencyPurgeInterval
+	if due {
+		s.purgedAt = now
+	}
+	s.mu.Unlock()
+
+	if due {
+		if _, err := s.repo.DeleteExpired(ctx, now); err != nil {
+			fmt.Printf("Failed to purge expired idempotency keys: %v\n", err)
+		}
+	}
+}
\ No newline at end of file
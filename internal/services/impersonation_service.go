@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"community-support-service/pkg/middleware"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ImpersonationService starts and ends admins' read-only "view as" sessions
+// and implements the middleware's ImpersonationStore.
+type ImpersonationService struct {
+	repo repositories.ImpersonationRepository
+	ttl  time.Duration
+
+	mu     sync.Mutex
+	claims map[string]*models.UserClaims // last claims saved per user
+}
+
+func NewImpersonationService(repo repositories.ImpersonationRepository, ttl time.Duration) *ImpersonationService {
+	return &ImpersonationService{
+		repo:   repo,
+		ttl:    ttl,
+		claims: make(map[string]*models.UserClaims),
+	}
+}
+
+// Start opens a session for adminID to view the API as req.UserID, with the
+// email, teams and courses from the user's last token. A user who has not
+// used the service yet is viewed without them. The session expires after the
+// TTL.
+func (s *ImpersonationService) Start(ctx context.Context, req *models.StartImpersonationRequest, adminID string) (*models.ImpersonationSession, error) {
+	userID := strings.TrimSpace(req.UserID)
+	reason := strings.TrimSpace(req.Reason)
+	if userID == "" {
+		return nil, &InputError{Message: "User ID is required"}
+	}
+	if reason == "" {
+		return nil, &InputError{Message: "A reason is required to impersonate a user"}
+	}
+	if userID == adminID {
+		return nil, &InputError{Message: "You cannot impersonate yourself"}
+	}
+
+	now := time.Now()
+	session := &models.ImpersonationSession{
+		ID:        uuid.New(),
+		AdminID:   adminID,
+		UserID:    userID,
+		Reason:    reason,
+		Teams:     pq.StringArray{},
+		Courses:   pq.StringArray{},
+		ExpiresAt: now.Add(s.ttl),
+		CreatedAt: now,
+	}
+	claims, err := s.repo.GetClaims(ctx, userID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	if claims != nil {
+		session.Email = claims.Email
+		session.Teams = claims.Teams
+		session.Courses = claims.Courses
+	}
+	if err := s.repo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// End closes a session before it expires. Ending a session that is no longer
+// active changes nothing.
+func (s *ImpersonationService) End(ctx context.Context, id uuid.UUID) (*models.ImpersonationSession, error) {
+	session, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return session, nil
+	}
+	if err := s.repo.End(ctx, id, now); err != nil {
+		return nil, err
+	}
+	session.EndedAt = &now
+	return session, nil
+}
+
+// ActiveSession returns the session with the given ID if it can still be
+// used, or nil.
+func (s *ImpersonationService) ActiveSession(ctx context.Context, id string) (*middleware.ImpersonationSession, error) {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	session, err := s.repo.GetByID(ctx, sessionID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsActive(time.Now()) {
+		return nil, nil
+	}
+
+	viewed := &middleware.ImpersonationSession{
+		ID:      session.ID.String(),
+		AdminID: session.AdminID,
+		UserID:  session.UserID,
+		Teams:   session.Teams,
+		Courses: session.Courses,
+	}
+	if session.Email != nil {
+		viewed.Email = *session.Email
+	}
+	return viewed, nil
+}
+
+// RecordRequest adds a request to the session's audit trail. Failures are
+// logged rather than failing a request that has already been answered.
+func (s *ImpersonationService) RecordRequest(ctx context.Context, request *middleware.ImpersonatedRequest) {
+	sessionID, err := uuid.Parse(request.SessionID)
+	if err != nil {
+		fmt.Printf("Failed to record impersonated request: %v\n", err)
+		return
+	}
+
+	record := &models.ImpersonatedRequest{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		AdminID:   request.AdminID,
+		UserID:    request.UserID,
+		Method:    request.Method,
+		Path:      request.Path,
+		Status:    request.Status,
+		RequestID: optionalString(request.RequestID),
+		CreatedAt: request.At,
+	}
+	if err := s.repo.RecordRequest(ctx, record); err != nil {
+		fmt.Printf("Failed to record impersonated request: %v\n", err)
+	}
+}
+
+// RecordClaims saves the email, teams and courses of the user's token when
+// they differ from the ones last saved by this instance. Failures are logged
+// rather than failing the request.
+func (s *ImpersonationService) RecordClaims(ctx context.Context, user *middleware.UserContext) {
+	claims := &models.UserClaims{
+		UserID:    user.UserID,
+		Email:     optionalString(user.Email),
+		Teams:     append(pq.StringArray{}, user.Teams...),
+		Courses:   append(pq.StringArray{}, user.Courses...),
+		UpdatedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	last, ok := s.claims[user.UserID]
+	s.mu.Unlock()
+	if ok && sameClaims(last, claims) {
+		return
+	}
+
+	if err := s.repo.SaveClaims(ctx, claims); err != nil {
+		fmt.Printf("Failed to save user claims: %v\n", err)
+		return
+	}
+	s.mu.Lock()
+	s.claims[user.UserID] = claims
+	s.mu.Unlock()
+}
+
+func sameClaims(a, b *models.UserClaims) bool {
+	return (a.Email == nil) == (b.Email == nil) && (a.Email == nil || *a.Email == *b.Email) &&
+		slices.Equal(a.Teams, b.Teams) && slices.Equal(a.Courses, b.Courses)
+}
\ No newline at end of file
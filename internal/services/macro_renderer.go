@@ -0,0 +1,69 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"community-support-service/internal/models"
+)
+
+// macroVariablePattern matches placeholders such as {{ticket.TicketNumber}}
+// or {{ student.name }}.
+var macroVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z]+\.[A-Za-z]+)\s*\}\}`)
+
+// macroVariables lists the placeholders a macro may use. Names are matched
+// case-insensitively, so {{ticket.ticketNumber}} works as well.
+var macroVariables = map[string]func(MacroContext) string{
+	"ticket.ticketnumber": func(c MacroContext) string { return c.Ticket.TicketNumber },
+	"ticket.title":        func(c MacroContext) string { return c.Ticket.Title },
+	"ticket.status":       func(c MacroContext) string { return string(c.Ticket.Status) },
+	"ticket.priority":     func(c MacroContext) string { return string(c.Ticket.Priority) },
+	"ticket.type":         func(c MacroContext) string { return string(c.Ticket.Type) },
+	"ticket.courseid":     func(c MacroContext) string { return derefString(c.Ticket.CourseID) },
+	"student.id":          func(c MacroContext) string { return c.Ticket.StudentID },
+	"student.name":        func(c MacroContext) string { return c.StudentName },
+	"student.email":       func(c MacroContext) string { return c.StudentEmail },
+	"agent.id":            func(c MacroContext) string { return c.AgentID },
+	"agent.email":         func(c MacroContext) string { return c.AgentEmail },
+}
+
+// MacroContext carries the values substituted into a macro's reply text.
+type MacroContext struct {
+	Ticket       *models.Ticket
+	StudentName  string
+	StudentEmail string
+	AgentID      string
+	AgentEmail   string
+}
+
+// RenderMacro substitutes every known placeholder in content. Unknown
+// placeholders are left as written; UnknownMacroVariables reports them when
+// the macro is saved.
+func RenderMacro(content string, ctx MacroContext) string {
+	return macroVariablePattern.ReplaceAllStringFunc(content, func(match string) string {
+		name := macroVariablePattern.FindStringSubmatch(match)[1]
+		if value, ok := macroVariables[strings.ToLower(name)]; ok {
+			return value(ctx)
+		}
+		return match
+	})
+}
+
+// UnknownMacroVariables returns the placeholders in content that RenderMacro
+// cannot fill.
+func UnknownMacroVariables(content string) []string {
+	var unknown []string
+	for _, match := range macroVariablePattern.FindAllStringSubmatch(content, -1) {
+		if _, ok := macroVariables[strings.ToLower(match[1])]; !ok {
+			unknown = append(unknown, match[1])
+		}
+	}
+	return unknown
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
\ No newline at end of file
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -26,7 +27,7 @@ func NewNotificationService(cfg *config.Config) *NotificationService {
 	}
 }
 
-func (s *NotificationService) SendTicketCreatedNotifications(ctx context.Context, ticket *models.Ticket, studentEmail string) error {
+func (s *NotificationService) SendTicketCreatedNotifications(ctx context.Context, ticket *models.Ticket, studentEmail string, watchers []*models.TicketWatcher) error {
 	// Send email to Kemuko admins
 	if err := s.sendAdminEmailNotification(ctx, ticket, studentEmail); err != nil {
 		return fmt.Errorf("failed to send admin email notification: %w", err)
@@ -42,10 +43,16 @@ func (s *NotificationService) SendTicketCreatedNotifications(ctx context.Context
 		return fmt.Errorf("failed to send slack notification: %w", err)
 	}
 
+	// Let anyone already watching the ticket know it was opened
+	recipients := watcherEmails(watchers, ticket.StudentID, false)
+	if err := s.sendWatcherEmails(ctx, ticket, recipients, fmt.Sprintf("Kemuko Support - New Ticket #%s", ticket.TicketNumber), "ticket_created_watcher_notification", nil); err != nil {
+		return fmt.Errorf("failed to send watcher notifications: %w", err)
+	}
+
 	return nil
 }
 
-func (s *NotificationService) SendAdminReplyNotification(ctx context.Context, ticket *models.Ticket, comment *models.TicketComment, studentEmail string) error {
+func (s *NotificationService) SendAdminReplyNotification(ctx context.Context, ticket *models.Ticket, comment *models.TicketComment, studentEmail string, watchers []*models.TicketWatcher) error {
 	// Send email to student about admin reply
 	emailReq := &models.EmailNotificationRequest{
 		To:         []string{studentEmail},
@@ -62,7 +69,178 @@ func (s *NotificationService) SendAdminReplyNotification(ctx context.Context, ti
 		ReplyTo: &s.config.Notifications.AdminEmail,
 	}
 
-	return s.sendEmailRequest(ctx, emailReq)
+	if !comment.IsInternal {
+		if err := s.sendEmailRequest(ctx, emailReq); err != nil {
+			return err
+		}
+	}
+
+	// The student was emailed above, so only the other watchers remain
+	var others []*models.TicketWatcher
+	for _, watcher := range watchers {
+		if watcher.UserID != ticket.StudentID {
+			others = append(others, watcher)
+		}
+	}
+	return s.SendCommentNotifications(ctx, ticket, comment, others)
+}
+
+// SendCommentNotifications emails the ticket's watchers about a new comment.
+// Internal comments only reach staff watchers, and the author is never
+// notified about their own comment. Public replies from the student are also
+// posted to the admin Slack channel.
+func (s *NotificationService) SendCommentNotifications(ctx context.Context, ticket *models.Ticket, comment *models.TicketComment, watchers []*models.TicketWatcher) error {
+	recipients := watcherEmails(watchers, comment.UserID, comment.IsInternal)
+	templateData := map[string]interface{}{
+		"commentMessage": comment.Content,
+		"isInternal":     comment.IsInternal,
+	}
+	if err := s.sendWatcherEmails(ctx, ticket, recipients, fmt.Sprintf("Kemuko Support - New Comment on Ticket #%s", ticket.TicketNumber), "ticket_comment_notification", templateData); err != nil {
+		return fmt.Errorf("failed to send watcher notifications: %w", err)
+	}
+
+	if comment.IsInternal || comment.UserID != ticket.StudentID {
+		return nil
+	}
+
+	slackReq := &models.SlackNotificationRequest{
+		Channel: s.config.Notifications.SlackChannel,
+		Message: fmt.Sprintf("💬 Student replied on Ticket #%s", ticket.TicketNumber),
+		Blocks: []models.SlackBlock{
+			{
+				Type: "section",
+				Text: &models.SlackText{
+					Type: "mrkdwn",
+					Text: fmt.Sprintf("*💬 Student Reply*\n*Ticket:* #%s\n*Title:* %s\n*Message:* %s",
+						ticket.TicketNumber, ticket.Title, comment.Content),
+				},
+			},
+		},
+		TemplateData: map[string]interface{}{
+			"ticketId":     ticket.ID.String(),
+			"ticketNumber": ticket.TicketNumber,
+		},
+	}
+	if err := s.sendSlackRequest(ctx, slackReq); err != nil {
+		return fmt.Errorf("failed to send slack notification: %w", err)
+	}
+
+	return nil
+}
+
+// SendTicketCompletedNotifications posts the resolution to the admin Slack
+// channel and emails every watcher, including the student.
+func (s *NotificationService) SendTicketCompletedNotifications(ctx context.Context, ticket *models.Ticket, resolverEmail string, watchers []*models.TicketWatcher) error {
+	slackReq := &models.SlackNotificationRequest{
+		Channel: s.config.Notifications.SlackChannel,
+		Message: fmt.Sprintf("✅ Ticket Completed"),
+		Blocks: []models.SlackBlock{
+			{
+				Type: "section",
+				Text: &models.SlackText{
+					Type: "mrkdwn",
+					Text: fmt.Sprintf("*✅ Ticket Completed*\n*Ticket:* #%s\n*Title:* %s\n*Completed by:* %s\n*Status:* %s",
+						ticket.TicketNumber, ticket.Title, resolverEmail, ticket.Status),
+				},
+			},
+		},
+		TemplateData: map[string]interface{}{
+			"ticketId":     ticket.ID.String(),
+			"ticketNumber": ticket.TicketNumber,
+			"status":       string(ticket.Status),
+		},
+	}
+	if err := s.sendSlackRequest(ctx, slackReq); err != nil {
+		return fmt.Errorf("failed to send slack notification: %w", err)
+	}
+
+	recipients := watcherEmails(watchers, "", false)
+	templateData := map[string]interface{}{
+		"status": string(ticket.Status),
+	}
+	if err := s.sendWatcherEmails(ctx, ticket, recipients, fmt.Sprintf("Kemuko Support - Ticket Resolved #%s", ticket.TicketNumber), "ticket_resolved_notification", templateData); err != nil {
+		return fmt.Errorf("failed to send watcher notifications: %w", err)
+	}
+
+	return nil
+}
+
+// SendTicketMergedNotifications tells the watchers of a duplicate, including
+// its student, that their ticket was merged and where to follow it now.
+func (s *NotificationService) SendTicketMergedNotifications(ctx context.Context, primary *models.Ticket, duplicate *models.Ticket, watchers []*models.TicketWatcher) error {
+	recipients := watcherEmails(watchers, "", false)
+	templateData := map[string]interface{}{
+		"primaryTicketNumber": primary.TicketNumber,
+		"primaryTicketTitle":  primary.Title,
+		"primaryTicketUrl":    fmt.Sprintf("%s/tickets/%s", s.config.Frontend.BaseURL, primary.ID),
+	}
+	if err := s.sendWatcherEmails(ctx, duplicate, recipients, fmt.Sprintf("Kemuko Support - Ticket #%s Merged into #%s", duplicate.TicketNumber, primary.TicketNumber), "ticket_merged_notification", templateData); err != nil {
+		return fmt.Errorf("failed to send merge notifications: %w", err)
+	}
+	return nil
+}
+
+// SendParentResolvedNotifications tells the watchers of a child ticket that it
+// was resolved together with its parent. Unlike a direct completion no Slack
+// message is posted, since an incident may resolve many children at once.
+func (s *NotificationService) SendParentResolvedNotifications(ctx context.Context, parent *models.Ticket, child *models.Ticket, watchers []*models.TicketWatcher) error {
+	recipients := watcherEmails(watchers, "", false)
+	templateData := map[string]interface{}{
+		"parentTicketNumber": parent.TicketNumber,
+		"parentTicketTitle":  parent.Title,
+	}
+	if err := s.sendWatcherEmails(ctx, child, recipients, fmt.Sprintf("Kemuko Support - Ticket #%s Resolved", child.TicketNumber), "ticket_parent_resolved_notification", templateData); err != nil {
+		return fmt.Errorf("failed to send parent resolution notifications: %w", err)
+	}
+	return nil
+}
+
+// watcherEmails returns the distinct addresses of watchers who should hear
+// about an event, skipping excludeUserID and, for internal events, any
+// watcher who cannot see internal comments.
+func watcherEmails(watchers []*models.TicketWatcher, excludeUserID string, internal bool) []string {
+	seen := make(map[string]bool)
+	var emails []string
+	for _, watcher := range watchers {
+		if watcher.UserID == excludeUserID || watcher.Email == "" || seen[watcher.Email] {
+			continue
+		}
+		if internal && !watcher.CanSeeInternal() {
+			continue
+		}
+		seen[watcher.Email] = true
+		emails = append(emails, watcher.Email)
+	}
+	return emails
+}
+
+// sendWatcherEmails sends one email per recipient so watchers don't see each
+// other's addresses. extraData is merged into the common ticket template data.
+func (s *NotificationService) sendWatcherEmails(ctx context.Context, ticket *models.Ticket, recipients []string, subject, templateID string, extraData map[string]interface{}) error {
+	var errs []error
+	for _, recipient := range recipients {
+		templateData := map[string]interface{}{
+			"ticketNumber": ticket.TicketNumber,
+			"ticketTitle":  ticket.Title,
+			"ticketUrl":    fmt.Sprintf("%s/tickets/%s", s.config.Frontend.BaseURL, ticket.ID),
+			"platformName": "Kemuko",
+		}
+		for key, value := range extraData {
+			templateData[key] = value
+		}
+
+		emailReq := &models.EmailNotificationRequest{
+			To:           []string{recipient},
+			Subject:      subject,
+			TemplateID:   templateID,
+			TemplateData: templateData,
+			ReplyTo:      &s.config.Notifications.AdminEmail,
+		}
+		if err := s.sendEmailRequest(ctx, emailReq); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func (s *NotificationService) sendAdminEmailNotification(ctx context.Context, ticket *models.Ticket, studentEmail string) error {
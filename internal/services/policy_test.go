@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"community-support-service/internal/models"
+)
+
+// The grants below mirror the built-in roles seeded by
+// migrations/017_create_roles.up.sql.
+var (
+	instructorPermissions = []string{
+		models.PermTicketsRead, models.PermTicketsComment, models.PermTicketsInternal, models.PermTicketsUpdate,
+		models.PermTicketsAssign, models.PermTicketsResolve, models.PermWatchersManage, models.PermMacrosUse, models.PermSlackReply,
+	}
+	adminPermissions = []string{
+		models.PermTicketsRead, models.PermTicketsComment, models.PermTicketsInternal, models.PermTicketsUpdate,
+		models.PermTicketsAssign, models.PermTicketsResolve, models.PermTicketsDelete, models.PermWatchersManage,
+		models.PermMacrosUse, models.PermMacrosShare, models.PermCatalogManage, models.PermReportsRead,
+		models.PermAccessManage, models.PermSlackReply,
+	}
+	servicePermissions = []string{models.PermTicketsRead, models.PermTicketsComment}
+)
+
+func instructorIn(userID string, courseIDs ...string) *Actor {
+	return &Actor{
+		UserID: userID,
+		Role:   "instructor",
+		Grants: []Grant{{Role: "instructor", Permissions: instructorPermissions, CourseIDs: courseIDs}},
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	courseID := "course-1"
+	assignee := "instructor-assigned"
+	ticket := &models.Ticket{StudentID: "student-1", CourseID: &courseID}
+	assigned := &models.Ticket{StudentID: "student-1", CourseID: &courseID, InstructorID: &assignee}
+
+	actors := map[string]*Actor{
+		"owner":                    {UserID: "student-1", Role: "student", Grants: []Grant{{Role: "student"}}},
+		"other student":            {UserID: "student-2", Role: "student", Grants: []Grant{{Role: "student"}}},
+		"instructor in course":     instructorIn("instructor-1", courseID),
+		"instructor out of course": instructorIn("instructor-2", "course-2"),
+		"assigned instructor":      instructorIn(assignee, "course-2"),
+		"admin": {
+			UserID: "admin-1",
+			Role:   models.RoleAdmin,
+			Grants: []Grant{{Role: models.RoleAdmin, Permissions: adminPermissions, AllCourses: true}},
+		},
+		"service": {
+			UserID: "client-1",
+			Role:   "service",
+			Grants: []Grant{{Role: "service", Permissions: servicePermissions, AllCourses: true}},
+		},
+		"anonymous": {},
+	}
+
+	actions := []Action{ActionView, ActionComment, ActionViewInternal, ActionUpdate, ActionAssign, ActionResolve, ActionDelete}
+
+	// allowed lists the actions each actor may perform; every other action
+	// must be refused.
+	tests := []struct {
+		actor   string
+		ticket  *models.Ticket
+		allowed []Action
+	}{
+		{"owner", ticket, []Action{ActionView, ActionComment}},
+		{"other student", ticket, nil},
+		{"instructor in course", ticket, []Action{ActionView, ActionComment, ActionViewInternal, ActionUpdate, ActionAssign, ActionResolve}},
+		{"instructor out of course", ticket, nil},
+		{"assigned instructor", assigned, []Action{ActionView, ActionComment, ActionViewInternal, ActionUpdate, ActionAssign, ActionResolve}},
+		{"instructor out of course", assigned, nil},
+		{"admin", ticket, actions},
+		{"service", ticket, []Action{ActionView, ActionComment}},
+		{"anonymous", ticket, nil},
+	}
+
+	for _, tt := range tests {
+		allowed := make(map[Action]bool, len(tt.allowed))
+		for _, action := range tt.allowed {
+			allowed[action] = true
+		}
+
+		for _, action := range actions {
+			t.Run(tt.actor+"/"+string(action), func(t *testing.T) {
+				err := Authorize(actors[tt.actor], action, tt.ticket)
+				if allowed[action] {
+					if err != nil {
+						t.Errorf("Authorize = %v, want nil", err)
+					}
+					return
+				}
+				if !errors.Is(err, ErrForbidden) {
+					t.Errorf("Authorize = %v, want a ForbiddenError", err)
+				}
+			})
+		}
+	}
+}
+
+func TestAuthorizeMessages(t *testing.T) {
+	courseID := "course-1"
+	ticket := &models.Ticket{StudentID: "student-1", CourseID: &courseID}
+	owner := &Actor{UserID: "student-1", Role: "student"}
+
+	// Actors who may view the ticket learn which action was refused; others
+	// are only told that access was denied.
+	if err := Authorize(owner, ActionResolve, ticket); err == nil || err.Error() != policyMessages[ActionResolve] {
+		t.Errorf("Authorize(owner, resolve) = %v, want %q", err, policyMessages[ActionResolve])
+	}
+	outsider := instructorIn("instructor-2", "course-2")
+	if err := Authorize(outsider, ActionResolve, ticket); err == nil || err.Error() != "Access denied" {
+		t.Errorf("Authorize(outsider, resolve) = %v, want \"Access denied\"", err)
+	}
+}
+
+func TestScopeFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		actor     *Actor
+		wantNil   bool
+		wantScope []string
+	}{
+		{"student", &Actor{UserID: "student-1", Grants: []Grant{{Role: "student"}}}, true, nil},
+		{"instructor", instructorIn("instructor-1", "course-1", "course-2"), false, []string{"course-1", "course-2"}},
+		{"admin", &Actor{UserID: "admin-1", Grants: []Grant{{Permissions: adminPermissions, AllCourses: true}}}, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := ScopeFor(tt.actor)
+			if tt.wantNil {
+				if scope != nil {
+					t.Errorf("ScopeFor = %+v, want nil", scope)
+				}
+				return
+			}
+			if scope == nil || scope.InstructorID != tt.actor.UserID || len(scope.CourseIDs) != len(tt.wantScope) {
+				t.Fatalf("ScopeFor = %+v, want courses %v for %s", scope, tt.wantScope, tt.actor.UserID)
+			}
+			for i, courseID := range tt.wantScope {
+				if scope.CourseIDs[i] != courseID {
+					t.Errorf("CourseIDs[%d] = %q, want %q", i, scope.CourseIDs[i], courseID)
+				}
+			}
+		})
+	}
+}
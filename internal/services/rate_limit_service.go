@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"community-support-service/pkg/middleware"
+)
+
+// rateLimitSweepInterval is how often refilled buckets are dropped.
+const rateLimitSweepInterval = 5 * time.Minute
+
+// RateLimitService keeps the rate limiter's token buckets and implements the
+// middleware's RateLimitStore. Buckets live in memory, or in Postgres when a
+// repository is given so that every instance shares them. Should Postgres
+// fail, the instance falls back to its own in-memory buckets until it
+// recovers, rather than refusing or waving through every request.
+type RateLimitService struct {
+	repo repositories.RateLimitRepository // nil for in-memory buckets only
+
+	mu      sync.Mutex
+	buckets map[string]*models.RateLimitBucket
+	sweptAt time.Time
+}
+
+func NewRateLimitService(repo repositories.RateLimitRepository) *RateLimitService {
+	return &RateLimitService{
+		repo:    repo,
+		buckets: make(map[string]*models.RateLimitBucket),
+		sweptAt: time.Now(),
+	}
+}
+
+// Take takes a token from the bucket with the given key.
+func (s *RateLimitService) Take(ctx context.Context, key string, limit middleware.RateLimit) time.Duration {
+	now := time.Now()
+	s.sweep(ctx, now)
+
+	if s.repo != nil {
+		var wait time.Duration
+		err := s.repo.UpdateBucket(ctx, key, func(bucket *models.RateLimitBucket) {
+			wait = takeToken(bucket, limit, now)
+		})
+		if err == nil {
+			return wait
+		}
+		fmt.Printf("Failed to update shared rate limit bucket, limiting locally: %v\n", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &models.RateLimitBucket{Key: key}
+		s.buckets[key] = bucket
+	}
+	return takeToken(bucket, limit, now)
+}
+
+// Refund puts back a token taken from the bucket with the given key.
+func (s *RateLimitService) Refund(ctx context.Context, key string, limit middleware.RateLimit) {
+	now := time.Now()
+
+	if s.repo != nil {
+		err := s.repo.UpdateBucket(ctx, key, func(bucket *models.RateLimitBucket) {
+			refundToken(bucket, limit, now)
+		})
+		if err == nil {
+			return
+		}
+		fmt.Printf("Failed to update shared rate limit bucket, refunding locally: %v\n", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if bucket, ok := s.buckets[key]; ok {
+		refundToken(bucket, limit, now)
+	}
+}
+
+// sweep drops refilled buckets, which behave like buckets never used, once
+// per sweep interval.
+func (s *RateLimitService) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.sweptAt) < rateLimitSweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.sweptAt = now
+	for key, bucket := range s.buckets {
+		if bucket.FullAt.Before(now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.mu.Unlock()
+
+	if s.repo != nil {
+		if _, err := s.repo.DeleteFullBuckets(ctx, now); err != nil {
+			fmt.Printf("Failed to purge rate limit buckets: %v\n", err)
+		}
+	}
+}
+
+// takeToken refills bucket for the time since its last use and takes a
+// token, returning zero or how long until a token is available.
+func takeToken(bucket *models.RateLimitBucket, limit middleware.RateLimit, now time.Time) time.Duration {
+	refill(bucket, limit, now)
+
+	var wait time.Duration
+	if bucket.Tokens >= 1 {
+		bucket.Tokens--
+	} else {
+		perSecond := float64(limit.Requests) / limit.Per.Seconds()
+		wait = time.Duration((1 - bucket.Tokens) / perSecond * float64(time.Second))
+	}
+	setFullAt(bucket, limit, now)
+	return wait
+}
+
+// refundToken refills bucket for the time since its last use and puts back a
+// token, up to the bucket's capacity.
+func refundToken(bucket *models.RateLimitBucket, limit middleware.RateLimit, now time.Time) {
+	refill(bucket, limit, now)
+	bucket.Tokens = math.Min(float64(limit.Requests), bucket.Tokens+1)
+	setFullAt(bucket, limit, now)
+}
+
+// refill adds the tokens bucket gained since its last use, up to its capacity.
+func refill(bucket *models.RateLimitBucket, limit middleware.RateLimit, now time.Time) {
+	capacity := float64(limit.Requests)
+	perSecond := capacity / limit.Per.Seconds()
+
+	elapsed := math.Max(0, now.Sub(bucket.UpdatedAt).Seconds())
+	if bucket.UpdatedAt.IsZero() {
+		elapsed = math.Inf(1)
+	}
+	bucket.Tokens = math.Min(capacity, bucket.Tokens+elapsed*perSecond)
+	bucket.UpdatedAt = now
+}
+
+// setFullAt records when bucket will have refilled completely.
+func setFullAt(bucket *models.RateLimitBucket, limit middleware.RateLimit, now time.Time) {
+	capacity := float64(limit.Requests)
+	perSecond := capacity / limit.Per.Seconds()
+	bucket.FullAt = now.Add(time.Duration((capacity - bucket.Tokens) / perSecond * float64(time.Second)))
+}
\ No newline at end of file
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"golang.org/x/sync/singleflight"
+)
+
+// RevocationService keeps the token revocation list. Revocations are stored
+// in Postgres and cached in memory; the cache is reloaded once it is older
+// than the TTL, so revocations made by other instances apply within a TTL.
+// Requests finding the cache stale at the same time share one reload.
+type RevocationService struct {
+	repo    repositories.RevocationRepository
+	ttl     time.Duration
+	reloads singleflight.Group
+
+	mu       sync.RWMutex
+	tokens   map[string]time.Time // jti to token expiry
+	users    map[string]time.Time // user ID to revokedBefore
+	loadedAt time.Time
+}
+
+func NewRevocationService(repo repositories.RevocationRepository, ttl time.Duration) *RevocationService {
+	return &RevocationService{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+// IsRevoked reports whether the token with the given jti, issued to userID at
+// issuedAt, has been revoked. Tokens without an iat claim (zero issuedAt) are
+// treated as revoked once their user has a cutoff. Cutoffs are kept in whole
+// seconds like iat, so a token issued in the same second as the cutoff is
+// revoked.
+func (s *RevocationService) IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if jti != "" {
+		if _, ok := s.tokens[jti]; ok {
+			return true, nil
+		}
+	}
+	if before, ok := s.users[userID]; ok && (issuedAt.IsZero() || !issuedAt.After(before)) {
+		return true, nil
+	}
+	return false, nil
+}
+
+// RevokeToken revokes a single token until it expires.
+func (s *RevocationService) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	if err := s.repo.RevokeToken(ctx, token); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.tokens != nil {
+		s.tokens[token.JTI] = token.ExpiresAt
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// RevokeUserTokens revokes every token issued to a user before the
+// revocation's cutoff, which is truncated to the second.
+func (s *RevocationService) RevokeUserTokens(ctx context.Context, revocation *models.UserTokenRevocation) error {
+	revocation.RevokedBefore = revocation.RevokedBefore.Truncate(time.Second)
+	if err := s.repo.RevokeUserTokens(ctx, revocation); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.users != nil && s.users[revocation.UserID].Before(revocation.RevokedBefore) {
+		s.users[revocation.UserID] = revocation.RevokedBefore
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *RevocationService) ensureLoaded(ctx context.Context) error {
+	if s.fresh() {
+		return nil
+	}
+	_, err, _ := s.reloads.Do("reload", func() (interface{}, error) {
+		// Another reload may have finished since the check above
+		if s.fresh() {
+			return nil, nil
+		}
+		return nil, s.reload(context.WithoutCancel(ctx))
+	})
+	return err
+}
+
+func (s *RevocationService) fresh() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tokens != nil && time.Since(s.loadedAt) < s.ttl
+}
+
+// reload replaces the cache with the revocations stored in Postgres.
+func (s *RevocationService) reload(ctx context.Context) error {
+	now := time.Now()
+	if _, err := s.repo.DeleteExpiredTokens(ctx, now); err != nil {
+		fmt.Printf("Failed to purge expired token revocations: %v\n", err)
+	}
+	revokedTokens, err := s.repo.GetActiveTokens(ctx, now)
+	if err != nil {
+		return s.staleOrError(fmt.Errorf("failed to load revoked tokens: %w", err))
+	}
+	userRevocations, err := s.repo.GetUserRevocations(ctx)
+	if err != nil {
+		return s.staleOrError(fmt.Errorf("failed to load user token revocations: %w", err))
+	}
+
+	tokens := make(map[string]time.Time, len(revokedTokens))
+	for _, token := range revokedTokens {
+		tokens[token.JTI] = token.ExpiresAt
+	}
+	users := make(map[string]time.Time, len(userRevocations))
+	for _, revocation := range userRevocations {
+		users[revocation.UserID] = revocation.RevokedBefore.Truncate(time.Second)
+	}
+
+	s.mu.Lock()
+	s.tokens = tokens
+	s.users = users
+	s.loadedAt = now
+	s.mu.Unlock()
+	return nil
+}
+
+// staleOrError keeps serving a previously loaded list when a reload fails,
+// and only reports the error when nothing was ever loaded.
+func (s *RevocationService) staleOrError(err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens == nil {
+		return err
+	}
+	// Retry after another TTL rather than on every request
+	s.loadedAt = time.Now()
+	fmt.Printf("%v\n", err)
+	return nil
+}
\ No newline at end of file
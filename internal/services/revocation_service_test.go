@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"community-support-service/internal/models"
+)
+
+// fakeRevocationRepository keeps user revocations in memory, counting and
+// slowing down loads.
+type fakeRevocationRepository struct {
+	users []*models.UserTokenRevocation
+	loads atomic.Int32
+}
+
+func (r *fakeRevocationRepository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	return nil
+}
+
+func (r *fakeRevocationRepository) RevokeUserTokens(ctx context.Context, revocation *models.UserTokenRevocation) error {
+	r.users = append(r.users, revocation)
+	return nil
+}
+
+func (r *fakeRevocationRepository) GetActiveTokens(ctx context.Context, now time.Time) ([]*models.RevokedToken, error) {
+	r.loads.Add(1)
+	time.Sleep(20 * time.Millisecond)
+	return nil, nil
+}
+
+func (r *fakeRevocationRepository) GetUserRevocations(ctx context.Context) ([]*models.UserTokenRevocation, error) {
+	return r.users, nil
+}
+
+func (r *fakeRevocationRepository) DeleteExpiredTokens(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestRevocationServiceCoalescesReloads(t *testing.T) {
+	repo := &fakeRevocationRepository{}
+	service := NewRevocationService(repo, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.IsRevoked(context.Background(), "", "user-1", time.Now()); err != nil {
+				t.Errorf("IsRevoked returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if loads := repo.loads.Load(); loads != 1 {
+		t.Errorf("revocations were loaded %d times, want 1", loads)
+	}
+}
+
+func TestRevocationServiceCutoffPrecision(t *testing.T) {
+	cutoff := time.Date(2026, 3, 1, 12, 0, 0, 700_000_000, time.UTC)
+	service := NewRevocationService(&fakeRevocationRepository{}, time.Minute)
+	if err := service.RevokeUserTokens(context.Background(), &models.UserTokenRevocation{UserID: "user-1", RevokedBefore: cutoff}); err != nil {
+		t.Fatalf("RevokeUserTokens returned error: %v", err)
+	}
+
+	// iat has whole seconds, so a token from the cutoff's second could have
+	// been issued before it
+	tests := []struct {
+		issuedAt time.Time
+		want     bool
+	}{
+		{time.Time{}, true},
+		{cutoff.Truncate(time.Second).Add(-time.Second), true},
+		{cutoff.Truncate(time.Second), true},
+		{cutoff.Truncate(time.Second).Add(time.Second), false},
+	}
+	for _, tt := range tests {
+		revoked, err := service.IsRevoked(context.Background(), "", "user-1", tt.issuedAt)
+		if err != nil {
+			t.Fatalf("IsRevoked returned error: %v", err)
+		}
+		if revoked != tt.want {
+			t.Errorf("IsRevoked(iat %v) = %t, want %t", tt.issuedAt, revoked, tt.want)
+		}
+	}
+}
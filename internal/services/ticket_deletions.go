@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// Delete soft-deletes a ticket. Its comments, attachments and history are
+// kept so that it can be restored. When version is set, the ticket must still
+// be at that version.
+func (s *TicketService) Delete(ctx context.Context, ticketID uuid.UUID, version *int) error {
+	ticket, actor, err := s.getFor(ctx, ticketID, ActionDelete)
+	if err != nil {
+		return err
+	}
+	if err := CheckVersion(ticket, version); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.repo.Ticket.Delete(ctx, ticket.ID, actor.UserID, now); err != nil {
+		return err
+	}
+
+	history := &models.TicketHistory{
+		ID:          uuid.New(),
+		TicketID:    ticket.ID,
+		UserID:      actor.UserID,
+		Action:      "deleted",
+		Description: stringPtr("Ticket deleted"),
+		Metadata:    make(map[string]interface{}),
+		CreatedAt:   now,
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+	return nil
+}
+
+// DeleteComment soft-deletes a comment on the ticket. Internal comments are
+// only found by actors who may see them.
+func (s *TicketService) DeleteComment(ctx context.Context, ticketID, commentID uuid.UUID) error {
+	ticket, actor, err := s.getFor(ctx, ticketID, ActionDelete)
+	if err != nil {
+		return err
+	}
+
+	comment, err := s.repo.Comment.GetByID(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.TicketID != ticket.ID || (comment.IsInternal && !Can(actor, ActionViewInternal, ticket)) {
+		return &repositories.NotFoundError{Entity: "comment"}
+	}
+
+	now := time.Now()
+	if err := s.repo.Comment.Delete(ctx, comment.ID, actor.UserID, now); err != nil {
+		return err
+	}
+
+	history := &models.TicketHistory{
+		ID:        uuid.New(),
+		TicketID:  ticket.ID,
+		UserID:    actor.UserID,
+		Action:    "commentDeleted",
+		Metadata:  models.JSONB{"commentId": comment.ID.String()},
+		CreatedAt: now,
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+	return nil
+}
+
+// Restore brings back a deleted ticket with its comments, attachments and
+// history. Deleted tickets are outside every actor's view, so the caller
+// must have checked the tickets:restore permission.
+func (s *TicketService) Restore(ctx context.Context, ticketID uuid.UUID) (*models.Ticket, error) {
+	if err := s.repo.Ticket.Restore(ctx, ticketID); err != nil {
+		return nil, err
+	}
+	ticket, err := s.repo.Ticket.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var restoredBy string
+	if actor, ok := ActorFromContext(ctx); ok {
+		restoredBy = actor.UserID
+	}
+	history := &models.TicketHistory{
+		ID:          uuid.New(),
+		TicketID:    ticket.ID,
+		UserID:      restoredBy,
+		Action:      "restored",
+		Description: stringPtr("Ticket restored"),
+		Metadata:    make(map[string]interface{}),
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+	return ticket, nil
+}
+
+// RestoreComment brings back a deleted comment with its attachments. Like
+// Restore, the caller must have checked the tickets:restore permission.
+func (s *TicketService) RestoreComment(ctx context.Context, commentID uuid.UUID) (*models.TicketComment, error) {
+	if err := s.repo.Comment.Restore(ctx, commentID); err != nil {
+		return nil, err
+	}
+	comment, err := s.repo.Comment.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var restoredBy string
+	if actor, ok := ActorFromContext(ctx); ok {
+		restoredBy = actor.UserID
+	}
+	history := &models.TicketHistory{
+		ID:        uuid.New(),
+		TicketID:  comment.TicketID,
+		UserID:    restoredBy,
+		Action:    "commentRestored",
+		Metadata:  models.JSONB{"commentId": comment.ID.String()},
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+	return comment, nil
+}
\ No newline at end of file
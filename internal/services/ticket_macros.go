@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// MacroResult is the outcome of applying a macro to a ticket.
+type MacroResult struct {
+	Ticket           *models.Ticket
+	Comment          *models.TicketComment // the macro's reply, if it has one
+	ResolvedChildren []string              // children resolved along with the ticket
+}
+
+// ApplyMacro applies one of the actor's active macros to a ticket: its field
+// changes, tags and rendered reply are saved together with their history.
+// Assigning and resolving through a macro need the same permissions as doing
+// so directly. When version is set, the ticket must still be at that version.
+func (s *TicketService) ApplyMacro(ctx context.Context, ticketID, macroID uuid.UUID, version *int) (*MacroResult, error) {
+	ticket, actor, err := s.getFor(ctx, ticketID, ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckVersion(ticket, version); err != nil {
+		return nil, err
+	}
+
+	macro, err := s.repo.Macro.GetByID(ctx, macroID)
+	if err != nil {
+		return nil, err
+	}
+	if !macro.IsActive || (macro.OwnerID != nil && *macro.OwnerID != actor.UserID) {
+		return nil, &repositories.NotFoundError{Entity: "macro"}
+	}
+
+	if macro.Actions.AssignTo != nil {
+		if err := Authorize(actor, ActionAssign, ticket); err != nil {
+			return nil, err
+		}
+	}
+	if status := macro.Actions.Status; status != nil && (*status == models.TicketStatusResolved || *status == models.TicketStatusClosed) {
+		if err := Authorize(actor, ActionResolve, ticket); err != nil {
+			return nil, err
+		}
+	}
+
+	watchers, err := s.repo.Watcher.GetByTicketID(ctx, ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	wasResolved := ticket.Status == models.TicketStatusResolved
+	application := repositories.MacroApplication{Ticket: ticket, AppliedBy: actor.UserID}
+	entry := func(action string, oldValue, newValue *string, description string) *models.TicketHistory {
+		return &models.TicketHistory{
+			ID:          uuid.New(),
+			TicketID:    ticket.ID,
+			UserID:      actor.UserID,
+			Action:      action,
+			OldValue:    oldValue,
+			NewValue:    newValue,
+			Description: stringPtr(description),
+			Metadata:    models.JSONB{"macroId": macro.ID.String()},
+			CreatedAt:   now,
+		}
+	}
+	record := func(action string, oldValue, newValue *string, description string) {
+		application.History = append(application.History, entry(action, oldValue, newValue, description))
+	}
+
+	actions := macro.Actions
+	if actions.Status != nil && *actions.Status != ticket.Status {
+		oldStatus := string(ticket.Status)
+		ticket.Status = *actions.Status
+		switch ticket.Status {
+		case models.TicketStatusResolved:
+			ticket.ResolvedAt = &now
+		case models.TicketStatusClosed:
+			ticket.ClosedAt = &now
+		}
+		record("statusChanged", &oldStatus, stringPtr(string(ticket.Status)), fmt.Sprintf("Status changed by macro %q", macro.Name))
+	}
+	if actions.Priority != nil && *actions.Priority != ticket.Priority {
+		oldPriority := string(ticket.Priority)
+		ticket.Priority = *actions.Priority
+		slaDueAt := s.sla.DueAt(string(ticket.Priority), ticket.CreatedAt)
+		ticket.SLADueAt = &slaDueAt
+		record("priorityChanged", &oldPriority, stringPtr(string(ticket.Priority)), fmt.Sprintf("Priority changed by macro %q", macro.Name))
+	}
+	if actions.AssignTo != nil {
+		assignee := *actions.AssignTo
+		if assignee == "me" {
+			assignee = actor.UserID
+		}
+		if ticket.InstructorID == nil || *ticket.InstructorID != assignee {
+			oldAssignee := ticket.InstructorID
+			ticket.InstructorID = &assignee
+			record("assigned", oldAssignee, &assignee, fmt.Sprintf("Assigned by macro %q", macro.Name))
+		}
+	}
+	for _, tag := range actions.AddTags {
+		// Saved only if the ticket does not carry the tag yet
+		application.AddTags = append(application.AddTags, entry("tagAdded", nil, stringPtr(tag), fmt.Sprintf("Tag added by macro %q", macro.Name)))
+	}
+
+	if macro.Content != nil && strings.TrimSpace(*macro.Content) != "" {
+		content := RenderMacro(*macro.Content, MacroContext{
+			Ticket:       ticket,
+			StudentName:  studentName(ticket),
+			StudentEmail: studentEmail(ticket, watchers),
+			AgentID:      actor.UserID,
+			AgentEmail:   actor.Email,
+		})
+		application.Comment = &models.TicketComment{
+			ID:         uuid.New(),
+			TicketID:   ticket.ID,
+			UserID:     actor.UserID,
+			Content:    content,
+			IsInternal: macro.IsInternal,
+			Metadata:   models.JSONB{"macroId": macro.ID.String()},
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		action := "commented"
+		if macro.IsInternal {
+			action = "internalNoteAdded"
+		}
+		record(action, nil, nil, fmt.Sprintf("Reply added by macro %q", macro.Name))
+	}
+	record("macroApplied", nil, stringPtr(macro.Name), fmt.Sprintf("Macro %q applied", macro.Name))
+
+	ticket.UpdatedAt = now
+	ticket.LastActivityAt = now
+	if err := s.repo.Ticket.ApplyMacro(ctx, application); err != nil {
+		return nil, err
+	}
+
+	if application.Comment != nil {
+		if err := s.notifications.SendCommentNotifications(ctx, ticket, application.Comment, watchers); err != nil {
+			fmt.Printf("Failed to send comment notifications: %v\n", err)
+		}
+	}
+
+	result := &MacroResult{Ticket: ticket, Comment: application.Comment}
+	if !wasResolved && ticket.Status == models.TicketStatusResolved {
+		result.ResolvedChildren = s.ResolveChildren(ctx, ticket, now)
+	}
+	return result, nil
+}
+
+// studentNameKey is the ticket metadata key holding the display name of the
+// student who opened the ticket, taken from their token at creation.
+const studentNameKey = "studentName"
+
+// studentName returns the student's display name recorded on the ticket, if
+// any. Tickets opened before names were recorded fall back to "Student".
+func studentName(ticket *models.Ticket) string {
+	if name, ok := ticket.Metadata[studentNameKey].(string); ok && name != "" {
+		return name
+	}
+	return "Student"
+}
+
+// studentEmail returns the address of the ticket's student from its watchers.
+func studentEmail(ticket *models.Ticket, watchers []*models.TicketWatcher) string {
+	for _, watcher := range watchers {
+		if watcher.UserID == ticket.StudentID {
+			return watcher.Email
+		}
+	}
+	return ""
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// Merge moves the comments, attachments, history and watchers of duplicates
+// the actor may update into the primary ticket, closes the duplicates with a
+// redirect to it and tells each duplicate's watchers. It returns the primary
+// ticket and the ticket numbers of the merged duplicates.
+func (s *TicketService) Merge(ctx context.Context, primaryID uuid.UUID, duplicateIDs []uuid.UUID) (*models.Ticket, []string, error) {
+	primary, actor, err := s.getFor(ctx, primaryID, ActionUpdate)
+	if err != nil {
+		return nil, nil, err
+	}
+	if primary.MergedIntoID != nil {
+		return nil, nil, &repositories.TicketMergedError{TicketNumber: primary.TicketNumber}
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	duplicates := make([]*models.Ticket, 0, len(duplicateIDs))
+	for _, duplicateID := range duplicateIDs {
+		if duplicateID == primary.ID || seen[duplicateID] {
+			return nil, nil, &InputError{Message: "Duplicate IDs must be distinct and differ from the primary ticket"}
+		}
+		seen[duplicateID] = true
+
+		duplicate, _, err := s.getFor(ctx, duplicateID, ActionUpdate)
+		if err != nil {
+			return nil, nil, err
+		}
+		if duplicate.MergedIntoID != nil {
+			return nil, nil, &repositories.TicketMergedError{TicketNumber: duplicate.TicketNumber}
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	// Collected before merging so that each duplicate's own followers, not
+	// the combined list, are told about their ticket
+	watchersByTicket := make(map[uuid.UUID][]*models.TicketWatcher)
+	for _, duplicate := range duplicates {
+		watchers, err := s.repo.Watcher.GetByTicketID(ctx, duplicate.ID)
+		if err != nil {
+			fmt.Printf("Failed to fetch ticket watchers: %v\n", err)
+		}
+		watchersByTicket[duplicate.ID] = watchers
+	}
+
+	if err := s.repo.Ticket.Merge(ctx, primary.ID, duplicateIDs, actor.UserID, time.Now()); err != nil {
+		return nil, nil, err
+	}
+
+	merged := make([]string, len(duplicates))
+	for i, duplicate := range duplicates {
+		if err := s.notifications.SendTicketMergedNotifications(ctx, primary, duplicate, watchersByTicket[duplicate.ID]); err != nil {
+			fmt.Printf("Failed to send merge notifications: %v\n", err)
+		}
+		merged[i] = duplicate.TicketNumber
+	}
+	return primary, merged, nil
+}
\ No newline at end of file
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// LinkTickets links a ticket to another one the actor may view. The relation
+// type is read from the ticket's point of view: parentOf, childOf, relatesTo,
+// blocks or blockedBy.
+func (s *TicketService) LinkTickets(ctx context.Context, ticketID uuid.UUID, req *models.CreateRelationRequest) (*models.TicketRelation, error) {
+	ticket, actor, err := s.getFor(ctx, ticketID, ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if req.TargetTicketID == ticket.ID {
+		return nil, &InputError{Message: "A ticket cannot be linked to itself"}
+	}
+
+	target, err := s.Get(ctx, req.TargetTicketID)
+	if err != nil {
+		return nil, err
+	}
+
+	relation := &models.TicketRelation{
+		ID:             uuid.New(),
+		SourceTicketID: ticket.ID,
+		TargetTicketID: target.ID,
+		CreatedBy:      actor.UserID,
+		CreatedAt:      time.Now(),
+	}
+	switch req.Type {
+	case "parentOf":
+		relation.Type = models.RelationTypeParent
+	case "childOf":
+		relation.Type = models.RelationTypeParent
+		relation.SourceTicketID, relation.TargetTicketID = target.ID, ticket.ID
+	case "relatesTo":
+		relation.Type = models.RelationTypeRelatesTo
+	case "blocks":
+		relation.Type = models.RelationTypeBlocks
+	case "blockedBy":
+		relation.Type = models.RelationTypeBlocks
+		relation.SourceTicketID, relation.TargetTicketID = target.ID, ticket.ID
+	default:
+		return nil, &InputError{Message: "Invalid relation type"}
+	}
+
+	if err := s.repo.Relation.Create(ctx, relation); err != nil {
+		return nil, err
+	}
+
+	history := &models.TicketHistory{
+		ID:          uuid.New(),
+		TicketID:    ticket.ID,
+		UserID:      actor.UserID,
+		Action:      "linked",
+		NewValue:    stringPtr(target.TicketNumber),
+		Description: stringPtr(fmt.Sprintf("Linked as %s ticket #%s", req.Type, target.TicketNumber)),
+		Metadata:    models.JSONB{"relationId": relation.ID.String()},
+		CreatedAt:   relation.CreatedAt,
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+
+	return relation, nil
+}
+
+// UnlinkTickets removes a relation involving the ticket.
+func (s *TicketService) UnlinkTickets(ctx context.Context, ticketID, relationID uuid.UUID) error {
+	ticket, actor, err := s.getFor(ctx, ticketID, ActionUpdate)
+	if err != nil {
+		return err
+	}
+
+	relation, err := s.repo.Relation.GetByID(ctx, relationID)
+	if err != nil {
+		return err
+	}
+	if relation.SourceTicketID != ticket.ID && relation.TargetTicketID != ticket.ID {
+		return &repositories.NotFoundError{Entity: "relation"}
+	}
+
+	if err := s.repo.Relation.Delete(ctx, relation.ID); err != nil {
+		return err
+	}
+
+	history := &models.TicketHistory{
+		ID:        uuid.New(),
+		TicketID:  ticket.ID,
+		UserID:    actor.UserID,
+		Action:    "unlinked",
+		Metadata:  models.JSONB{"relationId": relation.ID.String()},
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+	return nil
+}
+
+// SetIncident marks a ticket as the parent of a platform incident, or clears
+// the mark. When version is set, the ticket must still be at that version.
+func (s *TicketService) SetIncident(ctx context.Context, ticketID uuid.UUID, isIncident bool, version *int) (*models.Ticket, error) {
+	ticket, actor, err := s.getFor(ctx, ticketID, ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckVersion(ticket, version); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Ticket.SetIncident(ctx, ticket.ID, ticket.Version, isIncident); err != nil {
+		return nil, err
+	}
+
+	oldValue := fmt.Sprintf("%t", ticket.IsIncident)
+	history := &models.TicketHistory{
+		ID:        uuid.New(),
+		TicketID:  ticket.ID,
+		UserID:    actor.UserID,
+		Action:    "incidentChanged",
+		OldValue:  &oldValue,
+		NewValue:  stringPtr(fmt.Sprintf("%t", isIncident)),
+		Metadata:  make(map[string]interface{}),
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+
+	ticket.IsIncident = isIncident
+	ticket.Version++
+	return ticket, nil
+}
+
+// BroadcastIncidentUpdate posts a public comment on an incident ticket and on
+// each of its open children, notifying their watchers. It returns how many
+// children the incident has and the ticket numbers the comment could not be
+// posted on; those failures are logged rather than returned so that one
+// ticket does not stop the broadcast.
+func (s *TicketService) BroadcastIncidentUpdate(ctx context.Context, parentID uuid.UUID, content string) (int, []string, error) {
+	parent, actor, err := s.getFor(ctx, parentID, ActionUpdate)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !parent.IsIncident {
+		return 0, nil, ErrNotAnIncident
+	}
+
+	children, err := s.repo.Relation.GetChildren(ctx, parent.ID, true)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	now := time.Now()
+	var failed []string
+	for _, ticket := range append([]*models.Ticket{parent}, children...) {
+		comment := &models.TicketComment{
+			ID:        uuid.New(),
+			TicketID:  ticket.ID,
+			UserID:    actor.UserID,
+			Content:   content,
+			Metadata:  models.JSONB{"broadcastFromTicketId": parent.ID.String()},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := s.repo.Comment.Create(ctx, comment); err != nil {
+			fmt.Printf("Failed to create broadcast comment: %v\n", err)
+			failed = append(failed, ticket.TicketNumber)
+			continue
+		}
+
+		if err := s.repo.Ticket.TouchActivity(ctx, ticket.ID, now); err != nil {
+			fmt.Printf("Failed to update ticket activity: %v\n", err)
+		}
+
+		history := &models.TicketHistory{
+			ID:        uuid.New(),
+			TicketID:  ticket.ID,
+			UserID:    actor.UserID,
+			Action:    "incidentUpdate",
+			Metadata:  models.JSONB{"commentId": comment.ID.String(), "parentTicketId": parent.ID.String()},
+			CreatedAt: now,
+		}
+		if err := s.repo.History.Create(ctx, history); err != nil {
+			fmt.Printf("Failed to create ticket history: %v\n", err)
+		}
+
+		s.notifyComment(ctx, ticket, comment)
+	}
+
+	return len(children), failed, nil
+}
\ No newline at end of file
@@ -0,0 +1,422 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"community-support-service/internal/config"
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// TicketService holds the ticket operations shared by the HTTP handlers and
+// other entry points: access checks, history, watchers and notifications.
+// Every method acts on behalf of the Actor carried by its context.
+type TicketService struct {
+	repo          *repositories.Repository
+	notifications *NotificationService
+	sla           config.SLAConfig
+}
+
+func NewTicketService(repo *repositories.Repository, notifications *NotificationService, sla config.SLAConfig) *TicketService {
+	return &TicketService{
+		repo:          repo,
+		notifications: notifications,
+		sla:           sla,
+	}
+}
+
+// Create opens a ticket for the actor, validating its category and custom
+// form fields, and subscribes the actor to it.
+func (s *TicketService) Create(ctx context.Context, req *models.CreateTicketRequest) (*models.Ticket, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	// The student is notified about the ticket by email
+	if actor.Email == "" {
+		return nil, &InputError{Message: "An email address is needed to open a ticket"}
+	}
+
+	if req.CategoryID != nil {
+		category, err := s.repo.Category.GetByID(ctx, *req.CategoryID)
+		if errors.Is(err, repositories.ErrNotFound) || (err == nil && !category.IsActive) {
+			return nil, &InputError{Message: "Unknown or inactive category"}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	slaDueAt := s.sla.DueAt(string(req.Priority), now)
+
+	ticket := &models.Ticket{
+		ID:             uuid.New(),
+		TicketNumber:   generateTicketNumber(now),
+		Title:          req.Title,
+		Description:    req.Description,
+		Status:         models.TicketStatusOpen,
+		Priority:       req.Priority,
+		Type:           req.Type,
+		StudentID:      actor.UserID,
+		CourseID:       req.CourseID,
+		CategoryID:     req.CategoryID,
+		Metadata:       req.Metadata,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		SLADueAt:       &slaDueAt,
+		LastActivityAt: now,
+		Version:        1,
+	}
+
+	if err := s.applyFormFields(ctx, ticket, req.Fields); err != nil {
+		return nil, err
+	}
+
+	// The student's name comes only from their token, never from the request
+	delete(ticket.Metadata, studentNameKey)
+	if name := actor.DisplayName(); name != "" {
+		if ticket.Metadata == nil {
+			ticket.Metadata = models.JSONB{}
+		}
+		ticket.Metadata[studentNameKey] = name
+	}
+
+	if err := s.repo.Ticket.Create(ctx, ticket); err != nil {
+		return nil, err
+	}
+
+	history := &models.TicketHistory{
+		ID:          uuid.New(),
+		TicketID:    ticket.ID,
+		UserID:      actor.UserID,
+		Action:      "created",
+		Description: stringPtr("Ticket created by student"),
+		Metadata:    make(map[string]interface{}),
+		CreatedAt:   now,
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+
+	creator := &models.TicketWatcher{
+		ID:        uuid.New(),
+		TicketID:  ticket.ID,
+		UserID:    actor.UserID,
+		Email:     actor.Email,
+		Role:      models.WatcherRoleStudent,
+		Source:    models.WatcherSourceCreator,
+		AddedBy:   actor.UserID,
+		CreatedAt: now,
+	}
+	if err := s.repo.Watcher.Create(ctx, creator); err != nil {
+		fmt.Printf("Failed to add ticket creator as watcher: %v\n", err)
+	}
+
+	if err := s.notifications.SendTicketCreatedNotifications(ctx, ticket, actor.Email, []*models.TicketWatcher{creator}); err != nil {
+		fmt.Printf("Failed to send notifications: %v\n", err)
+	}
+
+	return ticket, nil
+}
+
+// Get returns a ticket the actor may view.
+func (s *TicketService) Get(ctx context.Context, id uuid.UUID) (*models.Ticket, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	ticket, err := s.repo.Ticket.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Authorize(actor, ActionView, ticket); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// getFor is Get also requiring that the actor may perform action on the
+// ticket. It returns the actor along with the ticket.
+func (s *TicketService) getFor(ctx context.Context, id uuid.UUID, action Action) (*models.Ticket, *Actor, error) {
+	ticket, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	actor, _ := ActorFromContext(ctx)
+	if err := Authorize(actor, action, ticket); err != nil {
+		return nil, nil, err
+	}
+	return ticket, actor, nil
+}
+
+// List returns a page of tickets matching filters, limited to the tickets the
+// actor may view. Listings of the actor's own tickets are not limited further.
+func (s *TicketService) List(ctx context.Context, filters repositories.TicketFilters, pagination repositories.Pagination) (*repositories.TicketPage, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	if filters.StudentID == nil || *filters.StudentID != actor.UserID {
+		if !actor.HasPermission(models.PermTicketsRead) {
+			filters.StudentID = &actor.UserID
+		}
+		filters.Scope = ScopeFor(actor)
+	}
+	return s.repo.Ticket.List(ctx, filters, pagination)
+}
+
+// Complete resolves a ticket and its open children. It returns the ticket
+// numbers of the resolved children. When version is set, the ticket must
+// still be at that version.
+func (s *TicketService) Complete(ctx context.Context, id uuid.UUID, version *int) (*models.Ticket, []string, error) {
+	ticket, actor, err := s.getFor(ctx, id, ActionResolve)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := CheckVersion(ticket, version); err != nil {
+		return nil, nil, err
+	}
+
+	if ticket.Status == models.TicketStatusResolved || ticket.Status == models.TicketStatusClosed {
+		return nil, nil, &repositories.TransitionError{From: string(ticket.Status), To: string(models.TicketStatusResolved)}
+	}
+
+	now := time.Now()
+	oldStatus := string(ticket.Status)
+	ticket.Status = models.TicketStatusResolved
+	ticket.UpdatedAt = now
+	ticket.LastActivityAt = now
+	ticket.ResolvedAt = &now
+
+	if err := s.repo.Ticket.Update(ctx, ticket); err != nil {
+		return nil, nil, err
+	}
+
+	history := &models.TicketHistory{
+		ID:          uuid.New(),
+		TicketID:    ticket.ID,
+		UserID:      actor.UserID,
+		Action:      "completed",
+		OldValue:    &oldStatus,
+		NewValue:    stringPtr(string(models.TicketStatusResolved)),
+		Description: stringPtr(fmt.Sprintf("Ticket completed by %s", actor.Role)),
+		Metadata:    make(map[string]interface{}),
+		CreatedAt:   now,
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+
+	watchers, err := s.repo.Watcher.GetByTicketID(ctx, ticket.ID)
+	if err != nil {
+		fmt.Printf("Failed to fetch ticket watchers: %v\n", err)
+	}
+	if err := s.notifications.SendTicketCompletedNotifications(ctx, ticket, actor.Email, watchers); err != nil {
+		fmt.Printf("Failed to send completion notifications: %v\n", err)
+	}
+
+	return ticket, s.ResolveChildren(ctx, ticket, now), nil
+}
+
+// CheckVersion returns a VersionConflictError unless version is nil or the
+// ticket's current version.
+func CheckVersion(ticket *models.Ticket, version *int) error {
+	if version == nil || *version == ticket.Version {
+		return nil
+	}
+	return &repositories.VersionConflictError{Entity: "ticket", Version: *version, Current: ticket.Version}
+}
+
+// ResolveChildren cascades a parent's resolution to its open children and
+// notifies their watchers. Failures are logged rather than returned because
+// the parent itself has already been resolved. A deleted parent resolves
+// nothing, and deleted children are left alone.
+func (s *TicketService) ResolveChildren(ctx context.Context, parent *models.Ticket, at time.Time) []string {
+	if parent.DeletedAt != nil {
+		return nil
+	}
+
+	var resolvedBy string
+	if actor, ok := ActorFromContext(ctx); ok {
+		resolvedBy = actor.UserID
+	}
+
+	children, err := s.repo.Ticket.ResolveChildren(ctx, parent.ID, resolvedBy, at)
+	if err != nil {
+		fmt.Printf("Failed to resolve child tickets: %v\n", err)
+		return nil
+	}
+
+	resolved := make([]string, len(children))
+	for i, child := range children {
+		resolved[i] = child.TicketNumber
+
+		watchers, err := s.repo.Watcher.GetByTicketID(ctx, child.ID)
+		if err != nil {
+			fmt.Printf("Failed to fetch ticket watchers: %v\n", err)
+		}
+		if err := s.notifications.SendParentResolvedNotifications(ctx, parent, child, watchers); err != nil {
+			fmt.Printf("Failed to send parent resolution notifications: %v\n", err)
+		}
+	}
+	return resolved
+}
+
+// AddComment posts a comment on a ticket, subscribes the actor to the ticket
+// and notifies its watchers.
+func (s *TicketService) AddComment(ctx context.Context, ticketID uuid.UUID, req *models.CreateCommentRequest) (*models.TicketComment, error) {
+	ticket, err := s.Get(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, _ := ActorFromContext(ctx)
+	action := ActionComment
+	if req.IsInternal {
+		action = ActionViewInternal
+	}
+	if err := Authorize(actor, action, ticket); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comment := &models.TicketComment{
+		ID:         uuid.New(),
+		TicketID:   ticket.ID,
+		UserID:     actor.UserID,
+		Content:    req.Content,
+		IsInternal: req.IsInternal,
+		Metadata:   req.Metadata,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.repo.Comment.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Ticket.TouchActivity(ctx, ticket.ID, now); err != nil {
+		fmt.Printf("Failed to update ticket activity: %v\n", err)
+	}
+
+	historyAction := "commented"
+	if comment.IsInternal {
+		historyAction = "internalNoteAdded"
+	}
+	history := &models.TicketHistory{
+		ID:        uuid.New(),
+		TicketID:  ticket.ID,
+		UserID:    actor.UserID,
+		Action:    historyAction,
+		Metadata:  models.JSONB{"commentId": comment.ID.String()},
+		CreatedAt: now,
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+
+	// Commenting on a ticket subscribes the author to further updates
+	if actor.Email != "" {
+		watcher := &models.TicketWatcher{
+			ID:        uuid.New(),
+			TicketID:  ticket.ID,
+			UserID:    actor.UserID,
+			Email:     actor.Email,
+			Role:      WatcherRoleFor(actor),
+			Source:    models.WatcherSourceComment,
+			AddedBy:   actor.UserID,
+			CreatedAt: now,
+		}
+		if err := s.repo.Watcher.Create(ctx, watcher); err != nil {
+			fmt.Printf("Failed to auto-watch ticket: %v\n", err)
+		}
+	}
+
+	s.notifyComment(ctx, ticket, comment)
+	return comment, nil
+}
+
+// notifyComment tells the ticket's watchers about a new comment. Failures are
+// logged because the comment itself has already been saved.
+func (s *TicketService) notifyComment(ctx context.Context, ticket *models.Ticket, comment *models.TicketComment) {
+	watchers, err := s.repo.Watcher.GetByTicketID(ctx, ticket.ID)
+	if err != nil {
+		fmt.Printf("Failed to fetch ticket watchers: %v\n", err)
+	}
+	if err := s.notifications.SendCommentNotifications(ctx, ticket, comment, watchers); err != nil {
+		fmt.Printf("Failed to send comment notifications: %v\n", err)
+	}
+}
+
+// applyFormFields validates the submitted custom field values against the
+// fields that apply to the ticket's type and category, storing the normalized
+// values in the ticket's metadata under their keys. Category fields take
+// precedence over type fields with the same key.
+func (s *TicketService) applyFormFields(ctx context.Context, ticket *models.Ticket, values models.JSONB) error {
+	applicable, err := s.repo.FormField.GetApplicable(ctx, string(ticket.Type), ticket.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]*models.FormField, len(applicable))
+	var ordered []*models.FormField
+	for _, field := range applicable {
+		if _, ok := fields[field.Key]; !ok {
+			fields[field.Key] = field
+			ordered = append(ordered, field)
+		}
+	}
+
+	var problems []string
+	var unknown []string
+	for key := range values {
+		if _, ok := fields[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		problems = append(problems, fmt.Sprintf("%s is not a field of this form", key))
+	}
+
+	if ticket.Metadata == nil && len(ordered) > 0 {
+		ticket.Metadata = models.JSONB{}
+	}
+	for _, field := range ordered {
+		// Field values only ever come from the form, never from free metadata.
+		delete(ticket.Metadata, field.Key)
+
+		value, err := field.Normalize(values[field.Key])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s %s", field.Label, err))
+			continue
+		}
+		if value != nil {
+			ticket.Metadata[field.Key] = value
+		}
+	}
+
+	if len(problems) > 0 {
+		return &InputError{Message: "Invalid form fields: " + strings.Join(problems, "; ")}
+	}
+	return nil
+}
+
+func generateTicketNumber(at time.Time) string {
+	return fmt.Sprintf("KEMUKO-%d", at.Unix())
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"community-support-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// NormalizeTag lowercases and trims a tag and reports whether the result is
+// a valid tag name.
+func NormalizeTag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	return tag, tagPattern.MatchString(tag)
+}
+
+// AddTags tags a ticket, recording each tag it did not already carry in its
+// history. Tags need not be in the catalog. It returns the newly added tags.
+func (s *TicketService) AddTags(ctx context.Context, ticketID uuid.UUID, tags []string) ([]string, error) {
+	ticket, actor, err := s.getFor(ctx, ticketID, ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name, ok := NormalizeTag(tag)
+		if !ok {
+			return nil, &InputError{Message: "Tag names must be 1-50 lowercase letters, digits, '-' or '_'"}
+		}
+		names = append(names, name)
+	}
+
+	return s.repo.Tag.AddToTicket(ctx, ticket.ID, names, actor.UserID, time.Now())
+}
+
+// RemoveTag removes a tag from a ticket, recording the change in its history.
+func (s *TicketService) RemoveTag(ctx context.Context, ticketID uuid.UUID, tag string) error {
+	ticket, actor, err := s.getFor(ctx, ticketID, ActionUpdate)
+	if err != nil {
+		return err
+	}
+
+	name, _ := NormalizeTag(tag)
+	removed, err := s.repo.Tag.RemoveFromTicket(ctx, ticket.ID, name, actor.UserID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return &repositories.NotFoundError{Entity: "ticket tag"}
+	}
+	return nil
+}
\ No newline at end of file
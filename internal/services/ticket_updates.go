@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"community-support-service/internal/models"
+	"community-support-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// UpdateResult is the outcome of updating a ticket.
+type UpdateResult struct {
+	Ticket           *models.Ticket
+	ResolvedChildren []string // children resolved along with the ticket
+}
+
+// Update changes the ticket's fields, recording each change in its history.
+// Assigning and resolving need the same permissions as doing so directly,
+// and a ticket can only be moved to a course the actor may update tickets
+// in. Metadata keys are merged into the ticket's metadata; keys the service
+// sets itself, such as form field values, cannot be changed. When version is
+// set, the ticket must still be at that version.
+func (s *TicketService) Update(ctx context.Context, ticketID uuid.UUID, req *models.UpdateTicketRequest, version *int) (*UpdateResult, error) {
+	ticket, actor, err := s.getFor(ctx, ticketID, ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckVersion(ticket, version); err != nil {
+		return nil, err
+	}
+
+	if req.InstructorID != nil {
+		if err := Authorize(actor, ActionAssign, ticket); err != nil {
+			return nil, err
+		}
+	}
+	if status := req.Status; status != nil && (*status == models.TicketStatusResolved || *status == models.TicketStatusClosed) {
+		if err := Authorize(actor, ActionResolve, ticket); err != nil {
+			return nil, err
+		}
+	}
+	if req.CourseID != nil {
+		// Judged by the actor's course grants alone, not by the assignment
+		moved := &models.Ticket{StudentID: ticket.StudentID, CourseID: req.CourseID}
+		if !Can(actor, ActionUpdate, moved) {
+			return nil, &ForbiddenError{Message: "You do not have permission to move tickets to this course"}
+		}
+	}
+	if req.CategoryID != nil {
+		category, err := s.repo.Category.GetByID(ctx, *req.CategoryID)
+		if errors.Is(err, repositories.ErrNotFound) || (err == nil && !category.IsActive) {
+			return nil, &InputError{Message: "Unknown or inactive category"}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(req.Metadata) > 0 {
+		if err := s.checkMetadataKeys(ctx, ticket, req.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	wasResolved := ticket.Status == models.TicketStatusResolved
+	var history []*models.TicketHistory
+	record := func(action string, oldValue, newValue *string, description string) {
+		history = append(history, &models.TicketHistory{
+			ID:          uuid.New(),
+			TicketID:    ticket.ID,
+			UserID:      actor.UserID,
+			Action:      action,
+			OldValue:    oldValue,
+			NewValue:    newValue,
+			Description: stringPtr(description),
+			Metadata:    make(map[string]interface{}),
+			CreatedAt:   now,
+		})
+	}
+
+	if req.Status != nil && *req.Status != ticket.Status {
+		oldStatus := string(ticket.Status)
+		ticket.Status = *req.Status
+		switch ticket.Status {
+		case models.TicketStatusResolved:
+			ticket.ResolvedAt = &now
+		case models.TicketStatusClosed:
+			ticket.ClosedAt = &now
+		}
+		record("statusChanged", &oldStatus, stringPtr(string(ticket.Status)), "Status changed")
+	}
+	if req.Priority != nil && *req.Priority != ticket.Priority {
+		oldPriority := string(ticket.Priority)
+		ticket.Priority = *req.Priority
+		slaDueAt := s.sla.DueAt(string(ticket.Priority), ticket.CreatedAt)
+		ticket.SLADueAt = &slaDueAt
+		record("priorityChanged", &oldPriority, stringPtr(string(ticket.Priority)), "Priority changed")
+	}
+	if req.InstructorID != nil && (ticket.InstructorID == nil || *ticket.InstructorID != *req.InstructorID) {
+		oldAssignee := ticket.InstructorID
+		ticket.InstructorID = req.InstructorID
+		record("assigned", oldAssignee, req.InstructorID, "Ticket assigned")
+	}
+
+	// The remaining fields share one history entry naming them
+	var changed []string
+	if req.Title != nil && *req.Title != ticket.Title {
+		ticket.Title = *req.Title
+		changed = append(changed, "title")
+	}
+	if req.Description != nil && *req.Description != ticket.Description {
+		ticket.Description = *req.Description
+		changed = append(changed, "description")
+	}
+	if req.Type != nil && *req.Type != ticket.Type {
+		ticket.Type = *req.Type
+		changed = append(changed, "type")
+	}
+	if req.CourseID != nil && (ticket.CourseID == nil || *ticket.CourseID != *req.CourseID) {
+		ticket.CourseID = req.CourseID
+		changed = append(changed, "courseId")
+	}
+	if req.CategoryID != nil && (ticket.CategoryID == nil || *ticket.CategoryID != *req.CategoryID) {
+		ticket.CategoryID = req.CategoryID
+		changed = append(changed, "categoryId")
+	}
+	if len(req.Metadata) > 0 {
+		if ticket.Metadata == nil {
+			ticket.Metadata = models.JSONB{}
+		}
+		for key, value := range req.Metadata {
+			ticket.Metadata[key] = value
+		}
+		changed = append(changed, "metadata")
+	}
+	if len(changed) > 0 {
+		record("updated", nil, stringPtr(strings.Join(changed, ",")), "Ticket updated: "+strings.Join(changed, ", "))
+	}
+
+	if len(history) == 0 {
+		return &UpdateResult{Ticket: ticket}, nil
+	}
+
+	ticket.UpdatedAt = now
+	ticket.LastActivityAt = now
+	if err := s.repo.Ticket.Update(ctx, ticket); err != nil {
+		return nil, err
+	}
+	for _, entry := range history {
+		if err := s.repo.History.Create(ctx, entry); err != nil {
+			fmt.Printf("Failed to create ticket history: %v\n", err)
+		}
+	}
+
+	result := &UpdateResult{Ticket: ticket}
+	if !wasResolved && ticket.Status == models.TicketStatusResolved {
+		watchers, err := s.repo.Watcher.GetByTicketID(ctx, ticket.ID)
+		if err != nil {
+			fmt.Printf("Failed to fetch ticket watchers: %v\n", err)
+		}
+		if err := s.notifications.SendTicketCompletedNotifications(ctx, ticket, actor.Email, watchers); err != nil {
+			fmt.Printf("Failed to send completion notifications: %v\n", err)
+		}
+		result.ResolvedChildren = s.ResolveChildren(ctx, ticket, now)
+	}
+	return result, nil
+}
+
+// Assign assigns the ticket to an instructor, recording the change in its
+// history. When version is set, the ticket must still be at that version.
+func (s *TicketService) Assign(ctx context.Context, ticketID uuid.UUID, instructorID string, version *int) (*models.Ticket, error) {
+	ticket, actor, err := s.getFor(ctx, ticketID, ActionAssign)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckVersion(ticket, version); err != nil {
+		return nil, err
+	}
+	if ticket.InstructorID != nil && *ticket.InstructorID == instructorID {
+		return ticket, nil
+	}
+
+	now := time.Now()
+	oldAssignee := ticket.InstructorID
+	ticket.InstructorID = &instructorID
+	ticket.UpdatedAt = now
+	ticket.LastActivityAt = now
+	if err := s.repo.Ticket.Update(ctx, ticket); err != nil {
+		return nil, err
+	}
+
+	history := &models.TicketHistory{
+		ID:          uuid.New(),
+		TicketID:    ticket.ID,
+		UserID:      actor.UserID,
+		Action:      "assigned",
+		OldValue:    oldAssignee,
+		NewValue:    &instructorID,
+		Description: stringPtr("Ticket assigned"),
+		Metadata:    make(map[string]interface{}),
+		CreatedAt:   now,
+	}
+	if err := s.repo.History.Create(ctx, history); err != nil {
+		fmt.Printf("Failed to create ticket history: %v\n", err)
+	}
+	return ticket, nil
+}
+
+// checkMetadataKeys refuses metadata keys that only the service sets: the
+// student's name and the values of the ticket's form fields.
+func (s *TicketService) checkMetadataKeys(ctx context.Context, ticket *models.Ticket, metadata models.JSONB) error {
+	fields, err := s.repo.FormField.GetApplicable(ctx, string(ticket.Type), ticket.CategoryID)
+	if err != nil {
+		return err
+	}
+	for key := range metadata {
+		if strings.EqualFold(key, studentNameKey) {
+			return &InputError{Message: fmt.Sprintf("metadata.%s cannot be changed", key)}
+		}
+		for _, field := range fields {
+			if field.Key == key {
+				return &InputError{Message: fmt.Sprintf("metadata.%s is a form field and cannot be changed", key)}
+			}
+		}
+	}
+	return nil
+}
\ No newline at end of file
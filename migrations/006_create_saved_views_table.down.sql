@@ -0,0 +1 @@
+DROP TABLE IF EXISTS savedViews;
\ No newline at end of file
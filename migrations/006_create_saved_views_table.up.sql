@@ -0,0 +1,14 @@
+CREATE TABLE savedViews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    query TEXT NOT NULL, -- Ticket filter expression, e.g. "status:open priority:>=high"
+    ownerId VARCHAR(255) NOT NULL, -- External user ID from auth service
+    teamId VARCHAR(255), -- Team the view is shared with, NULL for private views
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updatedAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT uq_saved_views_owner_name UNIQUE (ownerId, name)
+);
+
+CREATE INDEX idx_saved_views_owner_id ON savedViews(ownerId);
+CREATE INDEX idx_saved_views_team_id ON savedViews(teamId);
\ No newline at end of file
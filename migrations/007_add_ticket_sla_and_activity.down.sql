@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tickets_updated_at;
+DROP INDEX IF EXISTS idx_tickets_last_activity_at;
+DROP INDEX IF EXISTS idx_tickets_sla_due_at;
+
+ALTER TABLE tickets
+    DROP COLUMN IF EXISTS lastActivityAt,
+    DROP COLUMN IF EXISTS slaDueAt;
\ No newline at end of file
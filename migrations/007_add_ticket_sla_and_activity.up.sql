@@ -0,0 +1,19 @@
+ALTER TABLE tickets
+    ADD COLUMN slaDueAt TIMESTAMP WITH TIME ZONE, -- Response deadline derived from priority at creation
+    ADD COLUMN lastActivityAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(); -- Last update or comment
+
+UPDATE tickets t SET lastActivityAt = GREATEST(
+    t.updatedAt,
+    (SELECT MAX(c.createdAt) FROM ticketComments c WHERE c.ticketId = t.id)
+);
+
+UPDATE tickets SET slaDueAt = createdAt + CASE priority
+    WHEN 'urgent' THEN INTERVAL '4 hours'
+    WHEN 'high' THEN INTERVAL '24 hours'
+    WHEN 'medium' THEN INTERVAL '72 hours'
+    ELSE INTERVAL '120 hours'
+END;
+
+CREATE INDEX idx_tickets_sla_due_at ON tickets(slaDueAt);
+CREATE INDEX idx_tickets_last_activity_at ON tickets(lastActivityAt);
+CREATE INDEX idx_tickets_updated_at ON tickets(updatedAt);
\ No newline at end of file
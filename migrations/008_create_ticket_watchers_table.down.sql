@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ticketWatchers;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE ticketWatchers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticketId UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    userId VARCHAR(255) NOT NULL, -- External user ID from auth service
+    email VARCHAR(255) NOT NULL, -- Notification address captured when the watcher was added
+    role VARCHAR(50) NOT NULL CHECK (role IN ('student', 'guardian', 'instructor', 'teachingAssistant', 'admin')),
+    source VARCHAR(50) NOT NULL DEFAULT 'manual' CHECK (source IN ('creator', 'manual', 'self', 'comment')),
+    addedBy VARCHAR(255) NOT NULL, -- User who added the watcher
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT uq_ticket_watchers_ticket_user UNIQUE (ticketId, userId)
+);
+
+CREATE INDEX idx_ticket_watchers_ticket_id ON ticketWatchers(ticketId);
+CREATE INDEX idx_ticket_watchers_user_id ON ticketWatchers(userId);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tickets_description_trgm;
+DROP INDEX IF EXISTS idx_tickets_title_trgm;
+DROP INDEX IF EXISTS idx_tickets_merged_into_id;
+
+ALTER TABLE tickets DROP COLUMN IF EXISTS mergedIntoId;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE tickets
+    ADD COLUMN mergedIntoId UUID REFERENCES tickets(id) ON DELETE SET NULL; -- Primary ticket this duplicate was merged into
+
+CREATE INDEX idx_tickets_merged_into_id ON tickets(mergedIntoId);
+CREATE INDEX idx_tickets_title_trgm ON tickets USING GIN(title gin_trgm_ops);
+CREATE INDEX idx_tickets_description_trgm ON tickets USING GIN(description gin_trgm_ops);
\ No newline at end of file
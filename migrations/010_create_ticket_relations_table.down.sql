@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS ticketRelations;
+
+DROP INDEX IF EXISTS idx_tickets_is_incident;
+ALTER TABLE tickets DROP COLUMN IF EXISTS isIncident;
\ No newline at end of file
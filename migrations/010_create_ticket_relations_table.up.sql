@@ -0,0 +1,20 @@
+ALTER TABLE tickets
+    ADD COLUMN isIncident BOOLEAN DEFAULT false; -- Parent ticket tracking a platform-wide incident
+
+CREATE TABLE ticketRelations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sourceTicketId UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    targetTicketId UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL CHECK (type IN ('parent', 'relatesTo', 'blocks')), -- source is parent of / relates to / blocks target
+    createdBy VARCHAR(255) NOT NULL, -- External user ID from auth service
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT chk_ticket_relations_distinct CHECK (sourceTicketId <> targetTicketId),
+    CONSTRAINT uq_ticket_relations UNIQUE (sourceTicketId, targetTicketId, type)
+);
+
+-- A ticket can have at most one parent
+CREATE UNIQUE INDEX uq_ticket_relations_single_parent ON ticketRelations(targetTicketId) WHERE type = 'parent';
+CREATE INDEX idx_ticket_relations_source ON ticketRelations(sourceTicketId);
+CREATE INDEX idx_ticket_relations_target ON ticketRelations(targetTicketId);
+CREATE INDEX idx_tickets_is_incident ON tickets(isIncident) WHERE isIncident = true;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS macros;
\ No newline at end of file
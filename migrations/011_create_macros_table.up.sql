@@ -0,0 +1,14 @@
+CREATE TABLE macros (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    content TEXT, -- Reply template, e.g. "Hi {{student.name}}, ticket #{{ticket.TicketNumber}}..."
+    isInternal BOOLEAN DEFAULT false, -- Post the reply as an internal note
+    actions JSONB DEFAULT '{}', -- Field changes: status, priority, addTags, assignTo
+    ownerId VARCHAR(255), -- NULL for shared macros managed by admins, otherwise the instructor's personal macro
+    createdBy VARCHAR(255) NOT NULL, -- External user ID from auth service
+    isActive BOOLEAN DEFAULT true,
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updatedAt TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_macros_owner_id ON macros(ownerId);
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- Admin-managed catalog of tag colors; tickets may also carry tags that are not listed here
+CREATE TABLE tags (
+    name VARCHAR(50) PRIMARY KEY,
+    color VARCHAR(7), -- Hex color code for UI display
+    description TEXT,
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updatedAt TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Free-form labels on tickets
+CREATE TABLE ticketTags (
+    ticketId UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    addedBy VARCHAR(255) NOT NULL, -- External user ID from auth service
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (ticketId, tag)
+);
+
+CREATE INDEX idx_ticket_tags_tag ON ticketTags(tag);
\ No newline at end of file
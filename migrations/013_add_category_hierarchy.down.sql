@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_categories_parent_id;
+DROP INDEX IF EXISTS idx_categories_type;
+
+ALTER TABLE categories
+    DROP CONSTRAINT IF EXISTS chk_categories_parent,
+    DROP COLUMN IF EXISTS parentId,
+    DROP COLUMN IF EXISTS type;
\ No newline at end of file
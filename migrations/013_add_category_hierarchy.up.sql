@@ -0,0 +1,7 @@
+ALTER TABLE categories
+    ADD COLUMN type VARCHAR(50) DEFAULT 'general' CHECK (type IN ('general', 'technical', 'course', 'assignment', 'grading', 'platform', 'content')), -- Ticket type the category belongs to
+    ADD COLUMN parentId UUID REFERENCES categories(id) ON DELETE RESTRICT, -- NULL for top-level categories
+    ADD CONSTRAINT chk_categories_parent CHECK (parentId IS NULL OR parentId <> id);
+
+CREATE INDEX idx_categories_type ON categories(type);
+CREATE INDEX idx_categories_parent_id ON categories(parentId);
\ No newline at end of file
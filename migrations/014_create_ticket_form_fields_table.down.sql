@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ticketFormFields;
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- Typed custom fields shown on the ticket form for a category (and its subcategories) or a ticket type.
+-- Values are stored in tickets.metadata under the field key.
+CREATE TABLE ticketFormFields (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    key VARCHAR(64) NOT NULL, -- metadata key, e.g. assignmentId
+    label VARCHAR(100) NOT NULL,
+    fieldType VARCHAR(20) NOT NULL CHECK (fieldType IN ('text', 'number', 'select', 'date', 'url')),
+    required BOOLEAN DEFAULT false,
+    options TEXT[], -- allowed values for select fields
+    helpText TEXT,
+    categoryId UUID REFERENCES categories(id) ON DELETE CASCADE,
+    ticketType VARCHAR(50) CHECK (ticketType IN ('general', 'technical', 'course', 'assignment', 'grading', 'platform', 'content')),
+    sortOrder INTEGER DEFAULT 0,
+    isActive BOOLEAN DEFAULT true,
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updatedAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT chk_ticket_form_fields_scope CHECK ((categoryId IS NULL) <> (ticketType IS NULL))
+);
+
+CREATE UNIQUE INDEX uq_ticket_form_fields_category_key ON ticketFormFields(categoryId, key) WHERE categoryId IS NOT NULL;
+CREATE UNIQUE INDEX uq_ticket_form_fields_type_key ON ticketFormFields(ticketType, key) WHERE ticketType IS NOT NULL;
\ No newline at end of file
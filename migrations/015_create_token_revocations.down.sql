@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS userTokenRevocations;
+DROP TABLE IF EXISTS revokedTokens;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- Individually revoked JWTs, by jti. Rows can be purged once the token has expired.
+CREATE TABLE revokedTokens (
+    jti VARCHAR(255) PRIMARY KEY,
+    expiresAt TIMESTAMP WITH TIME ZONE NOT NULL, -- expiry of the revoked token
+    reason TEXT,
+    revokedBy VARCHAR(255) NOT NULL,
+    revokedAt TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revokedTokens(expiresAt);
+
+-- Per-user cutoff: every token issued to the user before revokedBefore is revoked.
+CREATE TABLE userTokenRevocations (
+    userId VARCHAR(255) PRIMARY KEY,
+    revokedBefore TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT,
+    revokedBy VARCHAR(255) NOT NULL,
+    updatedAt TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
\ No newline at end of file
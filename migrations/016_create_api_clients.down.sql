@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS apiClientUsage;
+DROP TABLE IF EXISTS apiClients;
\ No newline at end of file
@@ -0,0 +1,31 @@
+-- Service-to-service API clients. Only a SHA-256 hash of each key is stored;
+-- keyPrefix is the public part of the key used to look the client up.
+CREATE TABLE apiClients (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    keyPrefix VARCHAR(32) NOT NULL UNIQUE,
+    keyHash VARCHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}', -- e.g. tickets:create, tickets:read, comments:write
+    isActive BOOLEAN DEFAULT true,
+    createdBy VARCHAR(255) NOT NULL,
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updatedAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    lastUsedAt TIMESTAMP WITH TIME ZONE
+);
+
+-- Audit trail of every request made with an API client's key.
+CREATE TABLE apiClientUsage (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    clientId UUID NOT NULL REFERENCES apiClients(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    scope VARCHAR(50), -- scope the route required, NULL for routes closed to API clients
+    onBehalfOf VARCHAR(255), -- user the client acted for, if any
+    status INTEGER NOT NULL,
+    requestId VARCHAR(128),
+    remoteAddr VARCHAR(255),
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_api_client_usage_client_created ON apiClientUsage(clientId, createdAt DESC);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS roleAssignments;
+DROP TABLE IF EXISTS roles;
\ No newline at end of file
@@ -0,0 +1,32 @@
+-- Roles map to permission sets. A user holds the role named by their token
+-- (limited to the token's courses for course-scoped roles) plus any roles
+-- assigned here.
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    courseScoped BOOLEAN NOT NULL DEFAULT false,
+    builtIn BOOLEAN NOT NULL DEFAULT false, -- built-in roles cannot be deleted
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updatedAt TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE roleAssignments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    userId VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    courseIds TEXT[] NOT NULL DEFAULT '{}', -- empty for all courses
+    createdBy VARCHAR(255) NOT NULL,
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (userId, role)
+);
+
+-- The built-in roles keep the access the three token roles had before
+INSERT INTO roles (name, description, permissions, courseScoped, builtIn) VALUES
+    ('student', 'Opens tickets and follows their own', '{}', false, true),
+    ('instructor', 'Works the tickets of their courses and the tickets assigned to them',
+        '{tickets:read,tickets:comment,tickets:internal,tickets:update,tickets:assign,tickets:resolve,watchers:manage,macros:use,slack:reply}', true, true),
+    ('admin', 'Full access',
+        '{tickets:read,tickets:comment,tickets:internal,tickets:update,tickets:assign,tickets:resolve,tickets:delete,watchers:manage,macros:use,macros:share,catalog:manage,reports:read,access:manage,slack:reply}', false, true),
+    ('service', 'Service API clients acting as themselves; routes are further limited by scopes',
+        '{tickets:read,tickets:comment}', false, true);
\ No newline at end of file
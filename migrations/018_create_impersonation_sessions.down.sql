@@ -0,0 +1,3 @@
+UPDATE roles SET permissions = array_remove(permissions, 'users:impersonate');
+DROP TABLE IF EXISTS impersonatedRequests;
+DROP TABLE IF EXISTS impersonationSessions;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- Read-only "view as student" sessions started by admins.
+CREATE TABLE impersonationSessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    adminId VARCHAR(255) NOT NULL,
+    userId VARCHAR(255) NOT NULL, -- the user being viewed as
+    reason TEXT NOT NULL,
+    expiresAt TIMESTAMP WITH TIME ZONE NOT NULL,
+    endedAt TIMESTAMP WITH TIME ZONE, -- set when ended before expiring
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_impersonation_sessions_created_at ON impersonationSessions(createdAt DESC);
+
+-- Every request made within a session, with both identities.
+CREATE TABLE impersonatedRequests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sessionId UUID NOT NULL REFERENCES impersonationSessions(id) ON DELETE CASCADE,
+    adminId VARCHAR(255) NOT NULL,
+    userId VARCHAR(255) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    status INTEGER NOT NULL,
+    requestId VARCHAR(128),
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_impersonated_requests_session_id ON impersonatedRequests(sessionId, createdAt);
+
+UPDATE roles SET permissions = array_append(permissions, 'users:impersonate') WHERE name = 'admin';
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- Token buckets shared by every instance when RATE_LIMIT_STORE=postgres.
+CREATE TABLE rateLimitBuckets (
+    key VARCHAR(512) PRIMARY KEY, -- rule, user or IP, e.g. "POST /api/v1/tickets|user|<id>"
+    tokens DOUBLE PRECISION NOT NULL,
+    updatedAt TIMESTAMP WITH TIME ZONE NOT NULL,
+    fullAt TIMESTAMP WITH TIME ZONE NOT NULL -- when the bucket has refilled and can be dropped
+);
+
+CREATE INDEX idx_rate_limit_buckets_full_at ON rateLimitBuckets(fullAt);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS idempotencyKeys;
\ No newline at end of file
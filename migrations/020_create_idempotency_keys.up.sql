@@ -0,0 +1,14 @@
+-- Responses to requests sent with an Idempotency-Key, replayed on retries.
+CREATE TABLE idempotencyKeys (
+    userId VARCHAR(255) NOT NULL, -- user, or API client and user it acts for
+    key VARCHAR(255) NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL, -- hash of method, path and body
+    status INTEGER, -- NULL while the first request is in flight
+    contentType VARCHAR(255),
+    body BYTEA,
+    createdAt TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expiresAt TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (userId, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotencyKeys(expiresAt);
\ No newline at end of file
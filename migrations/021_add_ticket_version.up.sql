@@ -0,0 +1,3 @@
+-- Optimistic concurrency: every change to a ticket bumps its version, which
+-- is also the ticket's ETag.
+ALTER TABLE tickets ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
\ No newline at end of file
@@ -0,0 +1,6 @@
+UPDATE roles SET permissions = array_remove(permissions, 'tickets:restore');
+DROP TABLE IF EXISTS archivedTickets;
+DROP INDEX IF EXISTS idx_tickets_closed_at;
+DROP INDEX IF EXISTS idx_tickets_deleted_at;
+ALTER TABLE ticketComments DROP COLUMN IF EXISTS deletedBy, DROP COLUMN IF EXISTS deletedAt;
+ALTER TABLE tickets DROP COLUMN IF EXISTS deletedBy, DROP COLUMN IF EXISTS deletedAt;
\ No newline at end of file
@@ -0,0 +1,35 @@
+-- Deleting a ticket or comment only marks it. Every query skips marked rows
+-- and admins can restore them, so history and attachments are kept.
+ALTER TABLE tickets
+    ADD COLUMN deletedAt TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deletedBy VARCHAR(255);
+
+ALTER TABLE ticketComments
+    ADD COLUMN deletedAt TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deletedBy VARCHAR(255);
+
+CREATE INDEX idx_tickets_deleted_at ON tickets(deletedAt) WHERE deletedAt IS NOT NULL;
+CREATE INDEX idx_tickets_closed_at ON tickets(closedAt) WHERE status = 'closed';
+
+-- Closed tickets moved out of tickets once they are old enough, each with a
+-- JSON copy of its comments, attachments and history.
+CREATE TABLE archivedTickets (
+    id UUID PRIMARY KEY,
+    ticketNumber VARCHAR(20) NOT NULL,
+    studentId VARCHAR(255) NOT NULL,
+    instructorId VARCHAR(255),
+    courseId VARCHAR(255),
+    closedAt TIMESTAMP WITH TIME ZONE,
+    ticket JSONB NOT NULL,
+    comments JSONB NOT NULL DEFAULT '[]',
+    attachments JSONB NOT NULL DEFAULT '[]',
+    history JSONB NOT NULL DEFAULT '[]',
+    archivedAt TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_archived_tickets_ticket_number ON archivedTickets(ticketNumber);
+CREATE INDEX idx_archived_tickets_student_id ON archivedTickets(studentId);
+CREATE INDEX idx_archived_tickets_course_id ON archivedTickets(courseId);
+CREATE INDEX idx_archived_tickets_closed_at ON archivedTickets(closedAt DESC);
+
+UPDATE roles SET permissions = array_append(permissions, 'tickets:restore') WHERE name = 'admin';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE idempotencyKeys DROP COLUMN IF EXISTS etag;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Idempotent replays repeat the ETag of the stored response as well as its
+-- body.
+ALTER TABLE idempotencyKeys ADD COLUMN etag VARCHAR(255);
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE impersonationSessions
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS teams,
+    DROP COLUMN IF EXISTS courses;
+
+DROP TABLE IF EXISTS userClaims;
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- The claims from each user's most recent token, so that an impersonation
+-- session sees what the user would: their email, teams and courses.
+CREATE TABLE userClaims (
+    userId VARCHAR(255) PRIMARY KEY,
+    email VARCHAR(255),
+    teams TEXT[] NOT NULL DEFAULT '{}',
+    courses TEXT[] NOT NULL DEFAULT '{}',
+    updatedAt TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Sessions view the user with the claims they had when the session started
+ALTER TABLE impersonationSessions
+    ADD COLUMN email VARCHAR(255),
+    ADD COLUMN teams TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN courses TEXT[] NOT NULL DEFAULT '{}';
\ No newline at end of file
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"community-support-service/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// APIKeyHeader carries a service API client's key.
+	APIKeyHeader = "X-API-Key"
+	// OnBehalfOfHeader names the student an API client acts for, with their
+	// email in OnBehalfOfEmailHeader.
+	OnBehalfOfHeader      = "X-On-Behalf-Of"
+	OnBehalfOfEmailHeader = "X-On-Behalf-Of-Email"
+)
+
+// APIClient is an authenticated service API client.
+type APIClient struct {
+	ID     string
+	Name   string
+	Scopes []string
+}
+
+// APIClientUsage is one request made with an API client's key, for the audit
+// trail. Scope is empty for routes closed to API clients.
+type APIClientUsage struct {
+	ClientID   string
+	Method     string
+	Path       string
+	Scope      string
+	OnBehalfOf string
+	Status     int
+	RequestID  string
+	RemoteAddr string
+	At         time.Time
+}
+
+// APIClientStore looks up API keys and records their use.
+type APIClientStore interface {
+	// AuthenticateKey returns the active client owning key, or nil when the
+	// key is unknown or its client is deactivated.
+	AuthenticateKey(ctx context.Context, key string) (*APIClient, error)
+	RecordUsage(ctx context.Context, usage *APIClientUsage)
+}
+
+// APIKeyRules decide what API clients may call. Routes are keyed by method
+// and path template, e.g. "POST /api/v1/tickets"; routes not listed are
+// closed to API clients whatever their scopes.
+type APIKeyRules struct {
+	RouteScopes     map[string]string
+	DelegableScopes []string // scopes that may act on behalf of a student
+	// DelegatedOnlyScopes may only act on behalf of a student whose email
+	// is given, such as ticket creation, which notifies the student.
+	DelegatedOnlyScopes []string
+}
+
+type APIKeyMiddleware struct {
+	store APIClientStore
+	rules APIKeyRules
+}
+
+func NewAPIKeyMiddleware(store APIClientStore, rules APIKeyRules) *APIKeyMiddleware {
+	return &APIKeyMiddleware{
+		store: store,
+		rules: rules,
+	}
+}
+
+// HasAPIKey reports whether the request authenticates with an API key rather
+// than a JWT.
+func HasAPIKey(r *http.Request) bool {
+	return r.Header.Get(APIKeyHeader) != ""
+}
+
+// ValidateKey authenticates an API client and checks that it holds the scope
+// the route requires. The client acts as itself (role "service") or, with
+// X-On-Behalf-Of on a delegable scope, as the named student. Every request
+// from a known client is audited, including refused ones.
+func (m *APIKeyMiddleware) ValidateKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := m.store.AuthenticateKey(r.Context(), r.Header.Get(APIKeyHeader))
+		if err != nil {
+			fmt.Printf("Failed to authenticate API key: %v\n", err)
+			utils.WriteError(w, http.StatusServiceUnavailable, utils.CodeUnavailable, "Authentication is temporarily unavailable")
+			return
+		}
+		if client == nil {
+			utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "Invalid API key")
+			return
+		}
+
+		scope := m.routeScope(r)
+		onBehalfOf := strings.TrimSpace(r.Header.Get(OnBehalfOfHeader))
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			m.store.RecordUsage(r.Context(), &APIClientUsage{
+				ClientID:   client.ID,
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				Scope:      scope,
+				OnBehalfOf: onBehalfOf,
+				Status:     recorder.status,
+				RequestID:  GetRequestID(r.Context()),
+				RemoteAddr: r.RemoteAddr,
+				At:         time.Now(),
+			})
+		}()
+
+		userCtx, status, message := m.clientUser(client, scope, onBehalfOf, r)
+		if userCtx == nil {
+			code := utils.CodeForbidden
+			if status == http.StatusBadRequest {
+				code = utils.CodeBadRequest
+			}
+			utils.WriteError(recorder, status, code, message)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), UserContextKey, userCtx)
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+	}
+}
+
+// clientUser returns the identity the client acts as on this route, or the
+// status and message to refuse the request with.
+func (m *APIKeyMiddleware) clientUser(client *APIClient, scope, onBehalfOf string, r *http.Request) (*UserContext, int, string) {
+	if scope == "" {
+		return nil, http.StatusForbidden, "This endpoint is not available to API clients"
+	}
+	if !slices.Contains(client.Scopes, scope) {
+		return nil, http.StatusForbidden, fmt.Sprintf("API client lacks the %s scope", scope)
+	}
+
+	delegatedOnly := slices.Contains(m.rules.DelegatedOnlyScopes, scope)
+	if onBehalfOf == "" && delegatedOnly {
+		return nil, http.StatusBadRequest, fmt.Sprintf("The %s scope must act on behalf of a student; send %s and %s", scope, OnBehalfOfHeader, OnBehalfOfEmailHeader)
+	}
+	if onBehalfOf == "" {
+		return &UserContext{
+			UserID:     client.ID,
+			Role:       "service",
+			Name:       client.Name,
+			UserType:   "service",
+			ClientID:   client.ID,
+			ClientName: client.Name,
+		}, 0, ""
+	}
+
+	if !slices.Contains(m.rules.DelegableScopes, scope) {
+		return nil, http.StatusForbidden, fmt.Sprintf("The %s scope cannot act on behalf of a user", scope)
+	}
+	if len(onBehalfOf) > 255 {
+		return nil, http.StatusBadRequest, OnBehalfOfHeader + " is too long"
+	}
+	email := strings.TrimSpace(r.Header.Get(OnBehalfOfEmailHeader))
+	if email == "" && delegatedOnly {
+		return nil, http.StatusBadRequest, OnBehalfOfEmailHeader + " is required for the " + scope + " scope"
+	}
+	if email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return nil, http.StatusBadRequest, OnBehalfOfEmailHeader + " is not a valid email address"
+		}
+	}
+
+	// Delegated requests always act as a student, never as staff.
+	return &UserContext{
+		UserID:     onBehalfOf,
+		Role:       "student",
+		Email:      email,
+		UserType:   "student",
+		ClientID:   client.ID,
+		ClientName: client.Name,
+	}, 0, ""
+}
+
+func (m *APIKeyMiddleware) routeScope(r *http.Request) string {
+	template := routeTemplate(r)
+	if template == "" {
+		return ""
+	}
+	return m.rules.RouteScopes[r.Method+" "+template]
+}
+
+// routeTemplate returns the path template of the route matched for r, or ""
+// outside a route.
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+	return template
+}
+
+// statusRecorder remembers the status code written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
\ No newline at end of file
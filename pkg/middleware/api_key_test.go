@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// staticClientStore knows a single API key and discards usage records.
+type staticClientStore struct {
+	client *APIClient
+}
+
+func (s *staticClientStore) AuthenticateKey(ctx context.Context, key string) (*APIClient, error) {
+	if key != "key-1" {
+		return nil, nil
+	}
+	return s.client, nil
+}
+
+func (s *staticClientStore) RecordUsage(ctx context.Context, usage *APIClientUsage) {}
+
+func TestAPIKeyDelegatedOnlyScopes(t *testing.T) {
+	store := &staticClientStore{client: &APIClient{ID: "client-1", Name: "lms", Scopes: []string{"tickets:create", "comments:write"}}}
+	m := NewAPIKeyMiddleware(store, APIKeyRules{
+		RouteScopes: map[string]string{
+			"POST /api/v1/tickets":               "tickets:create",
+			"POST /api/v1/tickets/{id}/comments": "comments:write",
+		},
+		DelegableScopes:     []string{"tickets:create", "comments:write"},
+		DelegatedOnlyScopes: []string{"tickets:create"},
+	})
+
+	var user *UserContext
+	router := mux.NewRouter()
+	handler := m.ValidateKey(func(w http.ResponseWriter, r *http.Request) {
+		user, _ = GetUserFromContext(r.Context())
+	})
+	router.HandleFunc("/api/v1/tickets", handler).Methods("POST")
+	router.HandleFunc("/api/v1/tickets/{id}/comments", handler).Methods("POST")
+
+	tests := []struct {
+		name       string
+		path       string
+		onBehalfOf string
+		email      string
+		wantStatus int
+		wantUser   string
+	}{
+		{"create on behalf of a student", "/api/v1/tickets", "student-1", "ada@example.com", http.StatusOK, "student-1"},
+		{"create as the client", "/api/v1/tickets", "", "", http.StatusBadRequest, ""},
+		{"create without the student's email", "/api/v1/tickets", "student-1", "", http.StatusBadRequest, ""},
+		{"comment as the client", "/api/v1/tickets/ticket-1/comments", "", "", http.StatusOK, "client-1"},
+		{"comment without the student's email", "/api/v1/tickets/ticket-1/comments", "student-1", "", http.StatusOK, "student-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user = nil
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			req.Header.Set(APIKeyHeader, "key-1")
+			if tt.onBehalfOf != "" {
+				req.Header.Set(OnBehalfOfHeader, tt.onBehalfOf)
+			}
+			if tt.email != "" {
+				req.Header.Set(OnBehalfOfEmailHeader, tt.email)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("responded %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantUser == "" {
+				if user != nil {
+					t.Errorf("handler ran with user %+v", user)
+				}
+				return
+			}
+			if user == nil || user.UserID != tt.wantUser {
+				t.Errorf("user = %+v, want %s", user, tt.wantUser)
+			}
+		})
+	}
+}
@@ -2,10 +2,13 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"community-support-service/pkg/utils"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -21,16 +24,67 @@ type UserContext struct {
 	Email    string `json:"email"`
 	Name     string `json:"name"`
 	FullName string `json:"fullName"`
-	UserType string `json:"userType"`
+	UserType string   `json:"userType"`
+	Teams    []string `json:"teams"`
+	Courses  []string `json:"courses"`
+
+	// Set when an API client authenticated the request, acting as itself or
+	// on behalf of UserID.
+	ClientID   string `json:"clientId,omitempty"`
+	ClientName string `json:"clientName,omitempty"`
+
+	// Set when an admin is viewing the API as UserID through a read-only
+	// impersonation session.
+	ImpersonatorID string `json:"impersonatorId,omitempty"`
+
+	// Grants are attached by LoadGrants once the user is authenticated.
+	Grants []Grant `json:"-"`
+}
+
+// signingMethods are the JWT algorithms accepted. HMAC tokens are verified
+// with the shared secret, all others with the keys of the JWKS key set.
+var signingMethods = []string{
+	"HS256", "HS384", "HS512",
+	"RS256", "RS384", "RS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+var (
+	errTokenRevoked       = errors.New("token has been revoked")
+	errRevocationsUnknown = errors.New("token revocations could not be checked")
+)
+
+// ClaimRules are the checks applied to a token's claims once its signature
+// is verified. Tokens must always carry exp; nbf and iat are checked when
+// present, and iat may not lie in the future.
+type ClaimRules struct {
+	Issuer      string            // required iss claim, if set
+	Audience    string            // required aud claim entry, if set
+	Leeway      time.Duration     // clock skew allowed for exp, nbf and iat
+	Revocations RevocationChecker // optional revocation list
+}
+
+// RevocationChecker reports whether a token has been revoked, by its jti or
+// because its user's tokens issued before some time were revoked. issuedAt
+// is zero for tokens without an iat claim.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error)
 }
 
 type JWTMiddleware struct {
 	secretKey []byte
+	keySet    *KeySet
+	rules     ClaimRules
 }
 
-func NewJWTMiddleware(secretKey string) *JWTMiddleware {
+// NewJWTMiddleware verifies HMAC tokens with secretKey and asymmetric tokens
+// with keySet. Either may be empty to turn that kind of token off.
+func NewJWTMiddleware(secretKey string, keySet *KeySet, rules ClaimRules) *JWTMiddleware {
 	return &JWTMiddleware{
 		secretKey: []byte(secretKey),
+		keySet:    keySet,
+		rules:     rules,
 	}
 }
 
@@ -38,22 +92,24 @@ func (m *JWTMiddleware) ValidateToken(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		tokenString := extractTokenFromHeader(r)
 		if tokenString == "" {
-			http.Error(w, "Authorization header required", http.StatusUnauthorized)
+			utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "Authorization header required")
 			return
 		}
 
-		userCtx, err := m.parseToken(tokenString)
-		if err != nil {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+		userCtx, err := m.parseToken(r.Context(), tokenString)
+		switch {
+		case errors.Is(err, errTokenRevoked):
+			utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "Token has been revoked")
+			return
+		case errors.Is(err, errRevocationsUnknown):
+			fmt.Printf("Failed to check token revocation: %v\n", err)
+			utils.WriteError(w, http.StatusServiceUnavailable, utils.CodeUnavailable, "Authentication is temporarily unavailable")
+			return
+		case err != nil:
+			utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "Invalid token")
 			return
 		}
 
-		// Set headers for handlers to use
-		r.Header.Set("X-User-ID", userCtx.UserID)
-		r.Header.Set("X-User-Email", userCtx.Email)
-		r.Header.Set("X-User-Role", userCtx.Role)
-		r.Header.Set("X-User-Name", userCtx.Name)
-
 		ctx := context.WithValue(r.Context(), UserContextKey, userCtx)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
@@ -63,13 +119,7 @@ func (m *JWTMiddleware) OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		tokenString := extractTokenFromHeader(r)
 		if tokenString != "" {
-			if userCtx, err := m.parseToken(tokenString); err == nil {
-				// Set headers for handlers to use
-				r.Header.Set("X-User-ID", userCtx.UserID)
-				r.Header.Set("X-User-Email", userCtx.Email)
-				r.Header.Set("X-User-Role", userCtx.Role)
-				r.Header.Set("X-User-Name", userCtx.Name)
-
+			if userCtx, err := m.parseToken(r.Context(), tokenString); err == nil {
 				ctx := context.WithValue(r.Context(), UserContextKey, userCtx)
 				r = r.WithContext(ctx)
 			}
@@ -78,13 +128,8 @@ func (m *JWTMiddleware) OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-func (m *JWTMiddleware) parseToken(tokenString string) (*UserContext, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return m.secretKey, nil
-	})
+func (m *JWTMiddleware) parseToken(ctx context.Context, tokenString string) (*UserContext, error) {
+	token, err := jwt.Parse(tokenString, m.verificationKey, m.parserOptions()...)
 
 	if err != nil {
 		return nil, err
@@ -115,18 +160,10 @@ func (m *JWTMiddleware) parseToken(tokenString string) (*UserContext, error) {
 		if fullName, ok := userObj["fullName"].(string); ok {
 			userCtx.FullName = fullName
 		}
-		if userType, ok := userObj["userType"].(string); ok {
-			userCtx.UserType = userType
-			// Map userType to role for backwards compatibility
-			switch userType {
-			case "admin":
-				userCtx.Role = "admin"
-			case "instructor":
-				userCtx.Role = "instructor"
-			default:
-				userCtx.Role = "student"
-			}
-		}
+		userCtx.Teams = stringSliceClaim(userObj["teams"])
+		userCtx.Courses = stringSliceClaim(userObj["courses"])
+		userCtx.UserType, _ = userObj["userType"].(string)
+		userCtx.Role = roleForUserType(userCtx.UserType)
 	} else {
 		// Fallback: try to extract from top-level claims
 		if userID, ok := claims["userId"].(string); ok {
@@ -135,22 +172,112 @@ func (m *JWTMiddleware) parseToken(tokenString string) (*UserContext, error) {
 			userCtx.UserID = sub
 		}
 		
-		if role, ok := claims["role"].(string); ok {
-			userCtx.Role = role
-		}
+		role, _ := claims["role"].(string)
+		userCtx.Role = roleForUserType(role)
 		
 		if email, ok := claims["email"].(string); ok {
 			userCtx.Email = email
 		}
+
+		userCtx.Teams = stringSliceClaim(claims["teams"])
+		userCtx.Courses = stringSliceClaim(claims["courses"])
 	}
 
 	if userCtx.UserID == "" {
 		return nil, fmt.Errorf("user ID not found in token")
 	}
 
+	if err := m.checkRevoked(ctx, claims, userCtx.UserID); err != nil {
+		return nil, err
+	}
+
 	return userCtx, nil
 }
 
+func (m *JWTMiddleware) parserOptions() []jwt.ParserOption {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithLeeway(m.rules.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if m.rules.Issuer != "" {
+		options = append(options, jwt.WithIssuer(m.rules.Issuer))
+	}
+	if m.rules.Audience != "" {
+		options = append(options, jwt.WithAudience(m.rules.Audience))
+	}
+	return options
+}
+
+// checkRevoked consults the revocation list, if one is configured.
+func (m *JWTMiddleware) checkRevoked(ctx context.Context, claims jwt.MapClaims, userID string) error {
+	if m.rules.Revocations == nil {
+		return nil
+	}
+
+	jti, _ := claims["jti"].(string)
+	var issuedAt time.Time
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		issuedAt = iat.Time
+	}
+
+	revoked, err := m.rules.Revocations.IsRevoked(ctx, jti, userID, issuedAt)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errRevocationsUnknown, err)
+	}
+	if revoked {
+		return errTokenRevoked
+	}
+	return nil
+}
+
+// verificationKey picks the key for a token by its signing method: the shared
+// secret for HMAC, or the key set's key for the token's kid.
+func (m *JWTMiddleware) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if len(m.secretKey) == 0 {
+			return nil, fmt.Errorf("HMAC tokens are not accepted")
+		}
+		return m.secretKey, nil
+	}
+
+	if m.keySet == nil {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return m.keySet.verificationKeys(token)
+}
+
+// roleForUserType names the role a token's userType or role claim holds. Only
+// student, instructor and admin can be claimed; anything else, including
+// "service", which is reserved for API clients, falls back to student. Other
+// roles are only held through assignments.
+func roleForUserType(userType string) string {
+	switch userType {
+	case "instructor", "admin":
+		return userType
+	default:
+		return "student"
+	}
+}
+
+// stringSliceClaim converts a JSON array claim into a string slice, skipping
+// non-string entries.
+func stringSliceClaim(claim interface{}) []string {
+	items, ok := claim.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if value, ok := item.(string); ok && value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func extractTokenFromHeader(r *http.Request) string {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -170,29 +297,26 @@ func GetUserFromContext(ctx context.Context) (*UserContext, bool) {
 	return user, ok
 }
 
-func RequireRole(allowedRoles ...string) func(http.HandlerFunc) http.HandlerFunc {
+// RequirePermission admits users holding at least one of the permissions
+// through any of their grants. Whether a grant covers a particular ticket is
+// left to the handler.
+func RequirePermission(permissions ...string) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			user, ok := GetUserFromContext(r.Context())
 			if !ok {
-				http.Error(w, "User context not found", http.StatusUnauthorized)
+				utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User context not found")
 				return
 			}
 
-			roleAllowed := false
-			for _, role := range allowedRoles {
-				if user.Role == role {
-					roleAllowed = true
-					break
+			for _, permission := range permissions {
+				if user.HasPermission(permission) {
+					next.ServeHTTP(w, r)
+					return
 				}
 			}
 
-			if !roleAllowed {
-				http.Error(w, "Insufficient permissions", http.StatusForbidden)
-				return
-			}
-
-			next.ServeHTTP(w, r)
+			utils.WriteError(w, http.StatusForbidden, utils.CodeForbidden, "Insufficient permissions")
 		}
 	}
 }
\ No newline at end of file
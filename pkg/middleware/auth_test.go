@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+// authenticate runs a token signed with testSecret through ValidateToken and
+// returns the response status and the user the handler saw, if any.
+func authenticate(t *testing.T, claims jwt.MapClaims) (int, *UserContext) {
+	t.Helper()
+
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	var user *UserContext
+	handler := NewJWTMiddleware(testSecret, nil, ClaimRules{}).ValidateToken(func(w http.ResponseWriter, r *http.Request) {
+		user, _ = GetUserFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tickets", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec.Code, user
+}
+
+func TestParseTokenNestedUserClaim(t *testing.T) {
+	status, user := authenticate(t, jwt.MapClaims{
+		"sub":  "ignored-subject",
+		"role": "admin",
+		"user": map[string]interface{}{
+			"id":       "user-1",
+			"email":    "ada@example.com",
+			"name":     "ada",
+			"fullName": "Ada Lovelace",
+			"userType": "instructor",
+			"teams":    []interface{}{"team-1", 42, "team-2"},
+			"courses":  []interface{}{"course-1"},
+		},
+	})
+	if status != http.StatusOK || user == nil {
+		t.Fatalf("ValidateToken responded %d, want 200 with a user", status)
+	}
+
+	want := &UserContext{
+		UserID:   "user-1",
+		Role:     "instructor",
+		Email:    "ada@example.com",
+		Name:     "ada",
+		FullName: "Ada Lovelace",
+		UserType: "instructor",
+		Teams:    []string{"team-1", "team-2"},
+		Courses:  []string{"course-1"},
+	}
+	if !reflect.DeepEqual(user, want) {
+		t.Errorf("user = %+v, want %+v", user, want)
+	}
+}
+
+func TestParseTokenFlatClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   *UserContext
+	}{
+		{
+			name: "userId",
+			claims: jwt.MapClaims{
+				"userId":  "user-1",
+				"sub":     "subject-1",
+				"role":    "instructor",
+				"email":   "ada@example.com",
+				"teams":   []interface{}{"team-1"},
+				"courses": []interface{}{"course-1", "course-2"},
+			},
+			want: &UserContext{
+				UserID:  "user-1",
+				Role:    "instructor",
+				Email:   "ada@example.com",
+				Teams:   []string{"team-1"},
+				Courses: []string{"course-1", "course-2"},
+			},
+		},
+		{
+			name:   "sub",
+			claims: jwt.MapClaims{"sub": "subject-1", "role": "student"},
+			want:   &UserContext{UserID: "subject-1", Role: "student"},
+		},
+		{
+			// A user claim that is not an object is ignored
+			name:   "malformed user claim",
+			claims: jwt.MapClaims{"user": "user-1", "sub": "subject-1"},
+			want:   &UserContext{UserID: "subject-1", Role: "student"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, user := authenticate(t, tt.claims)
+			if status != http.StatusOK || user == nil {
+				t.Fatalf("ValidateToken responded %d, want 200 with a user", status)
+			}
+			if !reflect.DeepEqual(user, tt.want) {
+				t.Errorf("user = %+v, want %+v", user, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTokenRequiresUserID(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{"nested user without id", jwt.MapClaims{"sub": "subject-1", "user": map[string]interface{}{"email": "ada@example.com"}}},
+		{"flat claims without userId or sub", jwt.MapClaims{"email": "ada@example.com", "role": "student"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, user := authenticate(t, tt.claims)
+			if status != http.StatusUnauthorized {
+				t.Errorf("ValidateToken responded %d, want 401", status)
+			}
+			if user != nil {
+				t.Errorf("handler ran with user %+v", user)
+			}
+		})
+	}
+}
+
+func TestParseTokenRoles(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   string
+	}{
+		{"nested student", jwt.MapClaims{"user": map[string]interface{}{"id": "user-1", "userType": "student"}}, "student"},
+		{"nested instructor", jwt.MapClaims{"user": map[string]interface{}{"id": "user-1", "userType": "instructor"}}, "instructor"},
+		{"nested admin", jwt.MapClaims{"user": map[string]interface{}{"id": "user-1", "userType": "admin"}}, "admin"},
+		{"nested without userType", jwt.MapClaims{"user": map[string]interface{}{"id": "user-1"}}, "student"},
+		{"nested service", jwt.MapClaims{"user": map[string]interface{}{"id": "user-1", "userType": "service"}}, "student"},
+		{"nested custom role", jwt.MapClaims{"user": map[string]interface{}{"id": "user-1", "userType": "ta"}}, "student"},
+		{"flat instructor", jwt.MapClaims{"sub": "user-1", "role": "instructor"}, "instructor"},
+		{"flat without role", jwt.MapClaims{"sub": "user-1"}, "student"},
+		{"flat service", jwt.MapClaims{"sub": "user-1", "role": "service"}, "student"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, user := authenticate(t, tt.claims)
+			if status != http.StatusOK || user == nil {
+				t.Fatalf("ValidateToken responded %d, want 200 with a user", status)
+			}
+			if user.Role != tt.want {
+				t.Errorf("Role = %q, want %q", user.Role, tt.want)
+			}
+		})
+	}
+}
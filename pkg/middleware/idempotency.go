@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"community-support-service/pkg/utils"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a request it may
+	// retry.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader marks a response replayed for a repeated key.
+	IdempotentReplayHeader = "Idempotent-Replayed"
+)
+
+var (
+	// ErrIdempotencyInFlight means the first request with the key has not
+	// completed yet.
+	ErrIdempotencyInFlight = errors.New("a request with this idempotency key is in progress")
+	// ErrIdempotencyMismatch means the key was used for a different request.
+	ErrIdempotencyMismatch = errors.New("idempotency key was used for a different request")
+)
+
+// IdempotentResponse is the stored response to a request with an
+// Idempotency-Key.
+type IdempotentResponse struct {
+	Status      int
+	ContentType string
+	ETag        string
+	Body        []byte
+}
+
+// IdempotencyStore keeps the responses to requests sent with an
+// Idempotency-Key, per owner and key.
+type IdempotencyStore interface {
+	// Begin claims key for the request with the given fingerprint and
+	// returns nil, or returns the response of the completed request that
+	// claimed it. It fails with ErrIdempotencyInFlight or
+	// ErrIdempotencyMismatch when the key is taken otherwise.
+	Begin(ctx context.Context, owner, key, fingerprint string) (*IdempotentResponse, error)
+	// Finish stores the response of a claimed key, or releases the key when
+	// response is nil so that the request can be retried.
+	Finish(ctx context.Context, owner, key, fingerprint string, response *IdempotentResponse)
+}
+
+// Idempotent lets clients retry a request safely by sending the same
+// Idempotency-Key: the first response is stored and replayed to repeats of
+// the request, and a repeat arriving while the first is in flight gets 409.
+// Server errors are not stored, so those requests can be retried. Requests
+// without the header are passed through. It must run after authentication;
+// keys belong to the authenticated user.
+func Idempotent(store IdempotencyStore) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > 255 || strings.TrimSpace(key) != key {
+				utils.WriteError(w, http.StatusBadRequest, utils.CodeBadRequest, IdempotencyKeyHeader+" must be at most 255 characters without surrounding spaces")
+				return
+			}
+
+			user, ok := GetUserFromContext(r.Context())
+			if !ok {
+				utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User context not found")
+				return
+			}
+			// Clients acting for the same user keep separate keys
+			owner := user.UserID
+			if user.ClientID != "" {
+				owner = user.ClientID + "|" + user.UserID
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				utils.WriteError(w, http.StatusBadRequest, utils.CodeInvalidBody, "Invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := requestFingerprint(r, body)
+
+			stored, err := store.Begin(r.Context(), owner, key, fingerprint)
+			switch {
+			case errors.Is(err, ErrIdempotencyInFlight):
+				utils.WriteError(w, http.StatusConflict, utils.CodeIdempotencyInFlight, "A request with this Idempotency-Key is still in progress")
+				return
+			case errors.Is(err, ErrIdempotencyMismatch):
+				utils.WriteError(w, http.StatusUnprocessableEntity, utils.CodeIdempotencyMismatch, "This Idempotency-Key was already used for a different request")
+				return
+			case err != nil:
+				fmt.Printf("Failed to check idempotency key: %v\n", err)
+				utils.WriteError(w, http.StatusServiceUnavailable, utils.CodeUnavailable, "Idempotency keys are temporarily unavailable")
+				return
+			}
+			if stored != nil {
+				w.Header().Set(IdempotentReplayHeader, "true")
+				if stored.ContentType != "" {
+					w.Header().Set("Content-Type", stored.ContentType)
+				}
+				if stored.ETag != "" {
+					w.Header().Set("ETag", stored.ETag)
+				}
+				w.WriteHeader(stored.Status)
+				w.Write(stored.Body)
+				return
+			}
+
+			// The response is stored even when the client has gone away,
+			// which is when it is most likely to retry
+			ctx := context.WithoutCancel(r.Context())
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			var response *IdempotentResponse
+			defer func() {
+				store.Finish(ctx, owner, key, fingerprint, response)
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			if recorder.status < http.StatusInternalServerError {
+				response = &IdempotentResponse{
+					Status:      recorder.status,
+					ContentType: recorder.Header().Get("Content-Type"),
+					ETag:        recorder.Header().Get("ETag"),
+					Body:        recorder.body.Bytes(),
+				}
+			}
+		}
+	}
+}
+
+// requestFingerprint identifies a request by method, path and body, so a key
+// reused for another request is detected.
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.Path)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder passes a response through while keeping a copy.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
\ No newline at end of file
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// memoryIdempotencyStore keeps completed responses in a map and has no
+// requests in flight.
+type memoryIdempotencyStore struct {
+	responses map[string]*IdempotentResponse
+}
+
+func (s *memoryIdempotencyStore) Begin(ctx context.Context, owner, key, fingerprint string) (*IdempotentResponse, error) {
+	return s.responses[owner+"|"+key+"|"+fingerprint], nil
+}
+
+func (s *memoryIdempotencyStore) Finish(ctx context.Context, owner, key, fingerprint string, response *IdempotentResponse) {
+	if response != nil {
+		s.responses[owner+"|"+key+"|"+fingerprint] = response
+	}
+}
+
+func TestIdempotentReplaysETag(t *testing.T) {
+	store := &memoryIdempotencyStore{responses: make(map[string]*IdempotentResponse)}
+	calls := 0
+	handler := Idempotent(store)(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"1"`)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success":true}`))
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tickets", nil)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		req = req.WithContext(context.WithValue(req.Context(), UserContextKey, &UserContext{UserID: "student-1"}))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	first := request()
+	replay := request()
+
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+	if replay.Header().Get(IdempotentReplayHeader) != "true" {
+		t.Error("second response is not marked as replayed")
+	}
+	if replay.Code != first.Code || replay.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", replay.Code, replay.Body, first.Code, first.Body)
+	}
+	if got := replay.Header().Get("ETag"); got != `"1"` {
+		t.Errorf("replayed ETag = %q, want %q", got, `"1"`)
+	}
+}
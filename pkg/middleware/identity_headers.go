@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// identityHeaderPrefix is the prefix of the headers that once carried the
+// authenticated user to handlers. Identity now travels only in the request
+// context, so these headers are never trusted.
+const identityHeaderPrefix = "X-User-"
+
+// StripIdentityHeaders removes every X-User-* header a client sent, so a
+// forged header cannot reach a handler or a downstream service as if it
+// described the authenticated user.
+func StripIdentityHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name := range r.Header {
+			if strings.HasPrefix(http.CanonicalHeaderKey(name), identityHeaderPrefix) {
+				delete(r.Header, name)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
\ No newline at end of file
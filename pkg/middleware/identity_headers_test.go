@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestStripIdentityHeadersIgnoresForgedHeaders(t *testing.T) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  "student-1",
+		"role": "student",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	var seen http.Header
+	var user *UserContext
+	auth := NewJWTMiddleware(testSecret, nil, ClaimRules{})
+	handler := StripIdentityHeaders(auth.ValidateToken(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.Header.Clone()
+		user, _ = GetUserFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tickets", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-User-ID", "admin-1")
+	req.Header.Set("X-User-Role", "admin")
+	req.Header["x-user-email"] = []string{"admin@example.com"} // not canonicalised
+	req.Header.Set("X-Request-ID", "request-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if user == nil {
+		t.Fatal("handler did not run with an authenticated user")
+	}
+	if user.UserID != "student-1" || user.Role != "student" {
+		t.Errorf("user = %s (%s), want student-1 (student) from the token", user.UserID, user.Role)
+	}
+	for name := range seen {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), identityHeaderPrefix) {
+			t.Errorf("forged header %s reached the handler", name)
+		}
+	}
+	if seen.Get("X-Request-ID") != "request-1" {
+		t.Error("StripIdentityHeaders removed an unrelated header")
+	}
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"community-support-service/pkg/utils"
+)
+
+const (
+	// ImpersonationHeader carries the ID of the admin's impersonation session.
+	ImpersonationHeader = "X-Impersonation-Session"
+	// ImpersonatingHeader names the viewed user on impersonated responses.
+	ImpersonatingHeader = "X-Impersonating"
+)
+
+// ImpersonationSession is an admin's active session viewing the API as
+// UserID, with the email, teams and courses from the user's last token.
+type ImpersonationSession struct {
+	ID      string
+	AdminID string
+	UserID  string
+	Email   string
+	Teams   []string
+	Courses []string
+}
+
+// ImpersonatedRequest is one request made within an impersonation session,
+// for the audit trail.
+type ImpersonatedRequest struct {
+	SessionID string
+	AdminID   string
+	UserID    string
+	Method    string
+	Path      string
+	Status    int
+	RequestID string
+	At        time.Time
+}
+
+// ImpersonationStore looks up impersonation sessions and records their use.
+type ImpersonationStore interface {
+	// ActiveSession returns the session with the given ID, or nil when it is
+	// unknown, has expired or has been ended.
+	ActiveSession(ctx context.Context, id string) (*ImpersonationSession, error)
+	RecordRequest(ctx context.Context, request *ImpersonatedRequest)
+	// RecordClaims remembers the claims of the user's token, for sessions
+	// later started to view the API as them.
+	RecordClaims(ctx context.Context, user *UserContext)
+}
+
+type ImpersonationMiddleware struct {
+	store      ImpersonationStore
+	resolver   GrantResolver
+	permission string
+}
+
+// NewImpersonationMiddleware lets users holding permission view the API as
+// another user through a session from store. The viewed user's grants come
+// from resolver.
+func NewImpersonationMiddleware(store ImpersonationStore, resolver GrantResolver, permission string) *ImpersonationMiddleware {
+	return &ImpersonationMiddleware{
+		store:      store,
+		resolver:   resolver,
+		permission: permission,
+	}
+}
+
+// Impersonate swaps the admin's identity for the viewed student's when the
+// request names an impersonation session, so every visibility rule applies
+// as it would to the student. Only safe methods are allowed, and every
+// request, refused or not, is audited with both identities. Other requests
+// from users with a token record its claims for later sessions. It must run
+// after LoadGrants.
+func (m *ImpersonationMiddleware) Impersonate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sessionID := strings.TrimSpace(r.Header.Get(ImpersonationHeader))
+		if sessionID == "" {
+			if user, ok := GetUserFromContext(r.Context()); ok && user.ClientID == "" {
+				m.store.RecordClaims(r.Context(), user)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		admin, ok := GetUserFromContext(r.Context())
+		if !ok {
+			utils.WriteError(w, http.StatusUnauthorized, utils.CodeUnauthorized, "User context not found")
+			return
+		}
+		if admin.ClientID != "" || !admin.HasPermission(m.permission) {
+			utils.WriteError(w, http.StatusForbidden, utils.CodeForbidden, "Insufficient permissions to impersonate users")
+			return
+		}
+
+		session, err := m.store.ActiveSession(r.Context(), sessionID)
+		if err != nil {
+			fmt.Printf("Failed to load impersonation session: %v\n", err)
+			utils.WriteError(w, http.StatusServiceUnavailable, utils.CodeUnavailable, "Impersonation is temporarily unavailable")
+			return
+		}
+		// Sessions cannot be borrowed by other admins
+		if session == nil || session.AdminID != admin.UserID {
+			utils.WriteError(w, http.StatusForbidden, utils.CodeForbidden, "Impersonation session is invalid or has expired")
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			m.store.RecordRequest(r.Context(), &ImpersonatedRequest{
+				SessionID: session.ID,
+				AdminID:   admin.UserID,
+				UserID:    session.UserID,
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Status:    recorder.status,
+				RequestID: GetRequestID(r.Context()),
+				At:        time.Now(),
+			})
+		}()
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			utils.WriteError(recorder, http.StatusForbidden, utils.CodeForbidden, "Impersonation sessions are read-only")
+			return
+		}
+
+		student := &UserContext{
+			UserID:         session.UserID,
+			Role:           "student",
+			Email:          session.Email,
+			UserType:       "student",
+			Teams:          session.Teams,
+			Courses:        session.Courses,
+			ImpersonatorID: admin.UserID,
+		}
+		grants, err := m.resolver.Grants(r.Context(), student)
+		if err != nil {
+			fmt.Printf("Failed to load user permissions: %v\n", err)
+			utils.WriteError(recorder, http.StatusServiceUnavailable, utils.CodeUnavailable, "Authorization is temporarily unavailable")
+			return
+		}
+		student.Grants = grants
+
+		recorder.Header().Set(ImpersonatingHeader, session.UserID)
+		ctx := context.WithValue(r.Context(), UserContextKey, student)
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+	}
+}
\ No newline at end of file
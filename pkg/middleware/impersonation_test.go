@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// memoryImpersonationStore holds sessions by ID and remembers the claims it
+// was given.
+type memoryImpersonationStore struct {
+	sessions map[string]*ImpersonationSession
+	claims   map[string]*UserContext
+}
+
+func (s *memoryImpersonationStore) ActiveSession(ctx context.Context, id string) (*ImpersonationSession, error) {
+	return s.sessions[id], nil
+}
+
+func (s *memoryImpersonationStore) RecordRequest(ctx context.Context, request *ImpersonatedRequest) {}
+
+func (s *memoryImpersonationStore) RecordClaims(ctx context.Context, user *UserContext) {
+	s.claims[user.UserID] = user
+}
+
+// noGrants resolves every user to no grants.
+type noGrants struct{}
+
+func (noGrants) Grants(ctx context.Context, user *UserContext) ([]Grant, error) {
+	return nil, nil
+}
+
+func TestImpersonateUsesStudentClaims(t *testing.T) {
+	store := &memoryImpersonationStore{
+		sessions: map[string]*ImpersonationSession{
+			"session-1": {
+				ID:      "session-1",
+				AdminID: "admin-1",
+				UserID:  "student-1",
+				Email:   "ada@example.com",
+				Teams:   []string{"team-1"},
+				Courses: []string{"course-1"},
+			},
+		},
+		claims: make(map[string]*UserContext),
+	}
+	admin := &UserContext{
+		UserID: "admin-1",
+		Role:   "admin",
+		Email:  "admin@example.com",
+		Teams:  []string{"staff"},
+		Grants: []Grant{{Role: "admin", Permissions: []string{"users:impersonate"}, AllCourses: true}},
+	}
+
+	var user *UserContext
+	handler := NewImpersonationMiddleware(store, noGrants{}, "users:impersonate").Impersonate(func(w http.ResponseWriter, r *http.Request) {
+		user, _ = GetUserFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/views", nil)
+	req.Header.Set(ImpersonationHeader, "session-1")
+	req = req.WithContext(context.WithValue(req.Context(), UserContextKey, admin))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusOK || user == nil {
+		t.Fatalf("Impersonate responded %d, want 200 with a user", rec.Code)
+	}
+	want := &UserContext{
+		UserID:         "student-1",
+		Role:           "student",
+		Email:          "ada@example.com",
+		UserType:       "student",
+		Teams:          []string{"team-1"},
+		Courses:        []string{"course-1"},
+		ImpersonatorID: "admin-1",
+	}
+	if !reflect.DeepEqual(user, want) {
+		t.Errorf("user = %+v, want %+v", user, want)
+	}
+	// The admin's own claims are not recorded while impersonating
+	if len(store.claims) != 0 {
+		t.Errorf("recorded claims for %v during an impersonated request", store.claims)
+	}
+}
+
+func TestImpersonateRecordsTokenClaims(t *testing.T) {
+	store := &memoryImpersonationStore{claims: make(map[string]*UserContext)}
+	handler := NewImpersonationMiddleware(store, noGrants{}, "users:impersonate").Impersonate(func(w http.ResponseWriter, r *http.Request) {})
+
+	request := func(user *UserContext) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tickets", nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserContextKey, user))
+		handler(httptest.NewRecorder(), req)
+	}
+	request(&UserContext{UserID: "student-1", Email: "ada@example.com", Courses: []string{"course-1"}})
+	request(&UserContext{UserID: "client-1", ClientID: "client-1"})
+
+	if got := store.claims["student-1"]; got == nil || got.Email != "ada@example.com" {
+		t.Errorf("claims for student-1 = %+v, want the token's", got)
+	}
+	if _, ok := store.claims["client-1"]; ok {
+		t.Error("recorded claims for an API client")
+	}
+}
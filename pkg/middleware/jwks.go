@@ -0,0 +1,319 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// minKeySetReload bounds how often an unknown kid may trigger a reload, so
+	// tokens with made-up key IDs cannot hammer the JWKS endpoint.
+	minKeySetReload = time.Minute
+
+	// keySetRetry is the delay before retrying a failed reload. It doubles
+	// with every further failure up to maxKeySetRetry, so an unreachable
+	// endpoint is not fetched on every request.
+	keySetRetry    = 5 * time.Second
+	maxKeySetRetry = 5 * time.Minute
+)
+
+// KeySet holds the public keys of a JWKS document, read from a file or URL.
+// Keys are reloaded once they are older than the refresh interval, and early
+// when a token names a kid the set does not know yet, so keys can be rotated
+// by publishing the new key next to the old one. Concurrent requests share a
+// single reload, and failed reloads are retried with backoff.
+type KeySet struct {
+	file       string
+	url        string
+	refresh    time.Duration
+	httpClient *http.Client
+	reloads    singleflight.Group
+
+	mu          sync.RWMutex
+	keys        []jwk
+	loadedAt    time.Time
+	attemptedAt time.Time // start of the last reload, successful or not
+	failures    int       // reloads failed in a row
+}
+
+// jwk is one usable key from a JWKS document.
+type jwk struct {
+	kid string
+	alg string
+	key interface{}
+}
+
+type jwksDocument struct {
+	Keys []json.RawMessage `json:"keys"`
+}
+
+type jwkFields struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// NewKeySet loads the JWKS document at file or, if file is empty, url. It
+// fails if the document cannot be loaded or holds no usable keys.
+func NewKeySet(file, url string, refresh time.Duration) (*KeySet, error) {
+	if file == "" && url == "" {
+		return nil, fmt.Errorf("a JWKS file or URL is required")
+	}
+	s := &KeySet{
+		file:    file,
+		url:     url,
+		refresh: refresh,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Keys returns the keys that may have signed a token with the given kid and
+// alg. Without a kid, every key usable with alg is returned.
+//
+// A stale set is refreshed in the background while the current keys keep
+// being used; only a token naming an unknown kid waits for the reload.
+func (s *KeySet) Keys(kid, alg string) ([]interface{}, error) {
+	s.mu.RLock()
+	keys := s.match(kid, alg)
+	stale := time.Since(s.loadedAt) > s.refresh
+	unknownKid := len(keys) == 0 && kid != "" && time.Since(s.attemptedAt) > minKeySetReload
+	due := !time.Now().Before(s.nextAttempt())
+	s.mu.RUnlock()
+
+	switch {
+	case !due:
+	case unknownKid:
+		<-s.reloads.DoChan("reload", s.sharedReload)
+		s.mu.RLock()
+		keys = s.match(kid, alg)
+		s.mu.RUnlock()
+	case stale:
+		s.reloads.DoChan("reload", s.sharedReload)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no key found for kid %q and alg %s", kid, alg)
+	}
+	return keys, nil
+}
+
+func (s *KeySet) match(kid, alg string) []interface{} {
+	var keys []interface{}
+	for _, key := range s.keys {
+		if kid != "" && key.kid != kid {
+			continue
+		}
+		if key.alg != "" && key.alg != alg {
+			continue
+		}
+		keys = append(keys, key.key)
+	}
+	return keys
+}
+
+// nextAttempt is the earliest time the next reload may start: right away
+// after a successful reload, and after a growing delay once reloads fail.
+// Callers hold s.mu.
+func (s *KeySet) nextAttempt() time.Time {
+	if s.failures == 0 {
+		return s.attemptedAt
+	}
+	delay := maxKeySetRetry
+	if s.failures < 8 {
+		delay = min(keySetRetry<<(s.failures-1), maxKeySetRetry)
+	}
+	return s.attemptedAt.Add(delay)
+}
+
+// sharedReload is the reload run by the singleflight group. Failures are only
+// logged: verification carries on with the keys already loaded.
+func (s *KeySet) sharedReload() (interface{}, error) {
+	err := s.reload()
+	if err != nil {
+		log.Printf("Failed to reload JWKS: %v", err)
+	}
+	return nil, err
+}
+
+func (s *KeySet) reload() error {
+	started := time.Now()
+	data, err := s.fetch()
+	var keys []jwk
+	if err == nil {
+		keys, err = parseJWKS(data)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attemptedAt = started
+	if err != nil {
+		s.failures++
+		return err
+	}
+	s.keys = keys
+	s.loadedAt = time.Now()
+	s.failures = 0
+	return nil
+}
+
+func (s *KeySet) fetch() ([]byte, error) {
+	if s.file != "" {
+		return os.ReadFile(s.file)
+	}
+
+	resp, err := s.httpClient.Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// parseJWKS returns the RSA, EC and Ed25519 signing keys of a JWKS document.
+// Encryption keys and key types it does not support are skipped.
+func parseJWKS(data []byte) ([]jwk, error) {
+	var doc jwksDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JWKS document: %w", err)
+	}
+
+	var keys []jwk
+	for _, raw := range doc.Keys {
+		var fields jwkFields
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("invalid JWK: %w", err)
+		}
+		if fields.Use != "" && fields.Use != "sig" {
+			continue
+		}
+
+		key, err := fields.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWK %q: %w", fields.Kid, err)
+		}
+		if key == nil {
+			continue
+		}
+		keys = append(keys, jwk{kid: fields.Kid, alg: fields.Alg, key: key})
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS document has no usable signing keys")
+	}
+	return keys, nil
+}
+
+// publicKey decodes the key, returning nil for unsupported key types.
+func (f jwkFields) publicKey() (interface{}, error) {
+	switch f.Kty {
+	case "RSA":
+		n, err := decodeBigInt(f.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(f.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch f.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil
+		}
+		x, err := decodeBigInt(f.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(f.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", f.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if f.Crv != "Ed25519" {
+			return nil, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(f.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("missing key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// verificationKeys returns the keys for a token's kid header as a key set, so
+// that during rotation a token without a kid is tried against every key.
+func (s *KeySet) verificationKeys(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	keys, err := s.Keys(kid, token.Method.Alg())
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 1 {
+		return keys[0], nil
+	}
+
+	set := jwt.VerificationKeySet{}
+	for _, key := range keys {
+		set.Keys = append(set.Keys, key)
+	}
+	return set, nil
+}
\ No newline at end of file
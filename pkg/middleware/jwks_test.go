@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jwksServer serves a JWKS document with one RSA key, or 503 while failing
+// is set, and counts the requests it receives.
+type jwksServer struct {
+	*httptest.Server
+	hits    atomic.Int32
+	failing atomic.Bool
+}
+
+func newJWKSServer(t *testing.T, kid string, delay time.Duration) *jwksServer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	document, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode JWKS: %v", err)
+	}
+
+	server := &jwksServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.hits.Add(1)
+		time.Sleep(delay)
+		if server.failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(document)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestKeySetCoalescesReloadsForUnknownKid(t *testing.T) {
+	server := newJWKSServer(t, "current", 50*time.Millisecond)
+	keySet, err := NewKeySet("", server.URL, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet returned error: %v", err)
+	}
+
+	server.failing.Store(true)
+	keySet.mu.Lock()
+	keySet.attemptedAt = time.Now().Add(-2 * minKeySetReload)
+	keySet.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := keySet.Keys("rotated", "RS256"); err == nil {
+				t.Error("Keys returned a key for an unknown kid")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if hits := server.hits.Load(); hits != 2 {
+		t.Errorf("JWKS endpoint was fetched %d times, want 2 (initial load and one shared reload)", hits)
+	}
+
+	// The failed reload backs off, so further requests do not refetch
+	keySet.Keys("rotated", "RS256")
+	if hits := server.hits.Load(); hits != 2 {
+		t.Errorf("JWKS endpoint was fetched %d times during backoff, want 2", hits)
+	}
+}
+
+func TestKeySetRefreshesStaleKeysInBackground(t *testing.T) {
+	server := newJWKSServer(t, "current", 0)
+	keySet, err := NewKeySet("", server.URL, time.Minute)
+	if err != nil {
+		t.Fatalf("NewKeySet returned error: %v", err)
+	}
+
+	server.failing.Store(true)
+	keySet.mu.Lock()
+	keySet.loadedAt = time.Now().Add(-time.Hour)
+	keySet.mu.Unlock()
+
+	// A stale set keeps verifying with its keys while it is refreshed
+	if keys, err := keySet.Keys("current", "RS256"); err != nil || len(keys) != 1 {
+		t.Fatalf("Keys = %v, %v; want the current key", keys, err)
+	}
+	waitFor(t, func() bool {
+		keySet.mu.RLock()
+		defer keySet.mu.RUnlock()
+		return keySet.failures == 1
+	})
+
+	for i := 0; i < 10; i++ {
+		if _, err := keySet.Keys("current", "RS256"); err != nil {
+			t.Fatalf("Keys returned error during backoff: %v", err)
+		}
+	}
+	if hits := server.hits.Load(); hits != 2 {
+		t.Errorf("JWKS endpoint was fetched %d times during backoff, want 2", hits)
+	}
+
+	// Once the backoff has passed, the next request refreshes the set again
+	server.failing.Store(false)
+	keySet.mu.Lock()
+	keySet.attemptedAt = time.Now().Add(-keySetRetry)
+	keySet.mu.Unlock()
+	keySet.Keys("current", "RS256")
+	waitFor(t, func() bool {
+		keySet.mu.RLock()
+		defer keySet.mu.RUnlock()
+		return keySet.failures == 0 && time.Since(keySet.loadedAt) < time.Minute
+	})
+}
+
+func TestKeySetBackoff(t *testing.T) {
+	attempted := time.Now()
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, keySetRetry},
+		{2, 2 * keySetRetry},
+		{4, 8 * keySetRetry},
+		{7, maxKeySetRetry},
+		{100, maxKeySetRetry},
+	}
+
+	for _, tt := range tests {
+		keySet := &KeySet{attemptedAt: attempted, failures: tt.failures}
+		if got := keySet.nextAttempt().Sub(attempted); got != tt.want {
+			t.Errorf("after %d failures the next attempt waits %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the key set to reload")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
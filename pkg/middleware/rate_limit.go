@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"community-support-service/pkg/utils"
+)
+
+// RateLimit is a token bucket holding up to Requests tokens, refilled evenly
+// over Per. Each request takes a token. A zero limit turns the bucket off.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (l RateLimit) enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+// RateLimitRule limits a route per authenticated user and per client IP.
+type RateLimitRule struct {
+	User RateLimit
+	IP   RateLimit
+}
+
+// RateLimitStore keeps token buckets.
+type RateLimitStore interface {
+	// Take takes a token from the bucket with the given key and returns zero,
+	// or how long to wait for one when the bucket is empty. Stores deal with
+	// their own failures rather than refusing requests.
+	Take(ctx context.Context, key string, limit RateLimit) time.Duration
+	// Refund puts back a token taken from the bucket with the given key.
+	Refund(ctx context.Context, key string, limit RateLimit)
+}
+
+type RateLimiter struct {
+	store      RateLimitStore
+	defaults   RateLimitRule
+	routes     map[string]RateLimitRule
+	trustProxy bool
+}
+
+// NewRateLimiter limits routes keyed by method and path template, e.g.
+// "POST /api/v1/tickets", by their own rule; each such route has its own
+// buckets. All other routes share the default buckets. With trustProxy the
+// client IP is taken from the last X-Forwarded-For entry, which is the one
+// the proxy in front of the service added.
+func NewRateLimiter(store RateLimitStore, defaults RateLimitRule, routes map[string]RateLimitRule, trustProxy bool) *RateLimiter {
+	return &RateLimiter{
+		store:      store,
+		defaults:   defaults,
+		routes:     routes,
+		trustProxy: trustProxy,
+	}
+}
+
+// LimitIP refuses requests with 429 and a Retry-After header once the client
+// IP's bucket for the route is empty. It runs before authentication, so that
+// floods of bad tokens and requests to public routes are limited too.
+func (l *RateLimiter) LimitIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, rule := l.rule(r)
+		if rule.IP.enabled() {
+			if wait := l.store.Take(r.Context(), l.ipKey(name, r), rule.IP); wait > 0 {
+				writeRateLimited(w, wait)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// LimitUser refuses requests with 429 and a Retry-After header once the
+// authenticated user's bucket for the route is empty. It must run after
+// authentication, and after LimitIP, whose token it gives back when it
+// refuses a request, so that a refused request costs neither bucket.
+func (l *RateLimiter) LimitUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, rule := l.rule(r)
+		if user, ok := GetUserFromContext(r.Context()); ok && rule.User.enabled() {
+			if wait := l.store.Take(r.Context(), name+"|user|"+user.UserID, rule.User); wait > 0 {
+				if rule.IP.enabled() {
+					l.store.Refund(r.Context(), l.ipKey(name, r), rule.IP)
+				}
+				writeRateLimited(w, wait)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// rule returns the name of the buckets limiting r and their rule.
+func (l *RateLimiter) rule(r *http.Request) (string, RateLimitRule) {
+	if route := routeTemplate(r); route != "" {
+		if routeRule, ok := l.routes[r.Method+" "+route]; ok {
+			return r.Method + " " + route, routeRule
+		}
+	}
+	return "default", l.defaults
+}
+
+func (l *RateLimiter) ipKey(name string, r *http.Request) string {
+	return name + "|ip|" + l.clientIP(r)
+}
+
+func writeRateLimited(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	utils.WriteError(w, http.StatusTooManyRequests, utils.CodeRateLimited, "Too many requests, retry in "+strconv.Itoa(seconds)+" seconds")
+}
+
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	if l.trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			entries := strings.Split(forwarded, ",")
+			if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
\ No newline at end of file
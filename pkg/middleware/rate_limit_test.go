@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// countingStore is a RateLimitStore holding a fixed number of tokens per key
+// that never refill.
+type countingStore struct {
+	tokens map[string]int
+}
+
+func (s *countingStore) Take(ctx context.Context, key string, limit RateLimit) time.Duration {
+	if _, ok := s.tokens[key]; !ok {
+		s.tokens[key] = limit.Requests
+	}
+	if s.tokens[key] == 0 {
+		return time.Minute
+	}
+	s.tokens[key]--
+	return 0
+}
+
+func (s *countingStore) Refund(ctx context.Context, key string, limit RateLimit) {
+	s.tokens[key]++
+}
+
+func TestRateLimiterRefusalCostsNoTokens(t *testing.T) {
+	store := &countingStore{tokens: make(map[string]int)}
+	rule := RateLimitRule{
+		User: RateLimit{Requests: 5, Per: time.Minute},
+		IP:   RateLimit{Requests: 2, Per: time.Minute},
+	}
+	limiter := NewRateLimiter(store, rule, nil, false)
+	handler := limiter.LimitIP(limiter.LimitUser(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(userID string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tickets", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req = req.WithContext(context.WithValue(req.Context(), UserContextKey, &UserContext{UserID: userID}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// The IP's two tokens are spent, so the rest of the requests are refused
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests} {
+		if status := request("user-1"); status != want {
+			t.Errorf("request %d responded %d, want %d", i+1, status, want)
+		}
+	}
+
+	if got := store.tokens["default|user|user-1"]; got != 3 {
+		t.Errorf("user bucket holds %d tokens, want 3: requests refused by the IP bucket must not cost the user", got)
+	}
+	if got := store.tokens["default|ip|192.0.2.1"]; got != 0 {
+		t.Errorf("IP bucket holds %d tokens, want 0", got)
+	}
+}
+
+func TestRateLimiterUserRefusalRefundsIP(t *testing.T) {
+	store := &countingStore{tokens: make(map[string]int)}
+	rule := RateLimitRule{
+		User: RateLimit{Requests: 1, Per: time.Minute},
+		IP:   RateLimit{Requests: 5, Per: time.Minute},
+	}
+	limiter := NewRateLimiter(store, rule, nil, false)
+	handler := limiter.LimitIP(limiter.LimitUser(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(user *UserContext) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tickets", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		if user != nil {
+			req = req.WithContext(context.WithValue(req.Context(), UserContextKey, user))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	user := &UserContext{UserID: "user-1"}
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests} {
+		if status := request(user); status != want {
+			t.Errorf("request %d responded %d, want %d", i+1, status, want)
+		}
+	}
+	if got := store.tokens["default|ip|192.0.2.1"]; got != 4 {
+		t.Errorf("IP bucket holds %d tokens, want 4: requests refused by the user bucket must not cost the IP", got)
+	}
+
+	// Requests without a user, such as ones with a bad token, still cost the IP
+	request(nil)
+	if got := store.tokens["default|ip|192.0.2.1"]; got != 3 {
+		t.Errorf("IP bucket holds %d tokens after an anonymous request, want 3", got)
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"community-support-service/pkg/utils"
+	"github.com/google/uuid"
+)
+
+const RequestIDContextKey contextKey = "requestId"
+
+// requestIDPattern bounds the client-supplied request IDs that are echoed
+// back, so arbitrary header content never reaches logs or responses.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID tags each request with an ID, reusing a well-formed X-Request-ID
+// from the client or generating one. The ID is returned in the X-Request-ID
+// response header and in every response envelope.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(utils.RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(utils.RequestIDHeader, requestID)
+		ctx := context.WithValue(r.Context(), RequestIDContextKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetRequestID returns the ID assigned to the request by RequestID.
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDContextKey).(string)
+	return requestID
+}
\ No newline at end of file
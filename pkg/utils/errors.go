@@ -0,0 +1,25 @@
+package utils
+
+// ErrorCode is the machine-readable error code in an error response. Clients
+// should branch on the code, never on the message.
+type ErrorCode string
+
+// Generic error codes. Domain-specific codes such as TICKET_NOT_FOUND or
+// INVALID_TRANSITION are assigned where domain errors are mapped to
+// responses.
+const (
+	CodeBadRequest       ErrorCode = "BAD_REQUEST"
+	CodeInvalidBody      ErrorCode = "INVALID_BODY"
+	CodeValidationFailed ErrorCode = "VALIDATION_FAILED"
+	CodeUnauthorized     ErrorCode = "UNAUTHORIZED"
+	CodeForbidden        ErrorCode = "FORBIDDEN"
+	CodeNotFound         ErrorCode = "NOT_FOUND"
+	CodeConflict         ErrorCode = "CONFLICT"
+	CodeRateLimited      ErrorCode = "RATE_LIMITED"
+
+	// Raised for requests repeating an Idempotency-Key
+	CodeIdempotencyInFlight ErrorCode = "IDEMPOTENCY_KEY_IN_USE"
+	CodeIdempotencyMismatch ErrorCode = "IDEMPOTENCY_KEY_MISMATCH"
+	CodeInternal         ErrorCode = "INTERNAL_ERROR"
+	CodeUnavailable      ErrorCode = "SERVICE_UNAVAILABLE"
+)
\ No newline at end of file
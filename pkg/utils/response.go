@@ -5,72 +5,105 @@ import (
 	"net/http"
 )
 
+// RequestIDHeader carries the request ID set by middleware.RequestID. The
+// writers below copy it into every response body.
+const RequestIDHeader = "X-Request-ID"
+
+// APIResponse is the envelope of every JSON response. Successful responses
+// carry Data and an optional Message; failed ones carry Error.
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Message string      `json:"message,omitempty"`
+	Success   bool        `json:"success"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     *APIError   `json:"error,omitempty"`
+	Message   string      `json:"message,omitempty"`
+	RequestID string      `json:"requestId,omitempty"`
+}
+
+// APIError is the error part of the response envelope.
+type APIError struct {
+	Code    ErrorCode    `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError describes why one request field was rejected. Field is the
+// field's JSON name, dotted for nested objects (e.g. "actions.status").
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 type PaginatedResponse struct {
 	Success    bool        `json:"success"`
 	Data       interface{} `json:"data"`
 	Pagination Pagination  `json:"pagination"`
+	RequestID  string      `json:"requestId,omitempty"`
 }
 
+// Pagination describes a cursor-paginated page. Cursors are opaque tokens to
+// pass back as the "cursor" query parameter; Total is only set when requested.
 type Pagination struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int   `json:"totalPages"`
+	Limit      int    `json:"limit"`
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
 }
 
 func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	
-	response := APIResponse{
+	writeResponse(w, statusCode, APIResponse{
 		Success: statusCode < 400,
 		Data:    data,
-	}
-	
-	json.NewEncoder(w).Encode(response)
+	})
 }
 
-func WriteError(w http.ResponseWriter, statusCode int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	
-	response := APIResponse{
+// WriteError writes an error response with a machine-readable code,
+// optionally listing the individual fields that failed validation.
+func WriteError(w http.ResponseWriter, statusCode int, code ErrorCode, message string, fields ...FieldError) {
+	writeResponse(w, statusCode, APIResponse{
 		Success: false,
-		Error:   message,
-	}
-	
-	json.NewEncoder(w).Encode(response)
+		Error: &APIError{
+			Code:    code,
+			Message: message,
+			Fields:  fields,
+		},
+	})
 }
 
 func WriteSuccess(w http.ResponseWriter, message string, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	
-	response := APIResponse{
+	writeResponse(w, http.StatusOK, APIResponse{
 		Success: true,
 		Message: message,
 		Data:    data,
-	}
-	
-	json.NewEncoder(w).Encode(response)
+	})
+}
+
+// WriteCreated is WriteSuccess for requests that created a resource.
+func WriteCreated(w http.ResponseWriter, message string, data interface{}) {
+	writeResponse(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
 }
 
 func WritePaginated(w http.ResponseWriter, data interface{}, pagination Pagination) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	
+
 	response := PaginatedResponse{
 		Success:    true,
 		Data:       data,
 		Pagination: pagination,
+		RequestID:  w.Header().Get(RequestIDHeader),
 	}
-	
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeResponse(w http.ResponseWriter, statusCode int, response APIResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response.RequestID = w.Header().Get(RequestIDHeader)
 	json.NewEncoder(w).Encode(response)
 }
\ No newline at end of file
@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"community-support-service/pkg/utils"
+)
+
+// MisspelledFields reports the object keys in data that only match a field of
+// v case-insensitively, such as "TicketNumber" for "ticketNumber".
+// encoding/json accepts those spellings, so callers decide whether to reject
+// them or just flag them. Nested objects are checked too; keys that match no
+// field are ignored.
+func MisspelledFields(data []byte, v interface{}) []utils.FieldError {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	var errs []utils.FieldError
+	checkKeys(raw, reflect.TypeOf(v), "", &errs)
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+func checkKeys(raw interface{}, t reflect.Type, prefix string, errs *[]utils.FieldError) {
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+
+	fields := jsonFields(t)
+	byLower := make(map[string]string, len(fields))
+	for name := range fields {
+		byLower[strings.ToLower(name)] = name
+	}
+
+	for key, value := range object {
+		if fieldType, ok := fields[key]; ok {
+			checkKeys(value, fieldType, prefix+key+".", errs)
+			continue
+		}
+		if name, ok := byLower[strings.ToLower(key)]; ok {
+			*errs = append(*errs, utils.FieldError{
+				Field:   prefix + name,
+				Message: fmt.Sprintf("is spelled %q; use %q", key, name),
+			})
+		}
+	}
+}
+
+// jsonFields maps the JSON names of t's exported fields, including those of
+// embedded structs, to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for name, fieldType := range jsonFields(embedded) {
+					fields[name] = fieldType
+				}
+				continue
+			}
+		}
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		fields[fieldName(field)] = field.Type
+	}
+	return fields
+}
\ No newline at end of file
@@ -0,0 +1,207 @@
+// Package validator checks request structs against their `validate` struct
+// tags and reports every invalid field by its JSON name.
+package validator
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"community-support-service/pkg/utils"
+)
+
+// Enum is implemented by string enum types such as models.TicketStatus. A
+// non-empty Enum field is rejected unless Valid reports true, whether or not
+// the field has a validate tag. If the type also has a Values method, the
+// accepted values are listed in the error message.
+type Enum interface {
+	Valid() bool
+}
+
+type enumValues interface {
+	Values() []string
+}
+
+// Validate checks v, a struct or pointer to struct, and returns one error per
+// invalid field in declaration order. Nested structs are checked too, with
+// dotted field names.
+//
+// Supported rules are required, omitempty, min, max, len, email, url and
+// oneof (space-separated values). Rules other than required only apply to
+// non-empty values, so omitempty is accepted but never needed. A string
+// holding only whitespace counts as empty. Unknown rules panic, as they are
+// programming errors.
+func Validate(v interface{}) []utils.FieldError {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []utils.FieldError
+	validateStruct(value, "", &errs)
+	return errs
+}
+
+func validateStruct(value reflect.Value, prefix string, errs *[]utils.FieldError) {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := prefix + fieldName(field)
+		fieldValue := value.Field(i)
+
+		var rules []string
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			rules = strings.Split(tag, ",")
+		}
+
+		if isEmpty(fieldValue) {
+			for _, rule := range rules {
+				if rule == "required" {
+					*errs = append(*errs, utils.FieldError{Field: name, Message: "is required"})
+					break
+				}
+			}
+			continue
+		}
+
+		target := fieldValue
+		for target.Kind() == reflect.Ptr {
+			target = target.Elem()
+		}
+
+		if message, ok := checkEnum(target); !ok {
+			*errs = append(*errs, utils.FieldError{Field: name, Message: message})
+			continue
+		}
+		if message, ok := checkRules(target, rules); !ok {
+			*errs = append(*errs, utils.FieldError{Field: name, Message: message})
+			continue
+		}
+
+		if target.Kind() == reflect.Struct {
+			validateStruct(target, name+".", errs)
+		}
+	}
+}
+
+// fieldName returns the name a field has in request JSON, falling back to
+// the Go field name.
+func fieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+func checkEnum(value reflect.Value) (string, bool) {
+	if !value.CanInterface() {
+		return "", true
+	}
+	enum, ok := value.Interface().(Enum)
+	if !ok || enum.Valid() {
+		return "", true
+	}
+	if values, ok := enum.(enumValues); ok {
+		return "must be one of: " + strings.Join(values.Values(), ", "), false
+	}
+	return "is not a valid value", false
+}
+
+func checkRules(value reflect.Value, rules []string) (string, bool) {
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required", "omitempty":
+		case "min", "max", "len":
+			limit, err := strconv.Atoi(param)
+			if err != nil {
+				panic(fmt.Sprintf("validator: invalid %s parameter %q", name, param))
+			}
+			if message, ok := checkSize(value, name, limit); !ok {
+				return message, false
+			}
+		case "email":
+			address, err := mail.ParseAddress(value.String())
+			if err != nil || address.Address != value.String() {
+				return "must be a valid email address", false
+			}
+		case "url":
+			parsed, err := url.ParseRequestURI(value.String())
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return "must be an http or https URL", false
+			}
+		case "oneof":
+			options := strings.Fields(param)
+			current := fmt.Sprint(value.Interface())
+			found := false
+			for _, option := range options {
+				if option == current {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return "must be one of: " + strings.Join(options, ", "), false
+			}
+		default:
+			panic(fmt.Sprintf("validator: unknown rule %q", rule))
+		}
+	}
+	return "", true
+}
+
+// checkSize applies min, max or len to a string's length in characters, a
+// collection's length or a number's value.
+func checkSize(value reflect.Value, rule string, limit int) (string, bool) {
+	var size float64
+	var unit string
+	switch value.Kind() {
+	case reflect.String:
+		size, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		size, unit = float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = value.Float()
+	default:
+		panic(fmt.Sprintf("validator: %s does not apply to %s", rule, value.Kind()))
+	}
+
+	switch {
+	case rule == "min" && size < float64(limit):
+		return fmt.Sprintf("must be at least %d%s", limit, unit), false
+	case rule == "max" && size > float64(limit):
+		return fmt.Sprintf("must be at most %d%s", limit, unit), false
+	case rule == "len" && size != float64(limit):
+		return fmt.Sprintf("must be exactly %d%s", limit, unit), false
+	}
+	return "", true
+}
\ No newline at end of file